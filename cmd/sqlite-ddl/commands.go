@@ -0,0 +1,683 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/codegen"
+	"github.com/Allam76/Sqlite3CreateTableParser/datagen"
+	"github.com/Allam76/Sqlite3CreateTableParser/dialect"
+	"github.com/Allam76/Sqlite3CreateTableParser/dictionary"
+	"github.com/Allam76/Sqlite3CreateTableParser/erd"
+	"github.com/Allam76/Sqlite3CreateTableParser/graph"
+	"github.com/Allam76/Sqlite3CreateTableParser/lint"
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+func runParse(args []string, stdout io.Writer) (int, error) {
+	flags := newFlagSet("parse", "[file ...]")
+	format := flags.String("format", "json", "output format: json or yaml")
+	if err := flags.Parse(args); err != nil {
+		return exitError, err
+	}
+	inputs, err := readInputs(flags.Args())
+	if err != nil {
+		return exitError, err
+	}
+
+	tables := []*parser.Table{}
+	for _, in := range inputs {
+		schema, err := parseSchema(in.name, in.sql)
+		if err != nil {
+			return exitError, err
+		}
+		tables = append(tables, schema.Tables...)
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(tables)
+	case "yaml":
+		encoder := yaml.NewEncoder(stdout)
+		err = encoder.Encode(tables)
+		if err == nil {
+			err = encoder.Close()
+		}
+	default:
+		return exitError, fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return exitError, err
+	}
+	return exitOK, nil
+}
+
+// formatScript rewrites every CREATE TABLE statement of sql in canonical
+// form, keeping its comments. Other statements, and comments leading a
+// statement, are kept as is.
+func formatScript(name, sql string) (string, error) {
+	var b strings.Builder
+	for i, statement := range parser.SplitStatements(sql) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		schema, err := parseSchema(fmt.Sprintf("%s:%d", name, statement.Line), statement.Text)
+		if err != nil {
+			return "", err
+		}
+		if len(schema.Tables) == 0 {
+			lines := strings.Split(statement.Text, "\n")
+			for len(lines) > 0 && strings.HasPrefix(strings.TrimSpace(lines[0]), "--") {
+				b.WriteString(strings.TrimSpace(lines[0]))
+				b.WriteString("\n")
+				lines = lines[1:]
+			}
+			b.WriteString(strings.Join(lines, "\n"))
+			b.WriteString(";\n")
+			continue
+		}
+		b.WriteString(parser.FormatWithComments(schema.Tables[0], statement.Text))
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+func runFmt(args []string, stdout io.Writer) (int, error) {
+	flags := newFlagSet("fmt", "[file ...]")
+	write := flags.Bool("w", false, "write the result to the file instead of standard output")
+	check := flags.Bool("check", false, "list files that are not formatted and exit with status 1")
+	if err := flags.Parse(args); err != nil {
+		return exitError, err
+	}
+	inputs, err := readInputs(flags.Args())
+	if err != nil {
+		return exitError, err
+	}
+
+	code := exitOK
+	for _, in := range inputs {
+		formatted, err := formatScript(in.name, in.sql)
+		if err != nil {
+			return exitError, err
+		}
+		switch {
+		case *check:
+			if formatted != in.sql {
+				fmt.Fprintln(stdout, in.name)
+				code = exitFindings
+			}
+		case *write && in.name != "<stdin>":
+			if in.database {
+				return exitError, fmt.Errorf("%s: cannot rewrite a database file", in.name)
+			}
+			if formatted != in.sql {
+				if err := os.WriteFile(in.name, []byte(formatted), 0o644); err != nil {
+					return exitError, err
+				}
+			}
+		default:
+			io.WriteString(stdout, formatted)
+		}
+	}
+	return code, nil
+}
+
+func runLint(args []string, stdout io.Writer) (int, error) {
+	flags := newFlagSet("lint", "[file ...]")
+	format := flags.String("format", "text", "output format: text, json or sarif")
+	enable := flags.String("enable", "", "comma-separated rules to run instead of all rules")
+	disable := flags.String("disable", "", "comma-separated rules to skip")
+	severities := flags.String("severity", "", "comma-separated rule=severity overrides")
+	failOn := flags.String("fail-on", "warning", "lowest severity that makes the command fail")
+	list := flags.Bool("rules", false, "list the available rules and exit")
+	if err := flags.Parse(args); err != nil {
+		return exitError, err
+	}
+
+	if *list {
+		for _, rule := range lint.Rules {
+			fmt.Fprintf(stdout, "%-32s %-8s %s\n", rule.Name, rule.Severity, rule.Description)
+		}
+		return exitOK, nil
+	}
+
+	config := lint.Config{
+		Enable:   splitList(*enable),
+		Disable:  splitList(*disable),
+		Severity: map[string]lint.Severity{},
+	}
+	for _, override := range splitList(*severities) {
+		parts := strings.SplitN(override, "=", 2)
+		if len(parts) != 2 {
+			return exitError, fmt.Errorf("invalid severity override %q", override)
+		}
+		severity, err := lint.ParseSeverity(parts[1])
+		if err != nil {
+			return exitError, err
+		}
+		config.Severity[parts[0]] = severity
+	}
+	threshold, err := lint.ParseSeverity(*failOn)
+	if err != nil {
+		return exitError, err
+	}
+	linter, err := lint.New(config)
+	if err != nil {
+		return exitError, err
+	}
+
+	inputs, err := readInputs(flags.Args())
+	if err != nil {
+		return exitError, err
+	}
+	code := exitOK
+	for _, in := range inputs {
+		findings, err := linter.LintSQL(in.sql)
+		if err != nil {
+			return exitError, fmt.Errorf("%s: %v", in.name, err)
+		}
+		switch *format {
+		case "text":
+			err = lint.WriteText(stdout, in.name, findings)
+		case "json":
+			err = lint.WriteJSON(stdout, in.name, findings)
+		case "sarif":
+			err = lint.WriteSARIF(stdout, in.name, findings)
+		default:
+			return exitError, fmt.Errorf("unknown format %q", *format)
+		}
+		if err != nil {
+			return exitError, err
+		}
+		if len(findings) > 0 && lint.MaxSeverity(findings) >= threshold {
+			code = exitFindings
+		}
+	}
+	return code, nil
+}
+
+func runValidate(args []string, stdout io.Writer) (int, error) {
+	flags := newFlagSet("validate", "[file ...]")
+	if err := flags.Parse(args); err != nil {
+		return exitError, err
+	}
+	inputs, err := readInputs(flags.Args())
+	if err != nil {
+		return exitError, err
+	}
+
+	code := exitOK
+	for _, in := range inputs {
+		schema, err := parseSchema(in.name, in.sql)
+		if err != nil {
+			return exitError, err
+		}
+		for _, table := range schema.Tables {
+			for _, diag := range parser.Validate(table) {
+				fmt.Fprintf(stdout, "%s: %s: %s\n", in.name, table.Name, diag.Message)
+				code = exitFindings
+			}
+		}
+	}
+	return code, nil
+}
+
+func runDiff(args []string, stdout io.Writer) (int, error) {
+	flags := newFlagSet("diff", "old new")
+	if err := flags.Parse(args); err != nil {
+		return exitError, err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitError, fmt.Errorf("expected two files or directories")
+	}
+
+	from, err := readSchemaPath(flags.Arg(0))
+	if err != nil {
+		return exitError, err
+	}
+	to, err := readSchemaPath(flags.Arg(1))
+	if err != nil {
+		return exitError, err
+	}
+
+	changes := parser.Diff(from, to)
+	for _, change := range changes {
+		fmt.Fprintln(stdout, change)
+	}
+	if len(changes) > 0 {
+		return exitFindings, nil
+	}
+	return exitOK, nil
+}
+
+// runGraph prints the objects of the schema in an order to create them in,
+// or their dependency graph. Foreign key cycles that deferred foreign keys
+// do not break are reported after the order and make the exit status 1.
+func runGraph(args []string, stdout io.Writer) (int, error) {
+	flags := newFlagSet("graph", "[file ...]")
+	format := flags.String("format", "order", "output format: order, dot or mermaid")
+	if err := flags.Parse(args); err != nil {
+		return exitError, err
+	}
+	inputs, err := readInputs(flags.Args())
+	if err != nil {
+		return exitError, err
+	}
+
+	all := &parser.Schema{}
+	for _, in := range inputs {
+		schema, err := parseSchema(in.name, in.sql)
+		if err != nil {
+			return exitError, err
+		}
+		all.Tables = append(all.Tables, schema.Tables...)
+		all.Indexes = append(all.Indexes, schema.Indexes...)
+		all.Views = append(all.Views, schema.Views...)
+		all.Triggers = append(all.Triggers, schema.Triggers...)
+	}
+	g := graph.New(all)
+
+	var cycles []graph.Cycle
+	for _, cycle := range g.Cycles() {
+		if !cycle.Deferred {
+			cycles = append(cycles, cycle)
+		}
+	}
+	switch *format {
+	case "order":
+		for _, node := range g.Order() {
+			fmt.Fprintln(stdout, node)
+		}
+		for _, cycle := range cycles {
+			fmt.Fprintf(stdout, "warning: %s\n", cycle)
+		}
+	case "dot":
+		err = graph.WriteDOT(stdout, g)
+	case "mermaid":
+		err = graph.WriteMermaid(stdout, g)
+	default:
+		return exitError, fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return exitError, err
+	}
+	if len(cycles) > 0 {
+		return exitFindings, nil
+	}
+	return exitOK, nil
+}
+
+func runDoc(args []string, stdout io.Writer) (int, error) {
+	flags := newFlagSet("doc", "[file ...]")
+	format := flags.String("format", "markdown", "output format: markdown or html")
+	output := flags.String("o", "", "write the Markdown to this file, or the HTML site to this directory")
+	if err := flags.Parse(args); err != nil {
+		return exitError, err
+	}
+	inputs, err := readInputs(flags.Args())
+	if err != nil {
+		return exitError, err
+	}
+
+	var sql strings.Builder
+	for _, in := range inputs {
+		sql.WriteString(in.sql)
+		sql.WriteString(";\n")
+	}
+	d, err := dictionary.FromSQL(sql.String())
+	if err != nil {
+		return exitError, err
+	}
+
+	switch *format {
+	case "markdown":
+		var b bytes.Buffer
+		if err := dictionary.WriteMarkdown(&b, d); err != nil {
+			return exitError, err
+		}
+		if *output != "" {
+			err = os.WriteFile(*output, b.Bytes(), 0o644)
+		} else {
+			_, err = stdout.Write(b.Bytes())
+		}
+	case "html":
+		if *output == "" {
+			return exitError, fmt.Errorf("html output needs a directory given with -o")
+		}
+		err = dictionary.WriteHTML(*output, d)
+	default:
+		return exitError, fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return exitError, err
+	}
+	return exitOK, nil
+}
+
+func runERD(args []string, stdout io.Writer) (int, error) {
+	flags := newFlagSet("erd", "[file ...]")
+	format := flags.String("format", "mermaid", "output format: mermaid, plantuml or dot")
+	include := flags.String("include", "", "comma-separated table name patterns to draw (default all)")
+	exclude := flags.String("exclude", "", "comma-separated table name patterns to leave out")
+	columns := flags.String("columns", "all", "columns to draw: all, keys or none")
+	if err := flags.Parse(args); err != nil {
+		return exitError, err
+	}
+
+	options := erd.Options{Include: splitList(*include), Exclude: splitList(*exclude)}
+	switch *columns {
+	case "all":
+		options.Columns = erd.COLUMNS_ALL
+	case "keys":
+		options.Columns = erd.COLUMNS_KEYS
+	case "none":
+		options.Columns = erd.COLUMNS_NONE
+	default:
+		return exitError, fmt.Errorf("unknown column mode %q", *columns)
+	}
+
+	inputs, err := readInputs(flags.Args())
+	if err != nil {
+		return exitError, err
+	}
+	var tables []*parser.Table
+	for _, in := range inputs {
+		schema, err := parseSchema(in.name, in.sql)
+		if err != nil {
+			return exitError, err
+		}
+		tables = append(tables, schema.Tables...)
+	}
+	diagram, err := erd.New(options, tables...)
+	if err != nil {
+		return exitError, err
+	}
+
+	switch *format {
+	case "mermaid":
+		err = erd.WriteMermaid(stdout, diagram)
+	case "plantuml":
+		err = erd.WritePlantUML(stdout, diagram)
+	case "dot":
+		err = erd.WriteDOT(stdout, diagram)
+	default:
+		return exitError, fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return exitError, err
+	}
+	return exitOK, nil
+}
+
+func runData(args []string, stdout io.Writer) (int, error) {
+	flags := newFlagSet("data", "[file ...]")
+	seed := flags.Int64("seed", 1, "seed of the random choices; the same seed writes the same rows")
+	rows := flags.Int("rows", 10, "number of rows of every table")
+	counts := flags.String("counts", "", "comma-separated table=rows pairs overriding -rows")
+	if err := flags.Parse(args); err != nil {
+		return exitError, err
+	}
+
+	options := datagen.Options{Seed: *seed, Rows: *rows, RowCounts: map[string]int{}}
+	for _, item := range splitList(*counts) {
+		i := strings.LastIndexByte(item, '=')
+		if i < 0 {
+			return exitError, fmt.Errorf("bad row count %q: want table=rows", item)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(item[i+1:]))
+		if err != nil || n < 0 {
+			return exitError, fmt.Errorf("bad row count %q: want table=rows", item)
+		}
+		options.RowCounts[strings.TrimSpace(item[:i])] = n
+	}
+
+	inputs, err := readInputs(flags.Args())
+	if err != nil {
+		return exitError, err
+	}
+	all := &parser.Schema{}
+	for _, in := range inputs {
+		schema, err := parseSchema(in.name, in.sql)
+		if err != nil {
+			return exitError, err
+		}
+		all.Tables = append(all.Tables, schema.Tables...)
+		all.Indexes = append(all.Indexes, schema.Indexes...)
+	}
+	tables, err := datagen.Generate(options, all)
+	if err != nil {
+		return exitError, err
+	}
+	if err := datagen.WriteSQL(stdout, tables); err != nil {
+		return exitError, err
+	}
+	return exitOK, nil
+}
+
+func runGen(args []string, stdout io.Writer) (int, error) {
+	flags := newFlagSet("gen", "[file ...]")
+	lang := flags.String("lang", "go", "language to generate: go, typescript, proto, jsonschema or openapi")
+	pkg := flags.String("package", "models", "package name of the generated Go or proto file")
+	null := flags.String("null", "sql", "nullable column types: sql, pointer or generic")
+	jsonTags := flags.Bool("json", false, "add json tags")
+	crud := flags.Bool("crud", false, "generate Insert, Get, Update and Delete functions")
+	placeholder := flags.String("placeholder", "?", "query placeholder style: ? or $")
+	output := flags.String("o", "", "write the generated code to this file instead of standard output")
+	lockFile := flags.String("lock", "", "proto field number lock file, created if missing and updated after generation")
+	if err := flags.Parse(args); err != nil {
+		return exitError, err
+	}
+
+	options := codegen.GoOptions{Package: *pkg, JSONTags: *jsonTags, CRUD: *crud}
+	switch *null {
+	case "sql":
+		options.Null = codegen.NULL_SQL
+	case "pointer":
+		options.Null = codegen.NULL_POINTER
+	case "generic":
+		options.Null = codegen.NULL_GENERIC
+	default:
+		return exitError, fmt.Errorf("unknown null style %q", *null)
+	}
+	switch *placeholder {
+	case "?":
+		options.Placeholder = codegen.PLACEHOLDER_QUESTION
+	case "$":
+		options.Placeholder = codegen.PLACEHOLDER_DOLLAR
+	default:
+		return exitError, fmt.Errorf("unknown placeholder style %q", *placeholder)
+	}
+
+	inputs, err := readInputs(flags.Args())
+	if err != nil {
+		return exitError, err
+	}
+	var tables []*parser.Table
+	for _, in := range inputs {
+		schema, err := parseSchema(in.name, in.sql)
+		if err != nil {
+			return exitError, err
+		}
+		tables = append(tables, schema.Tables...)
+	}
+
+	var src []byte
+	switch *lang {
+	case "go":
+		src, err = codegen.Go(options, tables...)
+	case "typescript", "ts":
+		src, err = codegen.TypeScript(codegen.TypeScriptOptions{}, tables...)
+	case "proto":
+		src, err = genProto(*pkg, *lockFile, tables)
+	case "jsonschema":
+		src, err = codegen.JSONSchemas(tables...)
+	case "openapi":
+		src, err = codegen.OpenAPI(codegen.OpenAPIOptions{}, tables...)
+	default:
+		return exitError, fmt.Errorf("unknown language %q", *lang)
+	}
+	if err != nil {
+		return exitError, err
+	}
+	if *output != "" {
+		if err := os.WriteFile(*output, src, 0o644); err != nil {
+			return exitError, err
+		}
+		return exitOK, nil
+	}
+	if _, err := stdout.Write(src); err != nil {
+		return exitError, err
+	}
+	return exitOK, nil
+}
+
+// genProto runs codegen.Proto with the lock stored in lockFile, if any, and
+// saves the updated lock.
+func genProto(pkg, lockFile string, tables []*parser.Table) ([]byte, error) {
+	options := codegen.ProtoOptions{Package: pkg}
+	if lockFile == "" {
+		return codegen.Proto(options, tables...)
+	}
+
+	options.Lock = codegen.NewProtoLock()
+	if f, err := os.Open(lockFile); err == nil {
+		options.Lock, err = codegen.ReadProtoLock(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", lockFile, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	src, err := codegen.Proto(options, tables...)
+	if err != nil {
+		return nil, err
+	}
+	var lock bytes.Buffer
+	if err := options.Lock.Write(&lock); err != nil {
+		return nil, err
+	}
+	return src, os.WriteFile(lockFile, lock.Bytes(), 0o644)
+}
+
+func runConvert(args []string, stdout io.Writer) (int, error) {
+	flags := newFlagSet("convert", "[file ...]")
+	to := flags.String("to", "postgres", "target database: postgres or mysql")
+	from := flags.String("from", "", "translate from postgres or mysql to SQLite instead")
+	format := flags.String("format", "sql", "output format: sql, with warnings as comments, or json")
+	strict := flags.Bool("strict", false, "exit with status 1 when the translation is lossy")
+	var mysql dialect.MySQLOptions
+	flags.StringVar(&mysql.Engine, "engine", "", "MySQL storage engine (default InnoDB)")
+	flags.StringVar(&mysql.Charset, "charset", "", "MySQL character set (default utf8mb4)")
+	flags.StringVar(&mysql.Collation, "collation", "", "MySQL table collation (default the binary collation of the character set)")
+	if err := flags.Parse(args); err != nil {
+		return exitError, err
+	}
+	inputs, err := readInputs(flags.Args())
+	if err != nil {
+		return exitError, err
+	}
+
+	var sql string
+	var warnings []dialect.Warning
+	if *from != "" {
+		sql, warnings, err = importInputs(*from, inputs)
+		if err != nil {
+			return exitError, err
+		}
+		*to = "sqlite"
+	}
+	var all parser.Schema
+	for _, in := range inputs {
+		if *from != "" {
+			break
+		}
+		schema, err := parseSchema(in.name, in.sql)
+		if err != nil {
+			return exitError, err
+		}
+		all.Tables = append(all.Tables, schema.Tables...)
+		all.Indexes = append(all.Indexes, schema.Indexes...)
+		all.Views = append(all.Views, schema.Views...)
+		all.Triggers = append(all.Triggers, schema.Triggers...)
+	}
+
+	switch *to {
+	case "sqlite":
+	case "postgres", "postgresql":
+		sql, warnings = dialect.ToPostgresSchema(&all)
+	case "mysql", "mariadb":
+		sql, warnings = dialect.ToMySQLSchema(mysql, &all)
+	default:
+		return exitError, fmt.Errorf("unknown target %q", *to)
+	}
+
+	switch *format {
+	case "sql":
+		// Warnings are written as comments so that the output stays a valid script.
+		for _, warning := range warnings {
+			fmt.Fprintf(stdout, "-- warning: %s\n", warning)
+		}
+		if len(warnings) > 0 {
+			fmt.Fprintln(stdout)
+		}
+		io.WriteString(stdout, sql)
+	case "json":
+		if warnings == nil {
+			warnings = []dialect.Warning{}
+		}
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(map[string]interface{}{"sql": sql, "warnings": warnings}); err != nil {
+			return exitError, err
+		}
+	default:
+		return exitError, fmt.Errorf("unknown format %q", *format)
+	}
+	if *strict && len(warnings) > 0 {
+		return exitFindings, nil
+	}
+	return exitOK, nil
+}
+
+// importInputs translates PostgreSQL or MySQL scripts to SQLite.
+func importInputs(from string, inputs []input) (string, []dialect.Warning, error) {
+	var b strings.Builder
+	var warnings []dialect.Warning
+	for _, in := range inputs {
+		var schema *parser.Schema
+		var more []dialect.Warning
+		var err error
+		switch from {
+		case "postgres", "postgresql":
+			schema, more, err = dialect.FromPostgres(in.sql)
+		case "mysql", "mariadb":
+			schema, more, err = dialect.FromMySQL(in.sql)
+		default:
+			return "", nil, fmt.Errorf("unknown source %q", from)
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("%s: %v", in.name, err)
+		}
+		for _, table := range schema.Tables {
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(parser.Format(table))
+			b.WriteString("\n")
+		}
+		warnings = append(warnings, more...)
+	}
+	return b.String(), warnings, nil
+}
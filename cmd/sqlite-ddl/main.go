@@ -0,0 +1,224 @@
+// Command sqlite-ddl parses, formats, lints, validates, compares, orders,
+// draws, documents, translates, generates code and test data from SQLite
+// CREATE TABLE statements.
+//
+// Usage:
+//
+//	sqlite-ddl <command> [flags] [file ...]
+//
+// Every command reads standard input when no file is given or a file is
+// named "-". A file may also be an SQLite database, whose schema is read
+// directly from the file. The exit status is 0 on success, 1 when the command found
+// problems (lint findings, validation errors, differences, unformatted
+// files, foreign key cycles) and 2 when it could not run at all.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/dbfile"
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+const (
+	exitOK       = 0
+	exitFindings = 1
+	exitError    = 2
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout io.Writer) (int, error)
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"parse", "print the parsed tables as JSON or YAML", runParse},
+		{"fmt", "reformat CREATE TABLE statements", runFmt},
+		{"lint", "check tables against schema conventions", runLint},
+		{"validate", "report errors SQLite would raise creating the tables", runValidate},
+		{"diff", "compare two schema files or directories", runDiff},
+		{"graph", "order the objects by their dependencies", runGraph},
+		{"doc", "write a data dictionary in Markdown or HTML", runDoc},
+		{"erd", "draw an entity-relationship diagram of the tables", runERD},
+		{"data", "write INSERT statements of test rows for the tables", runData},
+		{"gen", "generate code from the tables", runGen},
+		{"convert", "translate the tables to another database", runConvert},
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: sqlite-ddl <command> [flags] [file ...]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", c.name, c.summary)
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitError
+	}
+	for _, c := range commands {
+		if c.name != args[0] {
+			continue
+		}
+		code, err := c.run(args[1:], stdout)
+		if err != nil {
+			if !errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintf(stderr, "sqlite-ddl %s: %v\n", c.name, err)
+			}
+			return exitError
+		}
+		return code
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stdout)
+		return exitOK
+	}
+	fmt.Fprintf(stderr, "sqlite-ddl: unknown command %q\n", args[0])
+	usage(stderr)
+	return exitError
+}
+
+// input is one SQL script named by the command line, or the statements
+// read from a database file.
+type input struct {
+	name     string
+	sql      string
+	database bool
+}
+
+var stdin io.Reader = os.Stdin
+
+func readInput(name string) (input, error) {
+	var data []byte
+	var err error
+	if name == "-" {
+		name = "<stdin>"
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil || !bytes.HasPrefix(data, []byte(dbfile.Magic)) {
+		return input{name: name, sql: string(data)}, err
+	}
+	sql, err := databaseSQL(data)
+	if err != nil {
+		return input{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return input{name: name, sql: sql, database: true}, nil
+}
+
+// databaseSQL returns the statements creating the objects of an SQLite
+// database file, so that every command accepts database files as well as
+// scripts.
+func databaseSQL(data []byte) (string, error) {
+	f, err := dbfile.NewFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+	objects, err := f.Objects()
+	if err != nil {
+		return "", err
+	}
+	var sql strings.Builder
+	for _, object := range objects {
+		if object.SQL != "" {
+			sql.WriteString(object.SQL)
+			sql.WriteString(";\n")
+		}
+	}
+	return sql.String(), nil
+}
+
+func readInputs(names []string) ([]input, error) {
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+	var inputs []input
+	for _, name := range names {
+		in, err := readInput(name)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, in)
+	}
+	return inputs, nil
+}
+
+// readSchemaPath reads a single file, or every *.sql file of a directory in
+// lexical order, and parses the result.
+func readSchemaPath(path string) (*parser.Schema, error) {
+	var sql strings.Builder
+	info, err := os.Stat(path)
+	if path != "-" && err != nil {
+		return nil, err
+	}
+	if path != "-" && info.IsDir() {
+		files, err := filepath.Glob(filepath.Join(path, "*.sql"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			in, err := readInput(file)
+			if err != nil {
+				return nil, err
+			}
+			sql.WriteString(in.sql)
+			sql.WriteString(";\n")
+		}
+	} else {
+		in, err := readInput(path)
+		if err != nil {
+			return nil, err
+		}
+		sql.WriteString(in.sql)
+	}
+	return parseSchema(path, sql.String())
+}
+
+func parseSchema(name, sql string) (*parser.Schema, error) {
+	schema, errCode := parser.ParseSchema(sql)
+	if errCode != parser.ERROR_NONE {
+		return nil, fmt.Errorf("%s: cannot parse schema: %s error", name, errCode)
+	}
+	return schema, nil
+}
+
+func newFlagSet(name, args string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: sqlite-ddl %s [flags] %s\n", name, args)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
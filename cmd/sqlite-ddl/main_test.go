@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestCommands(t *testing.T) {
+	dir := t.TempDir()
+	valid := writeFile(t, dir, "valid.sql", "-- users\ncreate table users (id integer primary key, name text not null);\n")
+	invalid := writeFile(t, dir, "invalid.sql", "CREATE TABLE t (a, a);")
+	mysqlDump := writeFile(t, dir, "mysql.sql", "CREATE TABLE `t` (`id` int NOT NULL AUTO_INCREMENT PRIMARY KEY) ENGINE=MyISAM;")
+	changed := writeFile(t, dir, "changed.sql", "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);")
+	objects := writeFile(t, dir, "objects.sql", "CREATE TABLE t (a INT); CREATE INDEX t_a ON t (a); CREATE VIEW v AS SELECT a FROM t;")
+	cycle := writeFile(t, dir, "cycle.sql", "CREATE TABLE a (b_id REFERENCES b); CREATE TABLE b (a_id REFERENCES a); CREATE VIEW v AS SELECT * FROM a;")
+
+	tests := []struct {
+		args   []string
+		code   int
+		output string
+	}{
+		{[]string{"parse", valid}, exitOK, `"name": "users"`},
+		{[]string{"parse", "-format", "yaml", valid}, exitOK, "name: users"},
+		{[]string{"parse", "../../dbfile/testdata/schema.db"}, exitOK, `"name": "wide"`},
+		{[]string{"fmt", valid}, exitOK, "-- users\nCREATE TABLE users (\n  id integer PRIMARY KEY,\n  name text NOT NULL\n);\n"},
+		{[]string{"fmt", "-check", valid}, exitFindings, valid},
+		{[]string{"validate", valid}, exitOK, ""},
+		{[]string{"validate", invalid}, exitFindings, "duplicate column name: a"},
+		{[]string{"lint", "-disable", "snake-case-names", valid}, exitOK, ""},
+		{[]string{"lint", invalid}, exitFindings, "[require-primary-key]"},
+		{[]string{"diff", valid, changed}, exitFindings, "~ column users.name: name text NOT NULL -> name TEXT"},
+		{[]string{"diff", valid, dir + "/valid.sql"}, exitOK, ""},
+		{[]string{"graph", valid}, exitOK, "table users\n"},
+		{[]string{"graph", cycle}, exitFindings, "table b\ntable a\nview v\nwarning: foreign key cycle between a, b:"},
+		{[]string{"graph", "-format", "dot", cycle}, exitFindings, `"v" -> "a" [style=dashed];`},
+		{[]string{"graph", "-format", "mermaid", valid}, exitOK, `n0["users"]`},
+		{[]string{"graph", "-format", "svg", valid}, exitError, ""},
+		{[]string{"doc", valid}, exitOK, "| `name` | text | TEXT | no |  |  |  |  |\n"},
+		{[]string{"doc", "-format", "html", "-o", filepath.Join(dir, "site"), valid}, exitOK, ""},
+		{[]string{"doc", "-format", "html", valid}, exitError, ""},
+		{[]string{"erd", cycle}, exitOK, "    b |o--o{ a : \"b_id\"\n"},
+		{[]string{"erd", "-format", "plantuml", "-exclude", "b", cycle}, exitOK, "entity \"a\" as e0 {\n  b_id <<FK>>\n}\n@enduml\n"},
+		{[]string{"erd", "-format", "dot", "-columns", "none", cycle}, exitOK, `"a" -> "b" [dir=both`},
+		{[]string{"erd", "-columns", "some", cycle}, exitError, ""},
+		{[]string{"data", "-rows", "2", valid}, exitOK, "INSERT INTO users (id, name) VALUES\n  (1, "},
+		{[]string{"data", "-counts", "USERS=0", valid}, exitOK, ""},
+		{[]string{"data", "-counts", "users", valid}, exitError, ""},
+		{[]string{"data", cycle}, exitError, ""},
+		{[]string{"gen", "-package", "db", valid}, exitOK, "type Users struct"},
+		{[]string{"gen", "-lang", "typescript", valid}, exitOK, "export interface NewUsers {"},
+		{[]string{"gen", "-lang", "proto", "-lock", filepath.Join(dir, "proto.lock"), valid}, exitOK, "string name = 2;"},
+		{[]string{"gen", "-lang", "jsonschema", valid}, exitOK, `"NewUsers": {`},
+		{[]string{"gen", "-lang", "openapi", valid}, exitOK, `"openapi": "3.1.0"`},
+		{[]string{"gen", "-lang", "rust", valid}, exitError, ""},
+		{[]string{"convert", valid}, exitOK, "CREATE TABLE users (\n  id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,"},
+		{[]string{"convert", "-strict", invalid}, exitFindings, "-- warning: t.a: the column has no type"},
+		{[]string{"convert", "-to", "mysql", "-engine", "Aria", valid}, exitOK, ") ENGINE=Aria DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;"},
+		{[]string{"convert", "-to", "mysql", "-format", "json", invalid}, exitOK, `"feature": "untyped column"`},
+		{[]string{"convert", "-to", "oracle", valid}, exitError, ""},
+		{[]string{"convert", objects}, exitOK, "\nCREATE INDEX t_a ON t (a);\n"},
+		{[]string{"convert", "-to", "mysql", "-strict", objects}, exitFindings, "-- warning: v: views are not translated; the view is skipped"},
+		{[]string{"convert", "-from", "mysql", mysqlDump}, exitOK, "CREATE TABLE t (\n  id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL\n);\n"},
+		{[]string{"convert", "-from", "postgres", mysqlDump}, exitError, ""},
+		{[]string{"parse", filepath.Join(dir, "missing.sql")}, exitError, ""},
+		{[]string{"nope"}, exitError, ""},
+	}
+
+	for _, test := range tests {
+		var stdout, stderr bytes.Buffer
+		code := run(test.args, &stdout, &stderr)
+		assert.Equal(t, test.code, code, strings.Join(test.args, " ")+": "+stderr.String())
+		assert.Contains(t, stdout.String(), test.output, strings.Join(test.args, " "))
+	}
+}
+
+func TestGenProtoLock(t *testing.T) {
+	dir := t.TempDir()
+	lock := filepath.Join(dir, "proto.lock")
+	before := writeFile(t, dir, "before.sql", "CREATE TABLE t (a INTEGER, b TEXT);")
+	after := writeFile(t, dir, "after.sql", "CREATE TABLE t (b TEXT, c REAL);")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"gen", "-lang", "proto", "-lock", lock, before}, &stdout, &stderr))
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"gen", "-lang", "proto", "-lock", lock, after}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "message T {\n  reserved 1;\n  reserved \"a\";\n  optional string b = 2;\n  optional double c = 3;\n}")
+}
+
+func TestFmtWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "schema.sql", "create table a(x);create index i on a(x);")
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"fmt", "-w", path}, &stdout, &stderr))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE a (\n  x\n);\n\ncreate index i on a(x);\n", string(data))
+
+	assert.Equal(t, exitOK, run([]string{"fmt", "-check", path}, &stdout, &stderr))
+}
+
+func TestFmtComments(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "schema.sql", `create table Users ( -- lint:ignore snake-case-names
+  id integer primary key, -- the rowid
+  /* shown on the profile */
+  name text
+);
+`)
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"fmt", "-w", path}, &stdout, &stderr))
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `-- lint:ignore snake-case-names
+CREATE TABLE Users (
+  id integer PRIMARY KEY, -- the rowid
+  /* shown on the profile */
+  name text
+);
+`, string(data))
+
+	assert.Equal(t, exitOK, run([]string{"fmt", "-check", path}, &stdout, &stderr))
+	stdout.Reset()
+	assert.Equal(t, exitOK, run([]string{"lint", path}, &stdout, &stderr))
+	assert.NotContains(t, stdout.String(), "snake_case")
+}
+
+func TestStdin(t *testing.T) {
+	stdin = strings.NewReader("CREATE TABLE t (a INT) WITHOUT ROWID;")
+	defer func() { stdin = os.Stdin }()
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitFindings, run([]string{"validate"}, &stdout, &stderr))
+	assert.Equal(t, "<stdin>: t: PRIMARY KEY missing on table t\n", stdout.String())
+}
@@ -0,0 +1,389 @@
+// Package codegen generates source code from parsed CREATE TABLE statements.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// NullStyle selects how nullable columns are represented in Go.
+type NullStyle int
+
+const (
+	// NULL_SQL uses the sql.NullString family of types.
+	NULL_SQL NullStyle = iota
+	// NULL_POINTER uses a pointer to the non-null type.
+	NULL_POINTER
+	// NULL_GENERIC uses sql.Null[T], available since Go 1.22.
+	NULL_GENERIC
+)
+
+// Placeholder selects the bind parameter syntax of generated queries.
+type Placeholder int
+
+const (
+	// PLACEHOLDER_QUESTION writes "?", understood by SQLite drivers.
+	PLACEHOLDER_QUESTION Placeholder = iota
+	// PLACEHOLDER_DOLLAR writes "$1", "$2", ...
+	PLACEHOLDER_DOLLAR
+)
+
+type GoOptions struct {
+	// Package is the package clause of the generated file; "models" if empty.
+	Package string
+	Null    NullStyle
+	// JSONTags adds a json tag next to the db tag of every field.
+	JSONTags bool
+	// CRUD adds Insert, Get, Update and Delete functions for every table
+	// with a primary key, written against database/sql.
+	CRUD        bool
+	Placeholder Placeholder
+	// Types overrides the GoTypes mapping for matching columns.
+	Types []TypeRule
+}
+
+// GoName converts an SQL identifier such as "user_id" to an exported Go
+// identifier such as "UserID".
+func GoName(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if upper := strings.ToUpper(word); commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	s := b.String()
+	if s == "" || !unicode.IsLetter([]rune(s)[0]) {
+		s = "X" + s
+	}
+	return s
+}
+
+var commonInitialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// goField is a column as it appears in the generated struct.
+type goField struct {
+	column *parser.Column
+	name   string
+	base   string
+	typ    string
+}
+
+var nullTypes = map[string]string{
+	"int64":     "sql.NullInt64",
+	"float64":   "sql.NullFloat64",
+	"string":    "sql.NullString",
+	"bool":      "sql.NullBool",
+	"time.Time": "sql.NullTime",
+}
+
+// goType returns the field type of a column. Nullable columns use the null
+// style, except for slices which already have a nil value; with NULL_SQL,
+// types without an sql.Null counterpart fall back to pointers.
+func goType(table *parser.Table, column *parser.Column, types *TypeMapper, style NullStyle) string {
+	base := types.Map(table, column)
+	if strings.HasPrefix(base, "[]") || !isNullable(table, column) {
+		return base
+	}
+	switch style {
+	case NULL_SQL:
+		if nullType, ok := nullTypes[base]; ok {
+			return nullType
+		}
+	case NULL_GENERIC:
+		return "sql.Null[" + base + "]"
+	}
+	return "*" + base
+}
+
+func goFields(table *parser.Table, types *TypeMapper, style NullStyle) []goField {
+	used := map[string]bool{}
+	fields := make([]goField, 0, len(table.Columns))
+	for i := range table.Columns {
+		column := &table.Columns[i]
+		name := GoName(column.Name)
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s%d", GoName(column.Name), n)
+		}
+		used[name] = true
+		fields = append(fields, goField{column: column, name: name, base: types.Map(table, column), typ: goType(table, column, types, style)})
+	}
+	return fields
+}
+
+// fieldComment describes the keys a column takes part in.
+func fieldComment(table *parser.Table, column *parser.Column) string {
+	var notes []string
+	for i, name := range table.PrimaryKey() {
+		if strings.EqualFold(name, column.Name) {
+			note := "primary key"
+			if len(table.PrimaryKey()) > 1 {
+				note = fmt.Sprintf("primary key (%d of %d)", i+1, len(table.PrimaryKey()))
+			}
+			if column.IsAutoincrement {
+				note += ", autoincrement"
+			}
+			notes = append(notes, note)
+		}
+	}
+	for _, target := range references(table, column) {
+		notes = append(notes, "references "+target)
+	}
+	return strings.Join(notes, "; ")
+}
+
+type goWriter struct {
+	bytes.Buffer
+	imports map[string]bool
+}
+
+func (w *goWriter) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&w.Buffer, format, args...)
+}
+
+// Go returns formatted Go source declaring one struct per table, with a db
+// tag naming each column, and optionally CRUD helpers.
+func Go(options GoOptions, tables ...*parser.Table) ([]byte, error) {
+	w := goWriter{imports: map[string]bool{}}
+	types := &TypeMapper{Kinds: GoTypes, Rules: options.Types}
+
+	if options.CRUD {
+		w.imports["context"] = true
+		w.imports["database/sql"] = true
+		w.printf(`
+// DBTX is satisfied by *sql.DB, *sql.Conn and *sql.Tx.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+`)
+	}
+
+	for _, table := range tables {
+		fields := goFields(table, types, options.Null)
+		typeName := GoName(table.Name)
+
+		w.printf("\n// %s is a row of table %s.\n", typeName, table.Name)
+		w.printf("type %s struct {\n", typeName)
+		for _, field := range fields {
+			tag := fmt.Sprintf("db:%q", field.column.Name)
+			if options.JSONTags {
+				tag += fmt.Sprintf(" json:%q", field.column.Name)
+			}
+			w.printf("%s %s `%s`", field.name, field.typ, tag)
+			if comment := fieldComment(table, field.column); comment != "" {
+				w.printf(" // %s", comment)
+			}
+			w.printf("\n")
+			if strings.Contains(field.typ, "sql.") {
+				w.imports["database/sql"] = true
+			}
+			if strings.Contains(field.typ, "time.") {
+				w.imports["time"] = true
+			}
+		}
+		w.printf("}\n")
+
+		if options.CRUD {
+			writeCRUD(&w, table, fields, options.Placeholder)
+		}
+	}
+
+	pkg := options.Package
+	if pkg == "" {
+		pkg = "models"
+	}
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by sqlite-ddl. DO NOT EDIT.\n\npackage %s\n", pkg)
+	if len(w.imports) > 0 {
+		var imports []string
+		for path := range w.imports {
+			imports = append(imports, path)
+		}
+		sort.Strings(imports)
+		src.WriteString("\nimport (\n")
+		for _, path := range imports {
+			fmt.Fprintf(&src, "%q\n", path)
+		}
+		src.WriteString(")\n")
+	}
+	src.Write(w.Bytes())
+	return format.Source(src.Bytes())
+}
+
+func placeholders(style Placeholder, from, n int) []string {
+	list := make([]string, n)
+	for i := range list {
+		if style == PLACEHOLDER_DOLLAR {
+			list[i] = fmt.Sprintf("$%d", from+i)
+		} else {
+			list[i] = "?"
+		}
+	}
+	return list
+}
+
+func writeCRUD(w *goWriter, table *parser.Table, fields []goField, style Placeholder) {
+	key := table.PrimaryKey()
+	if len(key) == 0 {
+		return
+	}
+	var keyFields, valueFields []goField
+	for _, field := range fields {
+		isKey := false
+		for _, name := range key {
+			isKey = isKey || strings.EqualFold(name, field.column.Name)
+		}
+		if isKey {
+			keyFields = append(keyFields, field)
+		} else {
+			valueFields = append(valueFields, field)
+		}
+	}
+
+	typeName := GoName(table.Name)
+	tableName := parser.QuoteIdentifier(table.Name)
+	columns := func(fields []goField) []string {
+		names := make([]string, len(fields))
+		for i, field := range fields {
+			names[i] = parser.QuoteIdentifier(field.column.Name)
+		}
+		return names
+	}
+	args := func(prefix string, fields []goField) string {
+		list := make([]string, len(fields))
+		for i, field := range fields {
+			list[i] = prefix + field.name
+		}
+		return strings.Join(list, ", ")
+	}
+	where := func(from int) string {
+		conditions := make([]string, len(keyFields))
+		for i, column := range columns(keyFields) {
+			conditions[i] = column + " = " + placeholders(style, from+i, 1)[0]
+		}
+		return strings.Join(conditions, " AND ")
+	}
+	params := make([]string, len(keyFields))
+	for i, field := range keyFields {
+		params[i] = fmt.Sprintf("%s %s", unexport(field.name), field.base)
+	}
+	paramNames := make([]string, len(keyFields))
+	for i, field := range keyFields {
+		paramNames[i] = unexport(field.name)
+	}
+
+	insert := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", tableName, strings.Join(columns(fields), ", "),
+		strings.Join(placeholders(style, 1, len(fields)), ", "))
+	if alias := rowidField(table, fields); alias != nil {
+		// NULL makes SQLite assign the rowid, which is read back into row.
+		insertArgs := make([]string, len(fields))
+		for i, field := range fields {
+			insertArgs[i] = "row." + field.name
+			if field.name == alias.name {
+				insertArgs[i] = "rowid"
+			}
+		}
+		w.printf("\n// Insert%s inserts row into %s. When row.%s is zero, SQLite assigns the\n", typeName, table.Name, alias.name)
+		w.printf("// rowid and row.%s is set to it.\n", alias.name)
+		w.printf("func Insert%s(ctx context.Context, db DBTX, row *%s) (sql.Result, error) {\n", typeName, typeName)
+		w.printf("var rowid interface{} = row.%s\nif row.%s == 0 {\nrowid = nil\n}\n", alias.name, alias.name)
+		w.printf("result, err := db.ExecContext(ctx, %q, %s)\n", insert, strings.Join(insertArgs, ", "))
+		w.printf("if err != nil || row.%s != 0 {\nreturn result, err\n}\n", alias.name)
+		w.printf("id, err := result.LastInsertId()\nif err != nil {\nreturn result, err\n}\n")
+		if alias.typ == "int64" {
+			w.printf("row.%s = id\n", alias.name)
+		} else {
+			w.printf("row.%s = %s(id)\n", alias.name, alias.typ)
+		}
+		w.printf("return result, nil\n}\n")
+	} else {
+		w.printf("\n// Insert%s inserts row into %s.\n", typeName, table.Name)
+		w.printf("func Insert%s(ctx context.Context, db DBTX, row *%s) (sql.Result, error) {\n", typeName, typeName)
+		w.printf("return db.ExecContext(ctx, %q, %s)\n}\n", insert, args("row.", fields))
+	}
+
+	w.printf("\n// Get%s returns the row of %s with the given primary key.\n", typeName, table.Name)
+	w.printf("func Get%s(ctx context.Context, db DBTX, %s) (*%s, error) {\n", typeName, strings.Join(params, ", "), typeName)
+	w.printf("var row %s\n", typeName)
+	w.printf("err := db.QueryRowContext(ctx, %q, %s).Scan(%s)\n",
+		fmt.Sprintf("SELECT %s FROM %s WHERE %s", strings.Join(columns(fields), ", "), tableName, where(1)),
+		strings.Join(paramNames, ", "), args("&row.", fields))
+	w.printf("if err != nil {\nreturn nil, err\n}\nreturn &row, nil\n}\n")
+
+	if len(valueFields) > 0 {
+		assignments := make([]string, len(valueFields))
+		for i, column := range columns(valueFields) {
+			assignments[i] = column + " = " + placeholders(style, i+1, 1)[0]
+		}
+		w.printf("\n// Update%s updates the row of %s with the primary key of row.\n", typeName, table.Name)
+		w.printf("func Update%s(ctx context.Context, db DBTX, row *%s) (sql.Result, error) {\n", typeName, typeName)
+		w.printf("return db.ExecContext(ctx, %q, %s, %s)\n}\n",
+			fmt.Sprintf("UPDATE %s SET %s WHERE %s", tableName, strings.Join(assignments, ", "), where(len(valueFields)+1)),
+			args("row.", valueFields), args("row.", keyFields))
+	}
+
+	w.printf("\n// Delete%s deletes the row of %s with the given primary key.\n", typeName, table.Name)
+	w.printf("func Delete%s(ctx context.Context, db DBTX, %s) (sql.Result, error) {\n", typeName, strings.Join(params, ", "))
+	w.printf("return db.ExecContext(ctx, %q, %s)\n}\n",
+		fmt.Sprintf("DELETE FROM %s WHERE %s", tableName, where(1)), strings.Join(paramNames, ", "))
+}
+
+var goIntegers = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+}
+
+// rowidField returns the field of the INTEGER PRIMARY KEY aliasing the
+// rowid, when its Go type is an integer, or nil.
+func rowidField(table *parser.Table, fields []goField) *goField {
+	key := table.PrimaryKey()
+	for i := range fields {
+		column := fields[i].column
+		if !table.IsWithoutRowid && len(key) == 1 && strings.EqualFold(key[0], column.Name) &&
+			strings.EqualFold(column.Type, "INTEGER") && column.PkOrder != parser.ORDER_DESC && goIntegers[fields[i].typ] {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+func unexport(name string) string {
+	runes := []rune(name)
+	i := 0
+	for i < len(runes) && unicode.IsUpper(runes[i]) {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+		i++
+	}
+	s := string(runes)
+	if goKeywords[s] {
+		s += "_"
+	}
+	return s
+}
+
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true,
+	"defer": true, "else": true, "fallthrough": true, "for": true, "func": true, "go": true, "goto": true,
+	"if": true, "import": true, "interface": true, "map": true, "package": true, "range": true,
+	"return": true, "select": true, "struct": true, "switch": true, "type": true, "var": true,
+	// Names used by the generated functions themselves.
+	"ctx": true, "db": true, "row": true, "err": true,
+}
@@ -0,0 +1,152 @@
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ddl "github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+const schema = `
+CREATE TABLE users (
+ id INTEGER PRIMARY KEY AUTOINCREMENT,
+ name TEXT NOT NULL,
+ email VARCHAR(100),
+ created_at DATETIME,
+ active BOOLEAN NOT NULL,
+ avatar BLOB
+);
+CREATE TABLE memberships (
+ user_id INTEGER REFERENCES users (id),
+ type TEXT,
+ PRIMARY KEY (user_id, type)
+) WITHOUT ROWID;
+`
+
+func parseSchema(t *testing.T) []*ddl.Table {
+	s, errCode := ddl.ParseSchema(schema)
+	assert.Equal(t, ddl.ERROR_NONE, errCode)
+	return s.Tables
+}
+
+func typeCheck(t *testing.T, src []byte) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "models.go", src, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	config := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	_, err = config.Check("models", fset, []*ast.File{file}, nil)
+	assert.NoError(t, err, string(src))
+}
+
+func TestGo(t *testing.T) {
+	src, err := Go(GoOptions{Package: "db", JSONTags: true}, parseSchema(t)...)
+	assert.NoError(t, err)
+	typeCheck(t, src)
+
+	for _, line := range []string{
+		"package db",
+		"ID        int64          `db:\"id\" json:\"id\"` // primary key, autoincrement",
+		"Email     sql.NullString `db:\"email\" json:\"email\"`",
+		"CreatedAt sql.NullTime",
+		"Active    bool",
+		"Avatar    []byte",
+		"UserID int64  `db:\"user_id\" json:\"user_id\"` // primary key (1 of 2); references users.id",
+	} {
+		assert.Contains(t, string(src), line)
+	}
+}
+
+func TestGoNullStyles(t *testing.T) {
+	src, err := Go(GoOptions{Null: NULL_POINTER}, parseSchema(t)...)
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "Email     *string")
+	assert.Contains(t, string(src), "CreatedAt *time.Time")
+
+	src, err = Go(GoOptions{Null: NULL_GENERIC}, parseSchema(t)...)
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "Email     sql.Null[string]")
+}
+
+func TestGoCRUD(t *testing.T) {
+	src, err := Go(GoOptions{CRUD: true, Placeholder: PLACEHOLDER_DOLLAR}, parseSchema(t)...)
+	assert.NoError(t, err)
+	typeCheck(t, src)
+
+	assert.Contains(t, string(src), `"UPDATE users SET name = $1, email = $2, created_at = $3, active = $4, avatar = $5 WHERE id = $6"`)
+	assert.Contains(t, string(src), "func GetMemberships(ctx context.Context, db DBTX, userID int64, type_ string) (*Memberships, error)")
+	assert.Contains(t, string(src), `"DELETE FROM memberships WHERE user_id = $1 AND type = $2"`)
+	assert.NotContains(t, string(src), "func UpdateMemberships")
+}
+
+// TestGoInsertRowid runs the generated InsertUsers against SQLite: a zero
+// ID lets SQLite assign the rowid, so inserting twice gives two rows.
+func TestGoInsertRowid(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs a program")
+	}
+	src, err := Go(GoOptions{Package: "main", CRUD: true}, parseSchema(t)...)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, string(src), "// InsertUsers inserts row into users. When row.ID is zero, SQLite assigns the")
+
+	// Inside the module, the program builds with its dependencies; go
+	// commands ignore the directory, whose name starts with "_".
+	dir, err := os.MkdirTemp(".", "_insert")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+	const program = `package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+)
+
+func main() {
+	ctx := context.Background()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+	if _, err := db.Exec(%q); err != nil {
+		panic(err)
+	}
+	for _, row := range []*Users{{Name: "a"}, {Name: "b"}, {ID: 10, Name: "c"}} {
+		if _, err := InsertUsers(ctx, db, row); err != nil {
+			panic(err)
+		}
+		fmt.Println(row.ID, row.Name)
+	}
+}
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "models.go"), src, 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte(fmt.Sprintf(program, schema)), 0o644))
+
+	output, err := exec.Command("go", "run", "./"+filepath.ToSlash(dir)).CombinedOutput()
+	if assert.NoError(t, err, string(output)) {
+		assert.Equal(t, "1 a\n2 b\n10 c\n", string(output))
+	}
+}
+
+func TestGoName(t *testing.T) {
+	assert.Equal(t, "UserID", GoName("user_id"))
+	assert.Equal(t, "HTTPStatus", GoName("http-status"))
+	assert.Equal(t, "X2fa", GoName("2fa"))
+}
@@ -0,0 +1,271 @@
+package codegen
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// JSONSchema is the subset of JSON Schema (draft 2020-12, which is also the
+// schema dialect of OpenAPI 3.1) used to describe table rows.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 interface{}            `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	ContentEncoding      string                 `json:"contentEncoding,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*JSONSchema `json:"$defs,omitempty"`
+}
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// TableSchema returns the JSON Schema of the object sent to insert a row
+// into table: properties are typed from the column kinds, and columns that
+// are NOT NULL without a DEFAULT are required.
+func TableSchema(table *parser.Table) *JSONSchema {
+	return tableSchema(table, false)
+}
+
+// tableSchema describes a row as written, or as read back when row is set:
+// then every column that cannot be NULL is required.
+func tableSchema(table *parser.Table, row bool) *JSONSchema {
+	closed := false
+	schema := &JSONSchema{
+		Title:                GoName(table.Name),
+		Description:          "A row of table " + table.Name + ".",
+		Type:                 "object",
+		Properties:           map[string]*JSONSchema{},
+		AdditionalProperties: &closed,
+	}
+	if !row {
+		schema.Title = "New" + schema.Title
+		schema.Description = "The values of a row inserted into " + table.Name + "."
+	}
+	for i := range table.Columns {
+		column := &table.Columns[i]
+		schema.Properties[column.Name] = columnSchema(table, column)
+		if !isNullable(table, column) && (row || !hasDefault(table, column)) {
+			schema.Required = append(schema.Required, column.Name)
+		}
+	}
+	return schema
+}
+
+var jsonTypes = map[Kind]string{
+	KIND_INTEGER: "integer",
+	KIND_REAL:    "number",
+	KIND_NUMERIC: "number",
+	KIND_TEXT:    "string",
+	KIND_BLOB:    "string",
+	KIND_BOOL:    "boolean",
+	KIND_TIME:    "string",
+}
+
+func columnSchema(table *parser.Table, column *parser.Column) *JSONSchema {
+	kind := ColumnKind(column)
+	schema := &JSONSchema{Description: fieldComment(table, column)}
+	// A column without a declared type holds anything.
+	if column.Type != "" {
+		schema.Type = jsonTypes[kind]
+	}
+	switch {
+	case kind == KIND_BLOB && column.Type != "":
+		schema.ContentEncoding = "base64"
+	case schema.Type == "string":
+		schema.Format = stringFormat(column)
+		if n, err := strconv.Atoi(strings.TrimSpace(strings.Split(column.Length, ",")[0])); err == nil && kind == KIND_TEXT {
+			schema.MaxLength = &n
+		}
+	}
+	schema.Enum = columnEnum(table, column)
+	if schema.Type != nil && isNullable(table, column) {
+		schema.Type = []string{schema.Type.(string), "null"}
+		if schema.Enum != nil {
+			schema.Enum = append(schema.Enum, nil)
+		}
+	}
+	return schema
+}
+
+// stringFormat returns the format of a string column holding dates or times,
+// recognized from the declared type or, for TEXT columns, from names such as
+// "created_at" or "birth_date".
+func stringFormat(column *parser.Column) string {
+	declared := strings.ToUpper(column.Type)
+	switch {
+	case strings.Contains(declared, "DATETIME"), strings.Contains(declared, "TIMESTAMP"):
+		return "date-time"
+	case strings.Contains(declared, "DATE"):
+		return "date"
+	case strings.Contains(declared, "TIME"):
+		return "time"
+	}
+	name := strings.ToLower(column.Name)
+	switch {
+	case strings.HasSuffix(name, "_at"), strings.HasSuffix(name, "timestamp"), strings.HasSuffix(name, "datetime"):
+		return "date-time"
+	case strings.HasSuffix(name, "_on"), strings.HasSuffix(name, "date"):
+		return "date"
+	case strings.HasSuffix(name, "_time"):
+		return "time"
+	}
+	return ""
+}
+
+// columnEnum returns the values of a CHECK (column IN (...)) constraint on
+// the column, declared either on the column or on the table.
+func columnEnum(table *parser.Table, column *parser.Column) []interface{} {
+	checks := []string{column.CheckExpr}
+	for _, constraint := range table.Constraints {
+		if constraint.Type == parser.TABLECONSTRAINT_CHECK {
+			checks = append(checks, constraint.CheckExpr)
+		}
+	}
+	for _, check := range checks {
+		if name, values, ok := parseInList(check); ok && strings.EqualFold(name, column.Name) {
+			return values
+		}
+	}
+	return nil
+}
+
+// parseInList recognizes an expression of the form name IN (literal, ...),
+// optionally in parentheses, where the literals are strings or numbers.
+func parseInList(expr string) (string, []interface{}, bool) {
+	s := strings.TrimSpace(expr)
+	for len(s) > 1 && s[0] == '(' && s[len(s)-1] == ')' {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	name, s, ok := scanName(s)
+	if !ok {
+		return "", nil, false
+	}
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || !strings.EqualFold(s[:2], "IN") {
+		return "", nil, false
+	}
+	s = strings.TrimSpace(s[2:])
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return "", nil, false
+	}
+	var values []interface{}
+	for s = s[1:]; ; {
+		var value interface{}
+		if value, s, ok = scanLiteral(strings.TrimSpace(s)); !ok {
+			return "", nil, false
+		}
+		values = append(values, value)
+		s = strings.TrimSpace(s)
+		if s == ")" {
+			return name, values, true
+		}
+		if !strings.HasPrefix(s, ",") {
+			return "", nil, false
+		}
+		s = s[1:]
+	}
+}
+
+// scanName reads a bare or quoted identifier at the start of s.
+func scanName(s string) (string, string, bool) {
+	if s == "" {
+		return "", s, false
+	}
+	if end := map[byte]byte{'"': '"', '`': '`', '[': ']'}[s[0]]; end != 0 {
+		i := strings.IndexByte(s[1:], end)
+		if i < 0 {
+			return "", s, false
+		}
+		return s[1 : i+1], s[i+2:], true
+	}
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	return s[:i], s[i:], i > 0
+}
+
+// scanLiteral reads a string or numeric literal at the start of s.
+func scanLiteral(s string) (interface{}, string, bool) {
+	if strings.HasPrefix(s, "'") {
+		var b strings.Builder
+		for i := 1; i < len(s); i++ {
+			if s[i] != '\'' {
+				b.WriteByte(s[i])
+			} else if i+1 < len(s) && s[i+1] == '\'' {
+				b.WriteByte('\'')
+				i++
+			} else {
+				return b.String(), s[i+1:], true
+			}
+		}
+		return nil, s, false
+	}
+	i := strings.IndexAny(s, ",) \t\n")
+	if i < 0 {
+		i = len(s)
+	}
+	if n, err := strconv.ParseInt(s[:i], 10, 64); err == nil {
+		return n, s[i:], true
+	}
+	if f, err := strconv.ParseFloat(s[:i], 64); err == nil {
+		return f, s[i:], true
+	}
+	return nil, s, false
+}
+
+// JSONSchemas returns a JSON Schema document defining, under $defs, the
+// insert schema of every table as returned by TableSchema.
+func JSONSchemas(tables ...*parser.Table) ([]byte, error) {
+	document := &JSONSchema{Schema: jsonSchemaDialect, Defs: map[string]*JSONSchema{}}
+	for _, table := range tables {
+		schema := TableSchema(table)
+		document.Defs[schema.Title] = schema
+	}
+	return json.MarshalIndent(document, "", "  ")
+}
+
+type OpenAPIOptions struct {
+	// Title and Version fill the info object; "Schema" and "1.0.0" if empty.
+	Title   string
+	Version string
+}
+
+// OpenAPI returns an OpenAPI 3.1 document whose components hold two schemas
+// per table: one named after the table describing a row as returned, where
+// every column that cannot be NULL is required, and one prefixed with New
+// describing a row to insert, as returned by TableSchema.
+func OpenAPI(options OpenAPIOptions, tables ...*parser.Table) ([]byte, error) {
+	info := map[string]string{"title": options.Title, "version": options.Version}
+	if info["title"] == "" {
+		info["title"] = "Schema"
+	}
+	if info["version"] == "" {
+		info["version"] = "1.0.0"
+	}
+	schemas := map[string]*JSONSchema{}
+	for _, table := range tables {
+		row := tableSchema(table, true)
+		schemas[row.Title] = row
+		insert := tableSchema(table, false)
+		schemas[insert.Title] = insert
+	}
+	return json.MarshalIndent(map[string]interface{}{
+		"openapi":           "3.1.0",
+		"info":              info,
+		"jsonSchemaDialect": jsonSchemaDialect,
+		"paths":             map[string]interface{}{},
+		"components":        map[string]interface{}{"schemas": schemas},
+	}, "", "  ")
+}
@@ -0,0 +1,74 @@
+package codegen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ddl "github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+func TestTableSchema(t *testing.T) {
+	tables := parseSchema(t)
+	users := tables[0]
+	users.Column("email").Length = "100"
+	// Add a column with a CHECK constraint and a table CHECK constraint.
+	users.Columns = append(users.Columns, ddl.Column{Name: "role", Type: "TEXT", CheckExpr: "role IN ('admin', 'it''s me')"})
+	users.Constraints = append(users.Constraints, ddl.TableConstraint{Type: ddl.TABLECONSTRAINT_CHECK, CheckExpr: "(level in (1, 2.5))"})
+	users.Columns = append(users.Columns, ddl.Column{Name: "level", Type: "NUMERIC", IsNotnull: true, DefaultExpr: "1"})
+	users.Columns = append(users.Columns, ddl.Column{Name: "deleted_at", Type: "TEXT"})
+
+	data, err := json.Marshal(TableSchema(users))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"title": "NewUsers",
+		"description": "The values of a row inserted into users.",
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["name", "active"],
+		"properties": {
+			"id": {"type": "integer", "description": "primary key, autoincrement"},
+			"name": {"type": "string"},
+			"email": {"type": ["string", "null"], "maxLength": 100},
+			"created_at": {"type": ["string", "null"], "format": "date-time"},
+			"active": {"type": "boolean"},
+			"avatar": {"type": ["string", "null"], "contentEncoding": "base64"},
+			"role": {"type": ["string", "null"], "enum": ["admin", "it's me", null]},
+			"level": {"type": "number", "enum": [1, 2.5]},
+			"deleted_at": {"type": ["string", "null"], "format": "date-time"}
+		}
+	}`, string(data))
+}
+
+func TestParseInList(t *testing.T) {
+	name, values, ok := parseInList(`"kind" IN ( 'a' ,'b')`)
+	assert.True(t, ok)
+	assert.Equal(t, "kind", name)
+	assert.Equal(t, []interface{}{"a", "b"}, values)
+
+	for _, expr := range []string{"", "kind = 'a'", "kind IN ('a', b)", "kind IN ('a'", "kind INSIDE ('a')", "length(kind) IN (1)"} {
+		_, _, ok := parseInList(expr)
+		assert.False(t, ok, expr)
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	data, err := OpenAPI(OpenAPIOptions{Title: "Members"}, parseSchema(t)...)
+	assert.NoError(t, err)
+
+	var document struct {
+		OpenAPI    string
+		Info       map[string]string
+		Components struct {
+			Schemas map[string]JSONSchema
+		}
+	}
+	assert.NoError(t, json.Unmarshal(data, &document))
+	assert.Equal(t, "3.1.0", document.OpenAPI)
+	assert.Equal(t, map[string]string{"title": "Members", "version": "1.0.0"}, document.Info)
+	assert.Len(t, document.Components.Schemas, 4)
+	assert.Equal(t, []string{"id", "name", "active"}, document.Components.Schemas["Users"].Required)
+	assert.Equal(t, []string{"name", "active"}, document.Components.Schemas["NewUsers"].Required)
+	assert.Equal(t, []string{"user_id", "type"}, document.Components.Schemas["Memberships"].Required)
+}
@@ -0,0 +1,208 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+type ProtoOptions struct {
+	// Package is the package statement of the generated file; omitted if empty.
+	Package string
+	// Types overrides the ProtoTypes mapping for matching columns.
+	Types []TypeRule
+	// Lock keeps field numbers stable across regenerations. Proto records the
+	// numbers it assigns in it, so the caller should save it afterwards. A nil
+	// Lock numbers the fields in column order.
+	Lock *ProtoLock
+}
+
+// ProtoLock records the field numbers assigned to the columns of every
+// message. It is stored as JSON next to the generated .proto file.
+type ProtoLock struct {
+	Messages map[string]*ProtoMessageLock `json:"messages"`
+}
+
+// ProtoMessageLock holds the field numbers of one message. Columns that
+// disappear from the table keep their number in Reserved so that it is never
+// reused for a different field.
+type ProtoMessageLock struct {
+	Fields        map[string]int `json:"fields"`
+	Reserved      []int          `json:"reserved,omitempty"`
+	ReservedNames []string       `json:"reserved_names,omitempty"`
+}
+
+func NewProtoLock() *ProtoLock {
+	return &ProtoLock{Messages: map[string]*ProtoMessageLock{}}
+}
+
+// ReadProtoLock decodes a lock written by Write.
+func ReadProtoLock(r io.Reader) (*ProtoLock, error) {
+	lock := NewProtoLock()
+	if err := json.NewDecoder(r).Decode(lock); err != nil {
+		return nil, err
+	}
+	if lock.Messages == nil {
+		lock.Messages = map[string]*ProtoMessageLock{}
+	}
+	for _, message := range lock.Messages {
+		if message.Fields == nil {
+			message.Fields = map[string]int{}
+		}
+	}
+	return lock, nil
+}
+
+func (l *ProtoLock) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(l)
+}
+
+// number returns the field number of name, assigning the next free one if
+// the field is new.
+func (m *ProtoMessageLock) number(name string) int {
+	if n, ok := m.Fields[name]; ok {
+		return n
+	}
+	next := 1
+	for _, n := range m.Fields {
+		if n >= next {
+			next = n + 1
+		}
+	}
+	for _, n := range m.Reserved {
+		if n >= next {
+			next = n + 1
+		}
+	}
+	m.Fields[name] = next
+	return next
+}
+
+// retain moves the fields not in names to the reserved lists. A reserved
+// name that is back in use gets a new number instead of its old one.
+func (m *ProtoMessageLock) retain(names map[string]bool) {
+	var reservedNames []string
+	for _, name := range m.ReservedNames {
+		if !names[name] {
+			reservedNames = append(reservedNames, name)
+		}
+	}
+	m.ReservedNames = reservedNames
+	for name, n := range m.Fields {
+		if !names[name] {
+			delete(m.Fields, name)
+			m.Reserved = append(m.Reserved, n)
+			m.ReservedNames = append(m.ReservedNames, name)
+		}
+	}
+	sort.Ints(m.Reserved)
+	sort.Strings(m.ReservedNames)
+}
+
+// ProtoName converts an SQL identifier to a proto field name: lower case
+// letters, digits and underscores, starting with a letter.
+func ProtoName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	s := b.String()
+	if s == "" || !unicode.IsLetter(rune(s[0])) {
+		s = "x" + s
+	}
+	return s
+}
+
+// Proto returns a proto3 file with one message per table, named like the Go
+// structs of Go. Nullable scalar columns are declared optional so that NULL
+// can be told apart from the zero value.
+func Proto(options ProtoOptions, tables ...*parser.Table) ([]byte, error) {
+	types := &TypeMapper{Kinds: ProtoTypes, Rules: options.Types}
+	lock := options.Lock
+	if lock == nil {
+		lock = NewProtoLock()
+	}
+
+	var body bytes.Buffer
+	imports := map[string]bool{}
+	for _, table := range tables {
+		messageName := GoName(table.Name)
+		message := lock.Messages[messageName]
+		if message == nil {
+			message = &ProtoMessageLock{Fields: map[string]int{}}
+			lock.Messages[messageName] = message
+		}
+
+		names := map[string]bool{}
+		for i := range table.Columns {
+			names[ProtoName(table.Columns[i].Name)] = true
+		}
+		message.retain(names)
+
+		fmt.Fprintf(&body, "\n// %s is a row of table %s.\n", messageName, table.Name)
+		fmt.Fprintf(&body, "message %s {\n", messageName)
+		if len(message.Reserved) > 0 {
+			numbers := make([]string, len(message.Reserved))
+			for i, n := range message.Reserved {
+				numbers[i] = fmt.Sprint(n)
+			}
+			fmt.Fprintf(&body, "  reserved %s;\n", strings.Join(numbers, ", "))
+		}
+		if len(message.ReservedNames) > 0 {
+			quoted := make([]string, len(message.ReservedNames))
+			for i, name := range message.ReservedNames {
+				quoted[i] = fmt.Sprintf("%q", name)
+			}
+			fmt.Fprintf(&body, "  reserved %s;\n", strings.Join(quoted, ", "))
+		}
+		for i := range table.Columns {
+			column := &table.Columns[i]
+			name := ProtoName(column.Name)
+			typ := types.Map(table, column)
+			if typ == "google.protobuf.Timestamp" {
+				imports["google/protobuf/timestamp.proto"] = true
+			}
+			for _, target := range references(table, column) {
+				fmt.Fprintf(&body, "  // references %s\n", target)
+			}
+			label := ""
+			// Message types already track presence.
+			if isNullable(table, column) && !strings.Contains(typ, ".") {
+				label = "optional "
+			}
+			fmt.Fprintf(&body, "  %s%s %s = %d;\n", label, typ, name, message.number(name))
+		}
+		body.WriteString("}\n")
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by sqlite-ddl. DO NOT EDIT.\n\nsyntax = \"proto3\";\n")
+	if options.Package != "" {
+		fmt.Fprintf(&src, "\npackage %s;\n", options.Package)
+	}
+	if len(imports) > 0 {
+		var paths []string
+		for path := range imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		src.WriteString("\n")
+		for _, path := range paths {
+			fmt.Fprintf(&src, "import %q;\n", path)
+		}
+	}
+	src.Write(body.Bytes())
+	return src.Bytes(), nil
+}
@@ -0,0 +1,62 @@
+package codegen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ddl "github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+func TestProto(t *testing.T) {
+	src, err := Proto(ProtoOptions{Package: "app.v1"}, parseSchema(t)...)
+	assert.NoError(t, err)
+
+	for _, line := range []string{
+		"syntax = \"proto3\";\n\npackage app.v1;\n\nimport \"google/protobuf/timestamp.proto\";\n",
+		"message Users {\n  int64 id = 1;\n  string name = 2;\n  optional string email = 3;\n  google.protobuf.Timestamp created_at = 4;\n  bool active = 5;\n  optional bytes avatar = 6;\n}",
+		"  // references users.id\n  int64 user_id = 1;\n",
+	} {
+		assert.Contains(t, string(src), line)
+	}
+}
+
+func TestProtoLock(t *testing.T) {
+	lock := NewProtoLock()
+	_, err := Proto(ProtoOptions{Lock: lock}, parseSchema(t)...)
+	assert.NoError(t, err)
+
+	var saved bytes.Buffer
+	assert.NoError(t, lock.Write(&saved))
+	lock, err = ReadProtoLock(&saved)
+	assert.NoError(t, err)
+
+	// Drop email and add a column before the others.
+	table, errCode := ddl.ParseTable(`CREATE TABLE users (
+ tenant INTEGER,
+ id INTEGER PRIMARY KEY AUTOINCREMENT,
+ name TEXT NOT NULL,
+ created_at DATETIME,
+ active BOOLEAN NOT NULL,
+ avatar BLOB
+);`, 0)
+	assert.Equal(t, ddl.ERROR_NONE, errCode)
+	src, err := Proto(ProtoOptions{Lock: lock}, table)
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "message Users {\n  reserved 3;\n  reserved \"email\";\n  optional int64 tenant = 7;\n  int64 id = 1;\n  string name = 2;\n")
+	assert.Equal(t, []int{3}, lock.Messages["Users"].Reserved)
+
+	// Numbers of removed columns are never reused, even when the name is.
+	table.Columns = append(table.Columns, ddl.Column{Name: "email", Type: "TEXT"})
+	src, err = Proto(ProtoOptions{Lock: lock}, table)
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "message Users {\n  reserved 3;\n  optional int64 tenant = 7;\n")
+	assert.Contains(t, string(src), "  optional string email = 8;\n")
+}
+
+func TestProtoName(t *testing.T) {
+	assert.Equal(t, "user_id", ProtoName("User_ID"))
+	assert.Equal(t, "first_name", ProtoName("first name"))
+	assert.Equal(t, "x2fa", ProtoName("2fa"))
+}
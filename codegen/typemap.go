@@ -0,0 +1,164 @@
+package codegen
+
+import (
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// Kind is the language-neutral type of a column, derived from its affinity
+// and, for NUMERIC columns, from its declared type name.
+type Kind int
+
+const (
+	KIND_INTEGER Kind = iota
+	KIND_REAL
+	KIND_NUMERIC
+	KIND_TEXT
+	KIND_BLOB
+	KIND_BOOL
+	KIND_TIME
+)
+
+// ColumnKind returns the kind of a column. Columns with INTEGER, REAL, TEXT
+// or BLOB affinity map to the kind of the same name; NUMERIC columns whose
+// declared type starts with BOOL are KIND_BOOL, those mentioning DATE or
+// TIME are KIND_TIME, and the rest KIND_NUMERIC.
+func ColumnKind(column *parser.Column) Kind {
+	switch column.Affinity() {
+	case parser.AFFINITY_INTEGER:
+		return KIND_INTEGER
+	case parser.AFFINITY_TEXT:
+		return KIND_TEXT
+	case parser.AFFINITY_REAL:
+		return KIND_REAL
+	case parser.AFFINITY_BLOB:
+		return KIND_BLOB
+	}
+	declared := strings.ToUpper(column.Type)
+	switch {
+	case strings.HasPrefix(declared, "BOOL"):
+		return KIND_BOOL
+	case strings.Contains(declared, "DATE"), strings.Contains(declared, "TIME"):
+		return KIND_TIME
+	}
+	return KIND_NUMERIC
+}
+
+// TypeRule overrides the type a generator chooses for the columns it
+// matches. Empty fields match anything; names and the declared type (without
+// its arguments) are compared case-insensitively. Type is written to the
+// generated code as is, and is used for non-null values: each generator
+// still applies its own representation of NULL.
+type TypeRule struct {
+	Table        string
+	Column       string
+	DeclaredType string
+	Type         string
+}
+
+func (r *TypeRule) matches(table *parser.Table, column *parser.Column) bool {
+	return (r.Table == "" || strings.EqualFold(r.Table, table.Name)) &&
+		(r.Column == "" || strings.EqualFold(r.Column, column.Name)) &&
+		(r.DeclaredType == "" || strings.EqualFold(r.DeclaredType, column.Type))
+}
+
+// TypeMapper maps columns to the types of one target language: the first
+// matching rule wins, otherwise the type registered for the column's kind.
+type TypeMapper struct {
+	Kinds map[Kind]string
+	Rules []TypeRule
+}
+
+func (m *TypeMapper) Map(table *parser.Table, column *parser.Column) string {
+	for i := range m.Rules {
+		if m.Rules[i].matches(table, column) {
+			return m.Rules[i].Type
+		}
+	}
+	return m.Kinds[ColumnKind(column)]
+}
+
+// GoTypes, TypeScriptTypes and ProtoTypes are the default kind mappings of
+// each generator.
+var (
+	GoTypes = map[Kind]string{
+		KIND_INTEGER: "int64",
+		KIND_REAL:    "float64",
+		KIND_NUMERIC: "float64",
+		KIND_TEXT:    "string",
+		KIND_BLOB:    "[]byte",
+		KIND_BOOL:    "bool",
+		KIND_TIME:    "time.Time",
+	}
+	TypeScriptTypes = map[Kind]string{
+		KIND_INTEGER: "number",
+		KIND_REAL:    "number",
+		KIND_NUMERIC: "number",
+		KIND_TEXT:    "string",
+		KIND_BLOB:    "Uint8Array",
+		KIND_BOOL:    "boolean",
+		KIND_TIME:    "string",
+	}
+	ProtoTypes = map[Kind]string{
+		KIND_INTEGER: "int64",
+		KIND_REAL:    "double",
+		KIND_NUMERIC: "double",
+		KIND_TEXT:    "string",
+		KIND_BLOB:    "bytes",
+		KIND_BOOL:    "bool",
+		KIND_TIME:    "google.protobuf.Timestamp",
+	}
+)
+
+// isNullable reports whether the column can hold NULL: it has no NOT NULL
+// constraint and is not the rowid alias or part of a WITHOUT ROWID key.
+func isNullable(table *parser.Table, column *parser.Column) bool {
+	if column.IsNotnull {
+		return false
+	}
+	for _, name := range table.PrimaryKey() {
+		if strings.EqualFold(name, column.Name) {
+			if table.IsWithoutRowid || (column.IsPrimaryKey && column.Affinity() == parser.AFFINITY_INTEGER) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// hasDefault reports whether SQLite fills the column in when an INSERT
+// omits it: it has a DEFAULT or is the rowid alias.
+func hasDefault(table *parser.Table, column *parser.Column) bool {
+	if column.DefaultExpr != "" {
+		return true
+	}
+	return column.IsPrimaryKey && !table.IsWithoutRowid && strings.EqualFold(column.Type, "INTEGER")
+}
+
+// references returns "table.column" for every foreign key the column is a
+// child column of.
+func references(table *parser.Table, column *parser.Column) []string {
+	var targets []string
+	if fk := column.ForeignKeyClause; fk != nil {
+		targets = append(targets, fkTarget(fk, 0))
+	}
+	for _, constraint := range table.Constraints {
+		if constraint.Type != parser.TABLECONSTRAINT_FOREIGNKEY || constraint.ForeignKeyClause == nil {
+			continue
+		}
+		for i, name := range constraint.ForeignKeyName {
+			if strings.EqualFold(name, column.Name) {
+				targets = append(targets, fkTarget(constraint.ForeignKeyClause, i))
+			}
+		}
+	}
+	return targets
+}
+
+func fkTarget(fk *parser.ForeignKey, i int) string {
+	if i < len(fk.ColumnName) {
+		return fk.Table + "." + fk.ColumnName[i]
+	}
+	return fk.Table
+}
@@ -0,0 +1,72 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"unicode"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+type TypeScriptOptions struct {
+	// Types overrides the TypeScriptTypes mapping for matching columns.
+	Types []TypeRule
+}
+
+// TypeScript returns TypeScript declarations with two interfaces per table:
+// one named after the table describing a row, where nullable columns are
+// "T | null", and one prefixed with New describing the values of an INSERT,
+// where columns that are nullable or filled in by SQLite are optional.
+func TypeScript(options TypeScriptOptions, tables ...*parser.Table) ([]byte, error) {
+	types := &TypeMapper{Kinds: TypeScriptTypes, Rules: options.Types}
+	var b bytes.Buffer
+	b.WriteString("// Code generated by sqlite-ddl. DO NOT EDIT.\n")
+
+	for _, table := range tables {
+		typeName := GoName(table.Name)
+		fmt.Fprintf(&b, "\n/** A row of table %s. */\n", table.Name)
+		fmt.Fprintf(&b, "export interface %s {\n", typeName)
+		for i := range table.Columns {
+			column := &table.Columns[i]
+			writeTSField(&b, table, column, types.Map(table, column), false)
+		}
+		b.WriteString("}\n")
+
+		fmt.Fprintf(&b, "\n/** The values of a row inserted into %s. */\n", table.Name)
+		fmt.Fprintf(&b, "export interface New%s {\n", typeName)
+		for i := range table.Columns {
+			column := &table.Columns[i]
+			optional := isNullable(table, column) || hasDefault(table, column)
+			writeTSField(&b, table, column, types.Map(table, column), optional)
+		}
+		b.WriteString("}\n")
+	}
+	return b.Bytes(), nil
+}
+
+func writeTSField(b *bytes.Buffer, table *parser.Table, column *parser.Column, typ string, optional bool) {
+	for _, target := range references(table, column) {
+		fmt.Fprintf(b, "  /** references %s */\n", target)
+	}
+	name := column.Name
+	if !isTSIdentifier(name) {
+		name = strconv.Quote(name)
+	}
+	if optional {
+		name += "?"
+	}
+	if isNullable(table, column) {
+		typ += " | null"
+	}
+	fmt.Fprintf(b, "  %s: %s;\n", name, typ)
+}
+
+func isTSIdentifier(name string) bool {
+	for i, r := range name {
+		if !(r == '_' || r == '$' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return false
+		}
+	}
+	return name != ""
+}
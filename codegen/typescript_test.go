@@ -0,0 +1,30 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeScript(t *testing.T) {
+	src, err := TypeScript(TypeScriptOptions{}, parseSchema(t)...)
+	assert.NoError(t, err)
+
+	for _, line := range []string{
+		"export interface Users {\n  id: number;\n  name: string;\n  email: string | null;\n  created_at: string | null;\n  active: boolean;\n  avatar: Uint8Array | null;\n}",
+		"export interface NewUsers {\n  id?: number;\n  name: string;\n  email?: string | null;\n",
+		"  /** references users.id */\n  user_id: number;\n  type: string;\n}",
+	} {
+		assert.Contains(t, string(src), line)
+	}
+}
+
+func TestTypeScriptRules(t *testing.T) {
+	src, err := TypeScript(TypeScriptOptions{Types: []TypeRule{
+		{DeclaredType: "DATETIME", Type: "Date"},
+		{Table: "memberships", Column: "type", Type: `"owner" | "member"`},
+	}}, parseSchema(t)...)
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "  created_at: Date | null;\n")
+	assert.Contains(t, string(src), "  type: \"owner\" | \"member\";\n")
+}
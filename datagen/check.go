@@ -0,0 +1,699 @@
+package datagen
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/codegen"
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// constrain narrows the values generated for the columns of ts to those
+// the simple terms of a CHECK constraint allow: the terms joined by AND
+// that compare a column or its length with constants, with BETWEEN or
+// comparison operators, or list its values with IN. The rows generated are
+// still evaluated against the whole constraint.
+func (ts *tableState) constrain(expr *parser.Expr) {
+	if expr.Kind == parser.EXPR_BINARY && expr.Op == "AND" {
+		ts.constrain(expr.Args[0])
+		ts.constrain(expr.Args[1])
+		return
+	}
+
+	switch expr.Kind {
+	case parser.EXPR_BINARY:
+		left, right, op := expr.Args[0], expr.Args[1], expr.Op
+		if c, length := ts.target(left); c != nil {
+			if value, ok := constant(right); ok {
+				c.restrict(length, op, value)
+			}
+			if op == "IS NOT" && right.Kind == parser.EXPR_LITERAL && strings.EqualFold(right.Value, "NULL") && !length {
+				c.notNull = true
+			}
+		} else if c, length := ts.target(right); c != nil {
+			if value, ok := constant(left); ok {
+				c.restrict(length, flip[op], value)
+			}
+		}
+	case parser.EXPR_BETWEEN:
+		if c, length := ts.target(expr.Args[0]); c != nil && expr.Op == "BETWEEN" {
+			lo, ok1 := constant(expr.Args[1])
+			hi, ok2 := constant(expr.Args[2])
+			if ok1 && ok2 {
+				c.restrict(length, ">=", lo)
+				c.restrict(length, "<=", hi)
+			}
+		}
+	case parser.EXPR_IN:
+		c, length := ts.target(expr.Args[0])
+		if c == nil || length || expr.Op != "IN" || expr.Select != "" || expr.Name != "" {
+			return
+		}
+		var values []interface{}
+		for _, arg := range expr.Args[1:] {
+			value, ok := constant(arg)
+			if !ok {
+				return
+			}
+			if value != nil {
+				values = append(values, applyAffinity(c.affinity, value))
+			}
+		}
+		c.allow(values)
+	case parser.EXPR_POSTFIX:
+		if c, length := ts.target(expr.Args[0]); c != nil && !length && expr.Op != "ISNULL" {
+			c.notNull = true
+		}
+	}
+}
+
+// flip maps a comparison operator to the one comparing its operands the
+// other way around.
+var flip = map[string]string{
+	"=": "=", "==": "==", "<>": "<>", "!=": "!=",
+	"<": ">", "<=": ">=", ">": "<", ">=": "<=",
+}
+
+// target returns the column of ts that expr is, or whose length it is.
+func (ts *tableState) target(expr *parser.Expr) (*columnPlan, bool) {
+	length := false
+	if expr.Kind == parser.EXPR_FUNCTION && strings.EqualFold(expr.Name, "length") && len(expr.Args) == 1 {
+		expr, length = expr.Args[0], true
+	}
+	if expr.Kind != parser.EXPR_COLUMN || expr.Table != "" && !strings.EqualFold(expr.Table, ts.table.Name) {
+		return nil, false
+	}
+	if i := ts.columnIndex(expr.Name); i >= 0 {
+		return ts.columns[i], length
+	}
+	return nil, false
+}
+
+// constant returns the value of an expression that uses no column.
+func constant(expr *parser.Expr) (interface{}, bool) {
+	usesColumns := false
+	expr.Walk(func(e *parser.Expr) bool {
+		usesColumns = usesColumns || e.Kind == parser.EXPR_COLUMN
+		return !usesColumns
+	})
+	if usesColumns {
+		return nil, false
+	}
+	return (&tableState{table: &parser.Table{}}).eval(expr, nil)
+}
+
+// restrict narrows the values of c, or of its length, to those for which
+// they compare with value by op.
+func (c *columnPlan) restrict(length bool, op string, value interface{}) {
+	if length {
+		n, ok := value.(int64)
+		if !ok {
+			return
+		}
+		switch op {
+		case ">":
+			c.minLen = max(c.minLen, int(n)+1)
+		case ">=":
+			c.minLen = max(c.minLen, int(n))
+		case "<":
+			c.setMaxLen(int(n) - 1)
+		case "<=":
+			c.setMaxLen(int(n))
+		case "=", "==":
+			c.minLen = max(c.minLen, int(n))
+			c.setMaxLen(int(n))
+		}
+		return
+	}
+
+	value = applyAffinity(c.affinity, value)
+	switch op {
+	case "=", "==":
+		c.allow([]interface{}{value})
+		return
+	case "<>", "!=":
+		if value == "" {
+			c.minLen = max(c.minLen, 1)
+		}
+		return
+	}
+	var f float64
+	switch v := value.(type) {
+	case int64:
+		f = float64(v)
+	case float64:
+		f = v
+	default:
+		return
+	}
+	if c.kind == codegen.KIND_TEXT || c.kind == codegen.KIND_TIME || c.kind == codegen.KIND_BLOB && c.column.Type != "" {
+		return
+	}
+	// Integer columns turn strict bounds into the next integer.
+	integer := c.kind == codegen.KIND_INTEGER || c.kind == codegen.KIND_BOOL || c.kind == codegen.KIND_NUMERIC && c.scale == 0
+	switch op {
+	case ">", ">=":
+		if integer && op == ">" {
+			f = math.Floor(f) + 1
+		} else if integer {
+			f = math.Ceil(f)
+		}
+		if !c.hasMin || f > c.min {
+			c.min, c.hasMin = f, true
+		}
+	case "<", "<=":
+		if integer && op == "<" {
+			f = math.Ceil(f) - 1
+		} else if integer {
+			f = math.Floor(f)
+		}
+		if !c.hasMax || f < c.max {
+			c.max, c.hasMax = f, true
+		}
+	}
+}
+
+func (c *columnPlan) setMaxLen(n int) {
+	if n < 0 {
+		n = 0
+	}
+	if c.maxLen == 0 || n < c.maxLen {
+		c.maxLen = n
+	}
+}
+
+// allow limits the values of c to values, or to those also in values when
+// they are already limited.
+func (c *columnPlan) allow(values []interface{}) {
+	if c.values == nil {
+		c.values = append([]interface{}{}, values...)
+		return
+	}
+	var kept []interface{}
+	for _, value := range c.values {
+		for _, v := range values {
+			if keyValue(value, "") == keyValue(v, "") {
+				kept = append(kept, value)
+				break
+			}
+		}
+	}
+	c.values = append([]interface{}{}, kept...)
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// satisfies reports whether row satisfies a CHECK constraint: like in
+// SQLite, unless the constraint is false, or when it cannot be evaluated.
+func (ts *tableState) satisfies(expr *parser.Expr, row []interface{}) bool {
+	value, ok := ts.eval(expr, row)
+	if !ok {
+		return true
+	}
+	t, null := truth(value)
+	return t || null
+}
+
+// eval returns the value of expr for row, computed like SQLite does, and
+// false when expr uses an operator, function or column eval does not know.
+// Booleans are the integers 1 and 0.
+func (ts *tableState) eval(expr *parser.Expr, row []interface{}) (interface{}, bool) {
+	switch expr.Kind {
+	case parser.EXPR_LITERAL:
+		return literal(expr.Value)
+	case parser.EXPR_COLUMN:
+		if expr.Table != "" && !strings.EqualFold(expr.Table, ts.table.Name) {
+			return nil, false
+		}
+		i := ts.columnIndex(expr.Name)
+		if i < 0 {
+			return nil, false
+		}
+		return row[i], true
+	case parser.EXPR_COLLATE:
+		return ts.eval(expr.Args[0], row)
+	case parser.EXPR_UNARY:
+		value, ok := ts.eval(expr.Args[0], row)
+		if !ok || value == nil {
+			return nil, ok
+		}
+		switch expr.Op {
+		case "-":
+			return arithmetic("-", int64(0), value), true
+		case "+":
+			return value, true
+		case "NOT":
+			t, _ := truth(value)
+			return boolean(!t), true
+		case "~":
+			if n, ok := toNumber(value).(int64); ok {
+				return ^n, true
+			}
+			return ^int64(toNumber(value).(float64)), true
+		}
+	case parser.EXPR_POSTFIX:
+		value, ok := ts.eval(expr.Args[0], row)
+		if !ok {
+			return nil, false
+		}
+		if expr.Op == "ISNULL" {
+			return boolean(value == nil), true
+		}
+		return boolean(value != nil), true
+	case parser.EXPR_BINARY:
+		return ts.evalBinary(expr, row)
+	case parser.EXPR_BETWEEN:
+		value, ok := ts.eval(expr.Args[0], row)
+		lo, ok1 := ts.eval(expr.Args[1], row)
+		hi, ok2 := ts.eval(expr.Args[2], row)
+		if !ok || !ok1 || !ok2 {
+			return nil, false
+		}
+		above := ts.compare(">=", expr.Args[0], expr.Args[1], value, lo)
+		below := ts.compare("<=", expr.Args[0], expr.Args[2], value, hi)
+		result := and(above, below)
+		if expr.Op != "BETWEEN" {
+			result = not(result)
+		}
+		return result, true
+	case parser.EXPR_IN:
+		if expr.Select != "" || expr.Name != "" {
+			return nil, false
+		}
+		value, ok := ts.eval(expr.Args[0], row)
+		if !ok {
+			return nil, false
+		}
+		var result interface{} = int64(0)
+		for _, arg := range expr.Args[1:] {
+			v, ok := ts.eval(arg, row)
+			if !ok {
+				return nil, false
+			}
+			switch equal := ts.compare("=", expr.Args[0], arg, value, v); {
+			case equal == nil:
+				result = nil
+			case equal == int64(1):
+				result = int64(1)
+			}
+			if result == int64(1) {
+				break
+			}
+		}
+		if value == nil && len(expr.Args) > 1 {
+			result = nil
+		}
+		if expr.Op != "IN" {
+			result = not(result)
+		}
+		return result, true
+	case parser.EXPR_FUNCTION:
+		return ts.evalFunction(expr, row)
+	}
+	return nil, false
+}
+
+func (ts *tableState) evalBinary(expr *parser.Expr, row []interface{}) (interface{}, bool) {
+	left, ok1 := ts.eval(expr.Args[0], row)
+	right, ok2 := ts.eval(expr.Args[1], row)
+	if !ok1 || !ok2 {
+		return nil, false
+	}
+	switch expr.Op {
+	case "AND":
+		return and(left, right), true
+	case "OR":
+		return not(and(not(left), not(right))), true
+	case "=", "==", "<>", "!=", "<", "<=", ">", ">=":
+		return ts.compare(expr.Op, expr.Args[0], expr.Args[1], left, right), true
+	case "IS", "IS NOT":
+		var same bool
+		if left == nil || right == nil {
+			same = left == nil && right == nil
+		} else {
+			same = ts.compare("=", expr.Args[0], expr.Args[1], left, right) == int64(1)
+		}
+		return boolean(same == (expr.Op == "IS")), true
+	case "+", "-", "*", "/", "%":
+		if left == nil || right == nil {
+			return nil, true
+		}
+		return arithmetic(expr.Op, left, right), true
+	case "||":
+		if left == nil || right == nil {
+			return nil, true
+		}
+		return toText(left) + toText(right), true
+	case "LIKE", "NOT LIKE":
+		if len(expr.Args) > 2 {
+			return nil, false
+		}
+		if left == nil || right == nil {
+			return nil, true
+		}
+		return boolean(like(asciiLower(toText(right)), asciiLower(toText(left))) == (expr.Op == "LIKE")), true
+	}
+	return nil, false
+}
+
+func (ts *tableState) evalFunction(expr *parser.Expr, row []interface{}) (interface{}, bool) {
+	if expr.Star || expr.Distinct {
+		return nil, false
+	}
+	args := make([]interface{}, len(expr.Args))
+	for i, arg := range expr.Args {
+		value, ok := ts.eval(arg, row)
+		if !ok {
+			return nil, false
+		}
+		args[i] = value
+	}
+	name := strings.ToLower(expr.Name)
+	switch {
+	case (name == "coalesce" || name == "ifnull") && len(args) >= 2:
+		for _, arg := range args {
+			if arg != nil {
+				return arg, true
+			}
+		}
+		return nil, true
+	case len(args) != 1:
+		return nil, false
+	case name == "typeof":
+		switch args[0].(type) {
+		case nil:
+			return "null", true
+		case int64:
+			return "integer", true
+		case float64:
+			return "real", true
+		case string:
+			return "text", true
+		}
+		return "blob", true
+	case args[0] == nil:
+		switch name {
+		case "length", "lower", "upper", "abs", "trim":
+			return nil, true
+		}
+	case name == "length":
+		switch v := args[0].(type) {
+		case []byte:
+			return int64(len(v)), true
+		case string:
+			return int64(utf8.RuneCountInString(v)), true
+		}
+		return int64(utf8.RuneCountInString(toText(args[0]))), true
+	case name == "lower":
+		return asciiLower(toText(args[0])), true
+	case name == "upper":
+		return asciiUpper(toText(args[0])), true
+	case name == "trim":
+		return strings.Trim(toText(args[0]), " "), true
+	case name == "abs":
+		switch v := toNumber(args[0]).(type) {
+		case int64:
+			if v < 0 {
+				return -v, true
+			}
+			return v, true
+		case float64:
+			return math.Abs(v), true
+		}
+	}
+	return nil, false
+}
+
+// compare compares two values the way SQLite compares the operands left
+// and right with op, after applying their affinities, and returns NULL
+// when one of them is NULL.
+func (ts *tableState) compare(op string, left, right *parser.Expr, a, b interface{}) interface{} {
+	if a == nil || b == nil {
+		return nil
+	}
+	la, lok := ts.affinity(left)
+	ra, rok := ts.affinity(right)
+	switch {
+	case lok && numeric(la) && (!rok || !numeric(ra)):
+		b = applyAffinity(parser.AFFINITY_NUMERIC, b)
+	case rok && numeric(ra) && (!lok || !numeric(la)):
+		a = applyAffinity(parser.AFFINITY_NUMERIC, a)
+	case lok && la == parser.AFFINITY_TEXT && !rok:
+		b = applyAffinity(parser.AFFINITY_TEXT, b)
+	case rok && ra == parser.AFFINITY_TEXT && !lok:
+		a = applyAffinity(parser.AFFINITY_TEXT, a)
+	}
+
+	c := order(a, b)
+	var result bool
+	switch op {
+	case "=", "==":
+		result = c == 0
+	case "<>", "!=":
+		result = c != 0
+	case "<":
+		result = c < 0
+	case "<=":
+		result = c <= 0
+	case ">":
+		result = c > 0
+	case ">=":
+		result = c >= 0
+	}
+	return boolean(result)
+}
+
+// affinity returns the affinity of an expression, which only columns and
+// their COLLATE expressions have.
+func (ts *tableState) affinity(expr *parser.Expr) (parser.Affinity, bool) {
+	for expr.Kind == parser.EXPR_COLLATE {
+		expr = expr.Args[0]
+	}
+	if expr.Kind != parser.EXPR_COLUMN {
+		return 0, false
+	}
+	if i := ts.columnIndex(expr.Name); i >= 0 {
+		return ts.columns[i].affinity, true
+	}
+	return 0, false
+}
+
+func numeric(affinity parser.Affinity) bool {
+	return affinity == parser.AFFINITY_INTEGER || affinity == parser.AFFINITY_REAL || affinity == parser.AFFINITY_NUMERIC
+}
+
+// order compares two values that are not NULL in SQLite's order: numbers
+// before text before blobs, and text compared byte by byte.
+func order(a, b interface{}) int {
+	class := func(v interface{}) int {
+		switch v.(type) {
+		case int64, float64:
+			return 0
+		case string:
+			return 1
+		}
+		return 2
+	}
+	if ca, cb := class(a), class(b); ca != cb {
+		return ca - cb
+	}
+	switch v := a.(type) {
+	case int64:
+		if w, ok := b.(int64); ok {
+			switch {
+			case v < w:
+				return -1
+			case v > w:
+				return 1
+			}
+			return 0
+		}
+		return compareFloats(float64(v), b.(float64))
+	case float64:
+		if w, ok := b.(int64); ok {
+			return compareFloats(v, float64(w))
+		}
+		return compareFloats(v, b.(float64))
+	case string:
+		return strings.Compare(v, b.(string))
+	}
+	return bytes.Compare(a.([]byte), b.([]byte))
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// arithmetic applies an arithmetic operator to two values that are not
+// NULL, converting text to numbers like SQLite does. Division by zero is
+// NULL.
+func arithmetic(op string, a, b interface{}) interface{} {
+	x, y := toNumber(a), toNumber(b)
+	i, iok := x.(int64)
+	j, jok := y.(int64)
+	if iok && jok {
+		switch op {
+		case "+":
+			return i + j
+		case "-":
+			return i - j
+		case "*":
+			return i * j
+		case "/", "%":
+			if j == 0 {
+				return nil
+			}
+			if op == "/" {
+				return i / j
+			}
+			return i % j
+		}
+	}
+	f, g := toFloat(x), toFloat(y)
+	switch op {
+	case "+":
+		return f + g
+	case "-":
+		return f - g
+	case "*":
+		return f * g
+	case "/":
+		if g == 0 {
+			return nil
+		}
+		return f / g
+	}
+	if int64(g) == 0 {
+		return nil
+	}
+	return float64(int64(f) % int64(g))
+}
+
+// toNumber converts a value that is not NULL to an int64 or a float64,
+// reading the longest number at the start of text, as SQLite does.
+func toNumber(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64, float64:
+		return v
+	case []byte:
+		value = string(v)
+	}
+	s := strings.TrimSpace(value.(string))
+	for end := len(s); end > 0; end-- {
+		if n, ok := parseNumber(s[:end]); ok {
+			return n
+		}
+	}
+	return int64(0)
+}
+
+func toFloat(number interface{}) float64 {
+	if n, ok := number.(int64); ok {
+		return float64(n)
+	}
+	return number.(float64)
+}
+
+// truth returns whether a value is true, and whether it is NULL.
+func truth(value interface{}) (bool, bool) {
+	if value == nil {
+		return false, true
+	}
+	return toFloat(toNumber(value)) != 0, false
+}
+
+func boolean(b bool) interface{} {
+	if b {
+		return int64(1)
+	}
+	return int64(0)
+}
+
+// and and not implement SQL's three-valued logic.
+func and(a, b interface{}) interface{} {
+	ta, na := truth(a)
+	tb, nb := truth(b)
+	switch {
+	case !na && !ta, !nb && !tb:
+		return int64(0)
+	case na || nb:
+		return nil
+	}
+	return int64(1)
+}
+
+func not(a interface{}) interface{} {
+	t, null := truth(a)
+	if null {
+		return nil
+	}
+	return boolean(!t)
+}
+
+// literal returns the value of a literal as written in SQL.
+func literal(text string) (interface{}, bool) {
+	switch upper := strings.ToUpper(text); {
+	case upper == "NULL":
+		return nil, true
+	case upper == "TRUE":
+		return int64(1), true
+	case upper == "FALSE":
+		return int64(0), true
+	case strings.HasPrefix(upper, "CURRENT_"):
+		return nil, false
+	case strings.HasPrefix(text, "'"):
+		return parser.Token{Kind: parser.TOKEN_STRING, Text: text}.Value(), true
+	case strings.HasPrefix(upper, "X'"):
+		b, err := hex.DecodeString(text[2 : len(text)-1])
+		return b, err == nil
+	case strings.HasPrefix(upper, "0X"):
+		n, err := strconv.ParseUint(strings.ReplaceAll(text[2:], "_", ""), 16, 64)
+		return int64(n), err == nil
+	}
+	return parseNumber(strings.ReplaceAll(text, "_", ""))
+}
+
+// like reports whether s matches the LIKE pattern, both folded to lower
+// case: % matches any text and _ any character.
+func like(pattern, s string) bool {
+	if pattern == "" {
+		return s == ""
+	}
+	r, size := utf8.DecodeRuneInString(pattern)
+	switch r {
+	case '%':
+		for i := 0; ; {
+			if like(pattern[size:], s[i:]) {
+				return true
+			}
+			if i == len(s) {
+				return false
+			}
+			_, n := utf8.DecodeRuneInString(s[i:])
+			i += n
+		}
+	case '_':
+		if s == "" {
+			return false
+		}
+		_, n := utf8.DecodeRuneInString(s)
+		return like(pattern[size:], s[n:])
+	}
+	c, n := utf8.DecodeRuneInString(s)
+	return s != "" && c == r && like(pattern[size:], s[n:])
+}
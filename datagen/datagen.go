@@ -0,0 +1,566 @@
+// Package datagen generates rows of test data for the tables of a parsed
+// schema. The rows are the same for the same seed, and they satisfy the
+// constraints of their tables: column affinity and length, NOT NULL,
+// primary keys, UNIQUE constraints and unique indexes, foreign keys, which
+// reference rows generated before them in dependency order, and the CHECK
+// constraints it can evaluate.
+package datagen
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/graph"
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// Options configures Generate.
+type Options struct {
+	// Seed seeds every random choice: the same seed, schema and options
+	// generate the same rows.
+	Seed int64
+	// Rows is the number of rows of every table; 10 if zero.
+	Rows int
+	// RowCounts overrides Rows for the tables it names, compared
+	// case-insensitively.
+	RowCounts map[string]int
+}
+
+// Table holds the rows generated for a table, with a value for each of
+// Columns in every row. A value is nil for NULL, or an int64, float64,
+// string or []byte, following the affinity of its column.
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// maxAttempts is the number of times a row is generated again when it
+// breaks a constraint, before Generate gives up.
+const maxAttempts = 1000
+
+// Generate returns rows for the tables of schema, in an order their rows can
+// be inserted in. Within a foreign key cycle, the deferred foreign keys may
+// reference rows of tables that come later, so such rows must be inserted in
+// one transaction. CHECK constraints and the expressions of unique indexes
+// that use functions or operators it does not know are not enforced.
+func Generate(options Options, schema *parser.Schema) ([]*Table, error) {
+	if options.Rows == 0 {
+		options.Rows = 10
+	}
+	g := &generator{rand: rand.New(rand.NewSource(options.Seed)), tables: map[string]*tableState{}}
+
+	var order []*tableState
+	for _, node := range graph.New(schema).Order() {
+		if node.Kind != graph.NODE_TABLE {
+			continue
+		}
+		ts := newTableState(schema.Table(node.Name))
+		ts.count = options.Rows
+		for name, count := range options.RowCounts {
+			if strings.EqualFold(name, ts.table.Name) {
+				ts.count = count
+			}
+		}
+		g.tables[strings.ToLower(ts.table.Name)] = ts
+		order = append(order, ts)
+	}
+	for _, ts := range order {
+		if err := g.plan(ts, schema); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, ts := range order {
+		for i := 0; i < ts.count; i++ {
+			if err := g.addRow(ts); err != nil {
+				return nil, err
+			}
+		}
+		ts.done = true
+	}
+	for _, ts := range order {
+		for _, p := range ts.pending {
+			if err := g.fill(ts, p); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	tables := make([]*Table, len(order))
+	for i, ts := range order {
+		tables[i] = ts.data
+	}
+	return tables, nil
+}
+
+type generator struct {
+	rand   *rand.Rand
+	tables map[string]*tableState
+}
+
+// tableState is a table being generated: how to generate its columns, the
+// constraints its rows must satisfy and the rows so far.
+type tableState struct {
+	table   *parser.Table
+	data    *Table
+	count   int
+	columns []*columnPlan
+	keys    []*uniqueKey
+	checks  []*check
+	fks     []*foreignKey
+	// rowid is the last value of the rowid alias, if the table has one.
+	rowid int64
+	done  bool
+	// pending lists the deferred foreign keys of the rows whose parent
+	// table had no rows yet, and unassigned, by row, their columns.
+	pending    []pendingKey
+	unassigned map[int][]bool
+}
+
+// uniqueKey is a primary key, a UNIQUE constraint or a unique index. A part
+// is a column, or an expression of an index. Rows are only indexed when
+// where, if any, is true for them.
+type uniqueKey struct {
+	parts   []keyPart
+	where   *parser.Expr
+	columns []int
+	seen    map[string]bool
+}
+
+type keyPart struct {
+	column    int
+	expr      *parser.Expr
+	collation string
+}
+
+// check is a CHECK constraint with the columns it uses.
+type check struct {
+	expr    *parser.Expr
+	columns []int
+}
+
+// foreignKey is a foreign key of a table, whose parent is nil when the
+// schema does not have the parent table.
+type foreignKey struct {
+	columns       []int
+	parentName    string
+	parent        *tableState
+	parentColumns []int
+	deferred      bool
+	nullable      bool
+}
+
+type pendingKey struct {
+	row int
+	fk  *foreignKey
+}
+
+func newTableState(table *parser.Table) *tableState {
+	ts := &tableState{table: table, data: &Table{Name: table.Name}, unassigned: map[int][]bool{}}
+	for i := range table.Columns {
+		ts.data.Columns = append(ts.data.Columns, table.Columns[i].Name)
+		ts.columns = append(ts.columns, newColumnPlan(table, i))
+	}
+	return ts
+}
+
+func (ts *tableState) columnIndex(name string) int {
+	for i := range ts.table.Columns {
+		if strings.EqualFold(ts.table.Columns[i].Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// plan collects the constraints of the table of ts.
+func (g *generator) plan(ts *tableState, schema *parser.Schema) error {
+	table := ts.table
+	for i := range table.Columns {
+		column := &table.Columns[i]
+		if column.IsPrimaryKey || column.IsUnique {
+			ts.addKey([]parser.IdxColumn{{Name: column.Name}}, nil)
+		}
+		exprs := []string{column.CheckExpr}
+		if column.Constraints != nil {
+			exprs = nil
+			for _, constraint := range column.Constraints {
+				if constraint.Type == parser.COLUMNCONSTRAINT_CHECK {
+					exprs = append(exprs, constraint.Expr)
+				}
+			}
+		}
+		for _, expr := range exprs {
+			ts.addCheck(expr)
+		}
+	}
+	for _, constraint := range table.Constraints {
+		switch constraint.Type {
+		case parser.TABLECONSTRAINT_PRIMARYKEY, parser.TABLECONSTRAINT_UNIQUE:
+			ts.addKey(constraint.IndexedColumns, nil)
+		case parser.TABLECONSTRAINT_CHECK:
+			ts.addCheck(constraint.CheckExpr)
+		}
+	}
+	for _, index := range schema.Indexes {
+		if !index.IsUnique || !strings.EqualFold(index.Table, table.Name) {
+			continue
+		}
+		var where *parser.Expr
+		if index.Where != "" {
+			if expr, errCode := parser.ParseExpr(index.Where); errCode == parser.ERROR_NONE {
+				where = expr
+			}
+		}
+		ts.addKey(index.Columns, where)
+	}
+
+	for _, name := range table.PrimaryKey() {
+		if i := ts.columnIndex(name); i >= 0 {
+			ts.columns[i].notNull = true
+		}
+	}
+	for _, key := range ts.keys {
+		if len(key.parts) == 1 && key.parts[0].column >= 0 && key.where == nil {
+			ts.columns[key.parts[0].column].unique = true
+		}
+	}
+
+	for _, fk := range table.ForeignKeys() {
+		key := &foreignKey{
+			parentName: fk.Clause.Table,
+			parent:     g.tables[strings.ToLower(fk.Clause.Table)],
+			deferred:   fk.Clause.Deferrable == parser.DEFTYPE_DEFERRABLE_INITIALLY_DEFERRED,
+		}
+		for _, name := range fk.Columns {
+			i := ts.columnIndex(name)
+			if i < 0 {
+				return fmt.Errorf("datagen: table %s: no such column %s in foreign key", table.Name, name)
+			}
+			key.columns = append(key.columns, i)
+			ts.columns[i].foreign = true
+			if !ts.columns[i].notNull {
+				key.nullable = true
+			}
+		}
+		if key.parent != nil {
+			names := fk.Clause.ColumnName
+			if len(names) == 0 {
+				names = key.parent.table.PrimaryKey()
+				if len(names) == 0 {
+					return fmt.Errorf("datagen: table %s: foreign key (%s) references %s, which has no primary key",
+						table.Name, strings.Join(fk.Columns, ", "), key.parent.table.Name)
+				}
+			}
+			for _, name := range names {
+				i := key.parent.columnIndex(name)
+				if i < 0 {
+					return fmt.Errorf("datagen: table %s: foreign key references no such column %s.%s", table.Name, key.parent.table.Name, name)
+				}
+				key.parentColumns = append(key.parentColumns, i)
+			}
+			if len(key.parentColumns) != len(key.columns) {
+				return fmt.Errorf("datagen: table %s: foreign key (%s) does not match the columns it references in %s",
+					table.Name, strings.Join(fk.Columns, ", "), key.parent.table.Name)
+			}
+		}
+		ts.fks = append(ts.fks, key)
+	}
+	return nil
+}
+
+func (ts *tableState) addKey(columns []parser.IdxColumn, where *parser.Expr) {
+	key := &uniqueKey{where: where, seen: map[string]bool{}}
+	for _, column := range columns {
+		part := keyPart{column: -1, collation: column.CollateName}
+		if column.Expr != nil {
+			part.expr = column.Expr
+		} else if part.column = ts.columnIndex(column.Name); part.column < 0 {
+			return
+		} else if part.collation == "" {
+			part.collation = ts.table.Columns[part.column].CollateName
+		}
+		key.parts = append(key.parts, part)
+		key.columns = append(key.columns, ts.exprColumns(part.expr)...)
+		if part.column >= 0 {
+			key.columns = append(key.columns, part.column)
+		}
+	}
+	key.columns = append(key.columns, ts.exprColumns(where)...)
+	ts.keys = append(ts.keys, key)
+}
+
+func (ts *tableState) addCheck(text string) {
+	if text == "" {
+		return
+	}
+	expr, errCode := parser.ParseExpr(text)
+	if errCode != parser.ERROR_NONE {
+		return
+	}
+	ts.checks = append(ts.checks, &check{expr, ts.exprColumns(expr)})
+	ts.constrain(expr)
+}
+
+// exprColumns returns the columns of the table of ts that expr uses.
+func (ts *tableState) exprColumns(expr *parser.Expr) []int {
+	var columns []int
+	expr.Walk(func(e *parser.Expr) bool {
+		if e.Kind == parser.EXPR_COLUMN {
+			if i := ts.columnIndex(e.Name); i >= 0 {
+				columns = append(columns, i)
+			}
+		}
+		return true
+	})
+	return columns
+}
+
+// addRow generates a row of ts that satisfies its constraints.
+func (g *generator) addRow(ts *tableState) error {
+	n := len(ts.data.Rows)
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		row := make([]interface{}, len(ts.columns))
+		unassigned := make([]bool, len(ts.columns))
+		for i, c := range ts.columns {
+			if !c.foreign {
+				row[i] = g.value(ts, c)
+			}
+		}
+
+		var pending []*foreignKey
+		retry := false
+		for _, fk := range ts.fks {
+			if fk.parent != nil && fk.parent != ts && !fk.parent.done && fk.deferred {
+				pending = append(pending, fk)
+				for _, i := range fk.columns {
+					unassigned[i] = true
+				}
+				continue
+			}
+			ok, err := g.reference(ts, fk, row, unassigned)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				retry = true
+				break
+			}
+		}
+		if retry || !ts.accepts(row, unassigned, nil) {
+			continue
+		}
+
+		ts.register(row, unassigned, nil)
+		ts.data.Rows = append(ts.data.Rows, row)
+		for i, c := range ts.columns {
+			if c.rowid && row[i] != nil {
+				ts.rowid = row[i].(int64)
+			}
+		}
+		if len(pending) > 0 {
+			ts.unassigned[n] = unassigned
+			for _, fk := range pending {
+				ts.pending = append(ts.pending, pendingKey{n, fk})
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("datagen: table %s: cannot generate row %d satisfying its constraints in %d attempts", ts.table.Name, n+1, maxAttempts)
+}
+
+// fill assigns the columns of a deferred foreign key of a row generated
+// before the rows of its parent table.
+func (g *generator) fill(ts *tableState, p pendingKey) error {
+	row, unassigned := ts.data.Rows[p.row], ts.unassigned[p.row]
+	for i := range unassigned {
+		unassigned[i] = false
+	}
+	for _, other := range ts.pending {
+		if other.row == p.row && other.fk != p.fk {
+			for _, i := range other.fk.columns {
+				if row[i] == nil {
+					unassigned[i] = true
+				}
+			}
+		}
+	}
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		candidate := append([]interface{}(nil), row...)
+		for _, i := range p.fk.columns {
+			candidate[i] = nil
+		}
+		ok, err := g.reference(ts, p.fk, candidate, unassigned)
+		if err != nil {
+			return err
+		}
+		if ok && ts.accepts(candidate, unassigned, p.fk.columns) {
+			ts.register(candidate, unassigned, p.fk.columns)
+			copy(row, candidate)
+			return nil
+		}
+	}
+	return fmt.Errorf("datagen: table %s: cannot reference %s from row %d in %d attempts", ts.table.Name, p.fk.parentName, p.row+1, maxAttempts)
+}
+
+// reference assigns the columns of fk in row from a row of its parent
+// table, or NULL. It reports false when no parent row fits the columns of
+// row assigned by other foreign keys, and an error when the foreign key
+// cannot be satisfied at all.
+func (g *generator) reference(ts *tableState, fk *foreignKey, row []interface{}, unassigned []bool) (bool, error) {
+	columns := make([]string, len(fk.columns))
+	for i, column := range fk.columns {
+		columns[i] = ts.table.Columns[column].Name
+	}
+	describe := func() string {
+		return fmt.Sprintf("datagen: table %s: foreign key (%s) references %s", ts.table.Name, strings.Join(columns, ", "), fk.parentName)
+	}
+
+	if fk.nullable && (fk.parent == nil || g.rand.Intn(10) == 0) {
+		for _, i := range fk.columns {
+			row[i] = nil
+		}
+		return true, nil
+	}
+	switch {
+	case fk.parent == nil:
+		return false, fmt.Errorf("%s, which is not in the schema", describe())
+	case fk.parent != ts && !fk.parent.done && !fk.nullable:
+		return false, fmt.Errorf("%s, whose rows are generated later: make the foreign key DEFERRABLE INITIALLY DEFERRED or its columns nullable", describe())
+	}
+
+	var candidates [][]interface{}
+	parentRows := fk.parent.data.Rows
+	if fk.parent != ts && !fk.parent.done {
+		parentRows = nil
+	}
+	for _, parentRow := range parentRows {
+		if values, ok := fk.match(row, unassigned, parentRow); ok {
+			candidates = append(candidates, values)
+		}
+	}
+	// The first row of a table referencing itself can only reference
+	// itself.
+	if fk.parent == ts && len(candidates) == 0 && !fk.nullable {
+		if values, ok := fk.match(row, unassigned, row); ok {
+			candidates = append(candidates, values)
+		}
+	}
+	if len(candidates) == 0 {
+		switch {
+		case fk.nullable:
+			for _, i := range fk.columns {
+				row[i] = nil
+			}
+			return true, nil
+		case len(parentRows) == 0 && fk.parent != ts:
+			return false, fmt.Errorf("%s, which has no rows", describe())
+		}
+		return false, nil
+	}
+	values := candidates[g.rand.Intn(len(candidates))]
+	for i, column := range fk.columns {
+		row[column] = values[i]
+	}
+	return true, nil
+}
+
+// match returns the values of the parent columns of fk in parentRow, and
+// whether they can be referenced from row: none is NULL, and those of the
+// columns of row already assigned by other foreign keys are equal.
+func (fk *foreignKey) match(row []interface{}, unassigned []bool, parentRow []interface{}) ([]interface{}, bool) {
+	values := make([]interface{}, len(fk.columns))
+	for i, column := range fk.columns {
+		value := parentRow[fk.parentColumns[i]]
+		if value == nil {
+			return nil, false
+		}
+		if row[column] != nil && !unassigned[column] && keyValue(row[column], "") != keyValue(value, "") {
+			return nil, false
+		}
+		values[i] = value
+	}
+	return values, true
+}
+
+// ready reports whether none of columns is unassigned and, when changed is
+// not nil, whether one of them is among changed.
+func ready(columns []int, unassigned []bool, changed []int) bool {
+	found := changed == nil
+	for _, column := range columns {
+		if unassigned[column] {
+			return false
+		}
+		for _, c := range changed {
+			if c == column {
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+// accepts reports whether row satisfies the checks and unique keys of ts
+// that only use assigned columns, limited to those using one of changed
+// when it is not nil.
+func (ts *tableState) accepts(row []interface{}, unassigned []bool, changed []int) bool {
+	for _, check := range ts.checks {
+		if ready(check.columns, unassigned, changed) && !ts.satisfies(check.expr, row) {
+			return false
+		}
+	}
+	for _, key := range ts.keys {
+		if !ready(key.columns, unassigned, changed) {
+			continue
+		}
+		if k, ok := ts.key(key, row); ok && key.seen[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// register records the keys of row accepted by accepts.
+func (ts *tableState) register(row []interface{}, unassigned []bool, changed []int) {
+	for _, key := range ts.keys {
+		if !ready(key.columns, unassigned, changed) {
+			continue
+		}
+		if k, ok := ts.key(key, row); ok {
+			key.seen[k] = true
+		}
+	}
+}
+
+// key returns the value of key for row, and false when the row is not
+// indexed: a part is NULL, or the WHERE clause of the index is not true.
+func (ts *tableState) key(key *uniqueKey, row []interface{}) (string, bool) {
+	if key.where != nil {
+		if value, ok := ts.eval(key.where, row); ok {
+			if t, null := truth(value); !t || null {
+				return "", false
+			}
+		}
+	}
+	var b strings.Builder
+	for _, part := range key.parts {
+		var value interface{}
+		if part.expr != nil {
+			v, ok := ts.eval(part.expr, row)
+			if !ok {
+				return "", false
+			}
+			value = v
+		} else {
+			value = row[part.column]
+		}
+		if value == nil {
+			return "", false
+		}
+		b.WriteString(keyValue(value, part.collation))
+		b.WriteByte(0)
+	}
+	return b.String(), true
+}
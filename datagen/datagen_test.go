@@ -0,0 +1,195 @@
+package datagen
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+const schema = `
+CREATE TABLE orders (
+  id INTEGER PRIMARY KEY,
+  customer_id INTEGER NOT NULL REFERENCES customers (id),
+  status TEXT NOT NULL DEFAULT 'new' CHECK (status IN ('new', 'paid', 'shipped')),
+  total NUMERIC(8, 2) NOT NULL CHECK (total BETWEEN 1 AND 500),
+  placed_at DATETIME,
+  discount REAL CHECK (discount >= 0 AND discount < total)
+);
+CREATE TABLE customers (
+  id INTEGER PRIMARY KEY,
+  email VARCHAR(30) NOT NULL UNIQUE COLLATE NOCASE,
+  name TEXT CHECK (length(name) >= 3),
+  age INTEGER CHECK (age > 17),
+  referrer_id INTEGER REFERENCES customers (id)
+);
+CREATE TABLE order_items (
+  order_id INTEGER NOT NULL REFERENCES orders,
+  line INTEGER NOT NULL CHECK (line BETWEEN 1 AND 3),
+  code CHAR(4) NOT NULL,
+  PRIMARY KEY (order_id, line)
+) WITHOUT ROWID;
+CREATE UNIQUE INDEX order_items_code ON order_items (order_id, code);
+
+CREATE TABLE teams (id INTEGER PRIMARY KEY, captain_id INTEGER NOT NULL REFERENCES players DEFERRABLE INITIALLY DEFERRED);
+CREATE TABLE players (id INTEGER PRIMARY KEY, team_id INTEGER NOT NULL REFERENCES teams);
+`
+
+func parseSchema(t *testing.T, sql string) *parser.Schema {
+	s, errCode := parser.ParseSchema(sql)
+	if !assert.Equal(t, parser.ERROR_NONE, errCode) {
+		t.FailNow()
+	}
+	return s
+}
+
+// load runs the schema and the INSERT statements of tables in one
+// transaction of a new in-memory database with foreign keys on.
+func load(t *testing.T, schema string, tables []*Table) *sql.DB {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+
+	var inserts bytes.Buffer
+	assert.NoError(t, WriteSQL(&inserts, tables))
+	statements := "PRAGMA foreign_keys = ON;\n" + schema + "BEGIN;\n" + inserts.String() + "COMMIT;\n"
+	for _, statement := range parser.SplitStatements(statements) {
+		if _, err := db.ExecContext(context.Background(), statement.Text); err != nil {
+			t.Fatalf("%s: %v", statement.Text, err)
+		}
+	}
+	return db
+}
+
+func TestGenerate(t *testing.T) {
+	tables, err := Generate(Options{Seed: 1, RowCounts: map[string]int{"Order_Items": 25}}, parseSchema(t, schema))
+	if !assert.NoError(t, err) {
+		return
+	}
+	var names []string
+	for _, table := range tables {
+		names = append(names, table.Name)
+	}
+	assert.Equal(t, []string{"customers", "orders", "order_items", "teams", "players"}, names)
+	assert.Len(t, tables[0].Rows, 10)
+	assert.Len(t, tables[2].Rows, 25)
+
+	customers, orders := tables[0], tables[1]
+	assert.Equal(t, []string{"id", "email", "name", "age", "referrer_id"}, customers.Columns)
+	for i, row := range customers.Rows {
+		assert.Equal(t, int64(i+1), row[0])
+		email := row[1].(string)
+		assert.LessOrEqual(t, len(email), 30)
+		assert.NotContains(t, email, "\x00")
+		if row[3] != nil {
+			assert.Greater(t, row[3], int64(17))
+		}
+		// The first customer can only be referred by nobody.
+		if i == 0 {
+			assert.Nil(t, row[4])
+		}
+	}
+	for _, row := range orders.Rows {
+		assert.Contains(t, []interface{}{"new", "paid", "shipped"}, row[2])
+		assert.IsType(t, float64(0), row[3])
+	}
+
+	db := load(t, schema, tables)
+	for table, count := range map[string]int{"customers": 10, "orders": 10, "order_items": 25, "teams": 10, "players": 10} {
+		var n int
+		assert.NoError(t, db.QueryRow("SELECT count(*) FROM "+table).Scan(&n))
+		assert.Equal(t, count, n, table)
+	}
+	rows, err := db.Query("PRAGMA foreign_key_check")
+	if assert.NoError(t, err) {
+		assert.False(t, rows.Next(), "foreign key violations")
+		rows.Close()
+	}
+
+	again, err := Generate(Options{Seed: 1, RowCounts: map[string]int{"order_items": 25}}, parseSchema(t, schema))
+	assert.NoError(t, err)
+	assert.Equal(t, tables, again)
+	other, err := Generate(Options{Seed: 2, RowCounts: map[string]int{"order_items": 25}}, parseSchema(t, schema))
+	assert.NoError(t, err)
+	assert.NotEqual(t, tables, other)
+}
+
+func TestGenerateErrors(t *testing.T) {
+	for _, test := range []struct {
+		sql, err string
+	}{
+		{"CREATE TABLE t (a INTEGER NOT NULL REFERENCES missing)",
+			"datagen: table t: foreign key (a) references missing, which is not in the schema"},
+		{"CREATE TABLE p (id INTEGER PRIMARY KEY); CREATE TABLE c (p_id INTEGER NOT NULL REFERENCES p)",
+			"datagen: table c: foreign key (p_id) references p, which has no rows"},
+		{"CREATE TABLE a (id INTEGER PRIMARY KEY, b_id INTEGER NOT NULL REFERENCES b); CREATE TABLE b (id INTEGER PRIMARY KEY, a_id INTEGER NOT NULL REFERENCES a)",
+			"datagen: table b: foreign key (a_id) references a, whose rows are generated later: make the foreign key DEFERRABLE INITIALLY DEFERRED or its columns nullable"},
+		{"CREATE TABLE p (a); CREATE TABLE c (p_a REFERENCES p)",
+			"datagen: table c: foreign key (p_a) references p, which has no primary key"},
+		{"CREATE TABLE t (flag BOOLEAN NOT NULL UNIQUE)",
+			"datagen: table t: cannot generate row 3 satisfying its constraints in 1000 attempts"},
+	} {
+		_, err := Generate(Options{RowCounts: map[string]int{"p": 0}}, parseSchema(t, test.sql))
+		assert.EqualError(t, err, test.err, test.sql)
+	}
+
+	// A nullable foreign key to a missing table is NULL.
+	tables, err := Generate(Options{Rows: 3}, parseSchema(t, "CREATE TABLE t (a INTEGER REFERENCES missing)"))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]interface{}{{nil}, {nil}, {nil}}, tables[0].Rows)
+}
+
+func TestEval(t *testing.T) {
+	ts := newTableState(&parser.Table{Name: "t", Columns: []parser.Column{
+		{Name: "n", Type: "INTEGER"}, {Name: "s", Type: "TEXT"}, {Name: "x"},
+	}})
+	row := []interface{}{int64(5), "Hello", nil}
+	for sql, want := range map[string]interface{}{
+		"n > '4'":                              int64(1),
+		"s > 4":                                int64(1),
+		"n BETWEEN 1 AND 5 AND s <> ''":        int64(1),
+		"n NOT IN (1, 2, NULL)":                nil,
+		"n IN (5, NULL)":                       int64(1),
+		"x IS NULL OR n / 0 > 1":               int64(1),
+		"length(s) = 5 AND lower(s) = 'hello'": int64(1),
+		"s LIKE 'h_l%'":                        int64(1),
+		"typeof(n) || '-' || typeof(x)":        "integer-null",
+		"-n * 2.5 + 1":                         -11.5,
+		"coalesce(x, n) % 3":                   int64(2),
+		"NOT (x > 1)":                          nil,
+	} {
+		expr, errCode := parser.ParseExpr(sql)
+		if !assert.Equal(t, parser.ERROR_NONE, errCode, sql) {
+			continue
+		}
+		value, ok := ts.eval(expr, row)
+		assert.True(t, ok, sql)
+		assert.Equal(t, want, value, sql)
+	}
+
+	expr, _ := parser.ParseExpr("n IN (SELECT 1) OR s GLOB 'H*'")
+	_, ok := ts.eval(expr, row)
+	assert.False(t, ok)
+}
+
+func TestWriteSQL(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, WriteSQL(&b, []*Table{{
+		Name:    "my table",
+		Columns: []string{"a", "b"},
+		Rows:    [][]interface{}{{int64(1), "it's"}, {2.0, []byte{0, 255}}, {nil, 1.5}},
+	}}))
+	assert.Equal(t, `INSERT INTO "my table" (a, b) VALUES
+  (1, 'it''s'),
+  (2.0, X'00FF'),
+  (NULL, 1.5);
+`, b.String())
+}
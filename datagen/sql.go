@@ -0,0 +1,66 @@
+package datagen
+
+import (
+	"bufio"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// rowsPerInsert is the largest number of rows WriteSQL puts in one INSERT
+// statement.
+const rowsPerInsert = 100
+
+// WriteSQL writes the rows of tables as INSERT statements, in the order of
+// tables.
+func WriteSQL(w io.Writer, tables []*Table) error {
+	b := bufio.NewWriter(w)
+	for _, table := range tables {
+		columns := make([]string, len(table.Columns))
+		for i, column := range table.Columns {
+			columns[i] = parser.QuoteIdentifier(column)
+		}
+		for start := 0; start < len(table.Rows); start += rowsPerInsert {
+			end := start + rowsPerInsert
+			if end > len(table.Rows) {
+				end = len(table.Rows)
+			}
+			b.WriteString("INSERT INTO ")
+			b.WriteString(parser.QuoteIdentifier(table.Name))
+			b.WriteString(" (")
+			b.WriteString(strings.Join(columns, ", "))
+			b.WriteString(") VALUES\n")
+			for i, row := range table.Rows[start:end] {
+				b.WriteString("  (")
+				for j, value := range row {
+					if j > 0 {
+						b.WriteString(", ")
+					}
+					b.WriteString(FormatValue(value))
+				}
+				b.WriteString(")")
+				if start+i < end-1 {
+					b.WriteString(",\n")
+				} else {
+					b.WriteString(";\n")
+				}
+			}
+		}
+	}
+	return b.Flush()
+}
+
+// FormatValue returns a generated value as an SQL literal.
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case []byte:
+		return "X'" + strings.ToUpper(hex.EncodeToString(v)) + "'"
+	}
+	return toText(value)
+}
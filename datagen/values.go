@@ -0,0 +1,427 @@
+package datagen
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/codegen"
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// columnPlan describes the values to generate for a column. The bounds,
+// lengths and values come from its declared type and CHECK constraints.
+type columnPlan struct {
+	column   *parser.Column
+	affinity parser.Affinity
+	kind     codegen.Kind
+	notNull  bool
+	// rowid is set for the alias of the rowid, whose values are sequential.
+	rowid   bool
+	unique  bool
+	foreign bool
+
+	defaultValue interface{}
+	hasDefault   bool
+
+	min, max       float64
+	hasMin, hasMax bool
+	minLen, maxLen int
+	// values, when not nil, lists the only values allowed.
+	values []interface{}
+	// precision and scale are the arguments of a type such as DECIMAL(10, 2).
+	precision, scale int
+}
+
+func newColumnPlan(table *parser.Table, i int) *columnPlan {
+	column := &table.Columns[i]
+	c := &columnPlan{
+		column:   column,
+		affinity: column.Affinity(),
+		kind:     codegen.ColumnKind(column),
+		notNull:  column.IsNotnull,
+	}
+	// A column without a declared type has BLOB affinity, but holds ids
+	// more often than binary data.
+	if column.Type == "" {
+		c.kind = codegen.KIND_INTEGER
+	}
+	primaryKey := table.PrimaryKey()
+	if len(primaryKey) == 1 && strings.EqualFold(primaryKey[0], column.Name) &&
+		strings.EqualFold(column.Type, "INTEGER") && !table.IsWithoutRowid {
+		c.rowid, c.notNull = true, true
+	}
+
+	args := typeArgs(column)
+	switch c.kind {
+	case codegen.KIND_TEXT, codegen.KIND_BLOB:
+		if len(args) > 0 && args[0] > 0 {
+			c.maxLen = args[0]
+		}
+	case codegen.KIND_NUMERIC, codegen.KIND_REAL, codegen.KIND_INTEGER:
+		if len(args) > 0 {
+			c.precision = args[0]
+		}
+		if len(args) > 1 {
+			c.scale = args[1]
+		}
+		if c.kind == codegen.KIND_REAL && c.scale == 0 {
+			c.scale = 2
+		}
+	}
+
+	if s := column.DefaultExpr; s != "" && !strings.EqualFold(s, "NULL") && !strings.HasPrefix(strings.ToUpper(s), "CURRENT_") {
+		c.hasDefault = true
+		switch strings.ToUpper(s) {
+		case "TRUE":
+			c.defaultValue = int64(1)
+		case "FALSE":
+			c.defaultValue = int64(0)
+		default:
+			c.defaultValue = applyAffinity(c.affinity, s)
+		}
+	}
+	return c
+}
+
+// typeArgs returns the arguments of the declared type of a column, such as
+// 10 and 2 for DECIMAL(10, 2).
+func typeArgs(column *parser.Column) []int {
+	var texts []string
+	if column.TypeName != nil {
+		for _, arg := range column.TypeName.Args {
+			texts = append(texts, arg.String())
+		}
+	} else if column.Length != "" {
+		texts = strings.Split(column.Length, ",")
+	}
+	var args []int
+	for _, text := range texts {
+		n, err := strconv.Atoi(strings.TrimSpace(text))
+		if err != nil {
+			return nil
+		}
+		args = append(args, n)
+	}
+	return args
+}
+
+// value returns a value for a column that does not come from a foreign
+// key.
+func (g *generator) value(ts *tableState, c *columnPlan) interface{} {
+	if c.rowid {
+		next := ts.rowid + 1
+		if c.hasMin && float64(next) < c.min {
+			next = int64(math.Ceil(c.min))
+		}
+		return next
+	}
+	if !c.notNull && g.rand.Intn(10) == 0 {
+		return nil
+	}
+	if c.hasDefault && g.rand.Intn(4) == 0 {
+		return c.defaultValue
+	}
+	if c.values != nil {
+		if len(c.values) == 0 {
+			return nil
+		}
+		return c.values[g.rand.Intn(len(c.values))]
+	}
+
+	switch c.kind {
+	case codegen.KIND_INTEGER, codegen.KIND_BOOL:
+		return g.integer(ts, c)
+	case codegen.KIND_REAL:
+		return g.real(ts, c)
+	case codegen.KIND_NUMERIC:
+		if c.scale > 0 {
+			return g.real(ts, c)
+		}
+		return g.integer(ts, c)
+	case codegen.KIND_TIME:
+		return g.time(c)
+	case codegen.KIND_TEXT:
+		return g.text(ts, c)
+	}
+	n := 8
+	if c.maxLen > 0 && c.maxLen < n {
+		n = c.maxLen
+	}
+	b := make([]byte, n)
+	g.rand.Read(b)
+	return b
+}
+
+// bounds returns the range of the values of a numeric column: from its
+// CHECK constraints, or else from its name, its precision and whether its
+// values must be unique.
+func (ts *tableState) bounds(c *columnPlan) (float64, float64) {
+	lo, hi := 1.0, 1000.0
+	if c.kind == codegen.KIND_REAL || c.scale > 0 {
+		lo = 0
+	}
+	name := nameWords(c.column.Name)
+	switch {
+	case c.kind == codegen.KIND_BOOL:
+		lo, hi = 0, 1
+	case name["age"]:
+		lo, hi = 18, 90
+	case name["year"]:
+		lo, hi = 1990, 2030
+	case name["quantity"], name["qty"], name["count"]:
+		lo, hi = 1, 100
+	case c.unique:
+		hi = math.Max(hi, float64(10*ts.count))
+	}
+	if c.precision > 0 && c.precision-c.scale < 16 {
+		limit := math.Pow(10, float64(c.precision-c.scale)) - math.Pow(10, -float64(c.scale))
+		hi = math.Min(hi, limit)
+	}
+
+	span := hi - lo
+	switch {
+	case c.hasMin && c.hasMax:
+		lo, hi = c.min, c.max
+	case c.hasMin:
+		lo = c.min
+		if hi < lo {
+			hi = lo + span
+		}
+	case c.hasMax:
+		hi = c.max
+		if lo > hi {
+			lo = hi - span
+		}
+	}
+	return lo, hi
+}
+
+func (g *generator) integer(ts *tableState, c *columnPlan) interface{} {
+	lo, hi := ts.bounds(c)
+	min, max := int64(math.Ceil(lo)), int64(math.Floor(hi))
+	if max <= min {
+		return applyAffinity(c.affinity, min)
+	}
+	return applyAffinity(c.affinity, min+g.rand.Int63n(max-min+1))
+}
+
+func (g *generator) real(ts *tableState, c *columnPlan) interface{} {
+	lo, hi := ts.bounds(c)
+	unit := math.Pow(10, float64(c.scale))
+	v := math.Round((lo+(hi-lo)*g.rand.Float64())*unit) / unit
+	return applyAffinity(c.affinity, v)
+}
+
+// epoch is the start of the five years of generated dates and times.
+var epoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// time returns a date or time as text, in the format SQLite's date and time
+// functions read.
+func (g *generator) time(c *columnPlan) interface{} {
+	t := epoch.Add(time.Duration(g.rand.Int63n(5*365*24*3600)) * time.Second)
+	declared := strings.ToUpper(c.column.Type)
+	switch {
+	case strings.Contains(declared, "DATETIME"), strings.Contains(declared, "TIMESTAMP"):
+		return t.Format("2006-01-02 15:04:05")
+	case strings.Contains(declared, "DATE"):
+		return t.Format("2006-01-02")
+	}
+	return t.Format("15:04:05")
+}
+
+var (
+	firstNames = []string{"Alice", "Bob", "Carol", "Dave", "Erin", "Frank", "Grace", "Heidi", "Ivan", "Judy", "Mallory", "Nia", "Oscar", "Peggy", "Rupert", "Sybil", "Trent", "Victor", "Walter", "Yuki"}
+	lastNames  = []string{"Anderson", "Brown", "Chen", "Davis", "Evans", "Garcia", "Hughes", "Ito", "Jones", "Kim", "Lopez", "Miller", "Nguyen", "Okafor", "Patel", "Rossi", "Smith", "Taylor", "Weber", "Young"}
+	loremWords = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do", "eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua", "enim", "minim", "veniam", "quis", "nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip", "commodo"}
+)
+
+// text returns text for a column, shaped by its name, such as an e-mail
+// address for a column named email, and fit to its length bounds.
+func (g *generator) text(ts *tableState, c *columnPlan) interface{} {
+	name := nameWords(c.column.Name)
+	lower := strings.ToLower(c.column.Name)
+	first, last := firstNames[g.rand.Intn(len(firstNames))], lastNames[g.rand.Intn(len(lastNames))]
+	var s string
+	switch {
+	case strings.Contains(lower, "email"):
+		s = fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(first), strings.ToLower(last), g.rand.Intn(1000))
+	case name["firstname"], name["first"] && name["name"], name["given"]:
+		s = first
+	case name["lastname"], name["last"] && name["name"], name["surname"], name["family"]:
+		s = last
+	case name["username"], name["user"] && name["name"], name["login"]:
+		s = fmt.Sprintf("%s%d", strings.ToLower(first), g.rand.Intn(1000))
+	case name["name"]:
+		s = first + " " + last
+	case strings.Contains(lower, "phone"):
+		s = fmt.Sprintf("+1-555-%04d", g.rand.Intn(10000))
+	case name["url"], name["website"]:
+		s = fmt.Sprintf("https://example.com/%s", loremWords[g.rand.Intn(len(loremWords))])
+	case name["uuid"], name["guid"]:
+		b := make([]byte, 16)
+		g.rand.Read(b)
+		s = fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case name["at"], name["date"], name["time"]:
+		s = epoch.Add(time.Duration(g.rand.Int63n(5*365*24*3600)) * time.Second).Format("2006-01-02 15:04:05")
+	default:
+		n := 1 + g.rand.Intn(4)
+		parts := make([]string, n)
+		for i := range parts {
+			parts[i] = loremWords[g.rand.Intn(len(loremWords))]
+		}
+		s = strings.Join(parts, " ")
+		if c.unique {
+			s += fmt.Sprintf(" %d", g.rand.Intn(10*ts.count+1000))
+		}
+	}
+
+	runes := []rune(s)
+	if c.maxLen > 0 && len(runes) > c.maxLen {
+		runes = []rune(strings.TrimRight(string(runes[:c.maxLen]), " "))
+	}
+	for len(runes) < c.minLen {
+		runes = append(runes, rune('a'+g.rand.Intn(26)))
+	}
+	return string(runes)
+}
+
+// nameWords returns the lower-case words of a column name, separated by
+// underscores, other punctuation or a change of case, as in createdAt.
+func nameWords(name string) map[string]bool {
+	words := map[string]bool{}
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words[strings.ToLower(string(word))] = true
+			word = word[:0]
+		}
+	}
+	for _, r := range name {
+		switch {
+		case unicode.IsUpper(r):
+			if len(word) > 0 && unicode.IsLower(word[len(word)-1]) {
+				flush()
+			}
+			word = append(word, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word = append(word, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+// applyAffinity converts a value the way SQLite does when storing it in a
+// column of the given affinity.
+func applyAffinity(affinity parser.Affinity, value interface{}) interface{} {
+	switch affinity {
+	case parser.AFFINITY_TEXT:
+		switch v := value.(type) {
+		case int64, float64:
+			return toText(v)
+		}
+	case parser.AFFINITY_INTEGER, parser.AFFINITY_NUMERIC, parser.AFFINITY_REAL:
+		v := value
+		if s, ok := value.(string); ok {
+			if n, ok := parseNumber(s); ok {
+				v = n
+			}
+		}
+		switch n := v.(type) {
+		case int64:
+			if affinity == parser.AFFINITY_REAL {
+				return float64(n)
+			}
+		case float64:
+			if affinity != parser.AFFINITY_REAL && n == math.Trunc(n) && math.Abs(n) < 1<<63 {
+				return int64(n)
+			}
+		}
+		return v
+	}
+	return value
+}
+
+// parseNumber parses text that is entirely an integer or a real number,
+// allowing surrounding spaces.
+func parseNumber(s string) (interface{}, bool) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, true
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !strings.ContainsAny(s, "xXpP") && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return f, true
+	}
+	return nil, false
+}
+
+// toText returns a value as text, the way SQLite converts it.
+func toText(value interface{}) string {
+	switch v := value.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		s := strconv.FormatFloat(v, 'g', 15, 64)
+		if !strings.ContainsAny(s, ".eEn") {
+			s += ".0"
+		}
+		return s
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return ""
+}
+
+// keyValue returns a value as a string that is the same for values equal
+// under collation in a unique index.
+func keyValue(value interface{}, collation string) string {
+	switch v := value.(type) {
+	case int64:
+		return "n" + strconv.FormatInt(v, 10)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<63 {
+			return "n" + strconv.FormatInt(int64(v), 10)
+		}
+		return "n" + strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		switch strings.ToLower(collation) {
+		case "nocase":
+			v = asciiLower(v)
+		case "rtrim":
+			v = strings.TrimRight(v, " ")
+		}
+		return "s" + v
+	case []byte:
+		return "b" + hex.EncodeToString(v)
+	}
+	return ""
+}
+
+// asciiLower folds ASCII letters only, like SQLite's NOCASE collation and
+// lower function without ICU.
+func asciiLower(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, s)
+}
+
+func asciiUpper(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,276 @@
+package dbfile
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"unicode/utf16"
+)
+
+const (
+	pageInteriorTable = 0x05
+	pageLeafTable     = 0x0d
+)
+
+// page reads page number n, numbered from 1.
+func (f *File) page(n int) ([]byte, error) {
+	if n < 1 || n > f.Header.PageCount {
+		return nil, fmt.Errorf("dbfile: page %d out of range 1-%d", n, f.Header.PageCount)
+	}
+	data := make([]byte, f.Header.PageSize)
+	if _, err := f.r.ReadAt(data, int64(n-1)*int64(f.Header.PageSize)); err != nil {
+		return nil, fmt.Errorf("dbfile: reading page %d: %w", n, err)
+	}
+	return data, nil
+}
+
+// walk calls fn with the decoded record of every row of the table B-tree
+// rooted at page root, in rowid order.
+func (f *File) walk(root int, fn func(rowid int64, values []interface{}) error) error {
+	return f.walkPage(root, map[int]bool{}, fn)
+}
+
+func (f *File) walkPage(n int, visited map[int]bool, fn func(rowid int64, values []interface{}) error) error {
+	if visited[n] {
+		return fmt.Errorf("dbfile: page %d is part of a cycle", n)
+	}
+	visited[n] = true
+
+	data, err := f.page(n)
+	if err != nil {
+		return err
+	}
+	offset := 0
+	if n == 1 {
+		offset = HeaderSize
+	}
+	if offset+8 > len(data) {
+		return fmt.Errorf("dbfile: page %d too small", n)
+	}
+	pageType := data[offset]
+	headerSize := 8
+	if pageType == pageInteriorTable {
+		headerSize = 12
+	} else if pageType != pageLeafTable {
+		return fmt.Errorf("dbfile: page %d has type %#x, not a table B-tree page", n, pageType)
+	}
+	cells := int(binary.BigEndian.Uint16(data[offset+3:]))
+	pointers := offset + headerSize
+	if pointers+2*cells > f.Header.UsableSize() {
+		return fmt.Errorf("dbfile: page %d has too many cells (%d)", n, cells)
+	}
+
+	for i := 0; i < cells; i++ {
+		cell := int(binary.BigEndian.Uint16(data[pointers+2*i:]))
+		if cell < pointers+2*cells || cell >= f.Header.UsableSize() {
+			return fmt.Errorf("dbfile: page %d: cell %d at invalid offset %d", n, i, cell)
+		}
+		if pageType == pageInteriorTable {
+			if cell+4 > len(data) {
+				return fmt.Errorf("dbfile: page %d: cell %d truncated", n, i)
+			}
+			child := int(binary.BigEndian.Uint32(data[cell:]))
+			if err := f.walkPage(child, visited, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		rowid, payload, err := f.leafCell(data, cell)
+		if err != nil {
+			return fmt.Errorf("dbfile: page %d: cell %d: %w", n, i, err)
+		}
+		values, err := f.decodeRecord(payload)
+		if err != nil {
+			return fmt.Errorf("dbfile: page %d: row %d: %w", n, rowid, err)
+		}
+		if err := fn(rowid, values); err != nil {
+			return err
+		}
+	}
+
+	if pageType == pageInteriorTable {
+		right := int(binary.BigEndian.Uint32(data[offset+8:]))
+		return f.walkPage(right, visited, fn)
+	}
+	return nil
+}
+
+// leafCell returns the rowid and the full payload of the table leaf cell at
+// offset, following its overflow pages.
+func (f *File) leafCell(data []byte, offset int) (int64, []byte, error) {
+	size, n := varint(data[offset:])
+	if n == 0 {
+		return 0, nil, fmt.Errorf("truncated payload size")
+	}
+	offset += n
+	rowid, n := varint(data[offset:])
+	if n == 0 {
+		return 0, nil, fmt.Errorf("truncated rowid")
+	}
+	offset += n
+
+	usable := f.Header.UsableSize()
+	if size > uint64(f.Header.PageCount)*uint64(usable) {
+		return 0, nil, fmt.Errorf("payload of %d bytes larger than the database", size)
+	}
+	payloadSize := int(size)
+	local := localPayload(payloadSize, usable)
+	if offset+local > usable {
+		return 0, nil, fmt.Errorf("payload overflows its page")
+	}
+	payload := make([]byte, 0, payloadSize)
+	payload = append(payload, data[offset:offset+local]...)
+	if local == payloadSize {
+		return int64(rowid), payload, nil
+	}
+	if offset+local+4 > usable {
+		return 0, nil, fmt.Errorf("missing overflow page number")
+	}
+
+	// Each overflow page starts with the number of the next one, 0 on the
+	// last page, followed by up to usable-4 bytes of payload.
+	next := int(binary.BigEndian.Uint32(data[offset+local:]))
+	visited := map[int]bool{}
+	for len(payload) < payloadSize {
+		if next == 0 || visited[next] {
+			return 0, nil, fmt.Errorf("overflow chain ends after %d of %d bytes", len(payload), payloadSize)
+		}
+		visited[next] = true
+		page, err := f.page(next)
+		if err != nil {
+			return 0, nil, err
+		}
+		chunk := payloadSize - len(payload)
+		if chunk > usable-4 {
+			chunk = usable - 4
+		}
+		payload = append(payload, page[4:4+chunk]...)
+		next = int(binary.BigEndian.Uint32(page))
+	}
+	return int64(rowid), payload, nil
+}
+
+// localPayload returns how many bytes of a payload of the given size are
+// stored on a table leaf page, as computed by SQLite.
+func localPayload(size, usable int) int {
+	maxLocal := usable - 35
+	if size <= maxLocal {
+		return size
+	}
+	minLocal := (usable-12)*32/255 - 23
+	local := minLocal + (size-minLocal)%(usable-4)
+	if local > maxLocal {
+		local = minLocal
+	}
+	return local
+}
+
+// varint decodes an SQLite variable-length integer and returns it with the
+// number of bytes read, or 0 if data is too short.
+func varint(data []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 9; i++ {
+		if i >= len(data) {
+			return 0, 0
+		}
+		if i == 8 {
+			return v<<8 | uint64(data[i]), 9
+		}
+		v = v<<7 | uint64(data[i]&0x7f)
+		if data[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return v, 9
+}
+
+// decodeRecord decodes a record into nil, int64, float64, string and []byte
+// values.
+func (f *File) decodeRecord(payload []byte) ([]interface{}, error) {
+	headerSize, n := varint(payload)
+	if n == 0 || headerSize > uint64(len(payload)) || int(headerSize) < n {
+		return nil, fmt.Errorf("invalid record header size")
+	}
+	header, body := payload[n:headerSize], payload[headerSize:]
+
+	var values []interface{}
+	for len(header) > 0 {
+		serialType, n := varint(header)
+		if n == 0 {
+			return nil, fmt.Errorf("truncated record header")
+		}
+		header = header[n:]
+
+		size := serialSize(serialType)
+		if size > uint64(len(body)) {
+			return nil, fmt.Errorf("record body truncated")
+		}
+		value := body[:size]
+		body = body[size:]
+
+		switch {
+		case serialType == 0:
+			values = append(values, nil)
+		case serialType <= 6:
+			values = append(values, bigEndianInt(value))
+		case serialType == 7:
+			values = append(values, math.Float64frombits(binary.BigEndian.Uint64(value)))
+		case serialType == 8:
+			values = append(values, int64(0))
+		case serialType == 9:
+			values = append(values, int64(1))
+		case serialType >= 12 && serialType%2 == 0:
+			values = append(values, append([]byte(nil), value...))
+		case serialType >= 13:
+			text, err := f.decodeText(value)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, text)
+		default:
+			return nil, fmt.Errorf("reserved serial type %d", serialType)
+		}
+	}
+	return values, nil
+}
+
+func serialSize(serialType uint64) uint64 {
+	switch {
+	case serialType <= 4:
+		return serialType
+	case serialType == 5:
+		return 6
+	case serialType <= 7:
+		return 8
+	case serialType < 12:
+		return 0
+	}
+	return (serialType - 12) / 2
+}
+
+// bigEndianInt decodes a signed big-endian integer of 1 to 8 bytes.
+func bigEndianInt(data []byte) int64 {
+	v := int64(int8(data[0]))
+	for _, b := range data[1:] {
+		v = v<<8 | int64(b)
+	}
+	return v
+}
+
+func (f *File) decodeText(data []byte) (string, error) {
+	if f.Header.TextEncoding == ENCODING_UTF8 {
+		return string(data), nil
+	}
+	if len(data)%2 != 0 {
+		return "", fmt.Errorf("odd length %s text", f.Header.TextEncoding)
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if f.Header.TextEncoding == ENCODING_UTF16LE {
+			units[i] = binary.LittleEndian.Uint16(data[2*i:])
+		} else {
+			units[i] = binary.BigEndian.Uint16(data[2*i:])
+		}
+	}
+	return string(utf16.Decode(units)), nil
+}
@@ -0,0 +1,283 @@
+// Package dbfile reads the schema of an SQLite database file without SQLite:
+// it decodes the sqlite_schema table stored in the file's first B-tree and
+// parses the SQL text of every table, index, view and trigger.
+//
+// Only the schema is read. The file is expected to be consistent, as after a
+// checkpoint: pages still in a write-ahead log are not seen.
+package dbfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// HeaderSize is the size of the database header at the start of page 1.
+const HeaderSize = 100
+
+// Magic is the header string every database file starts with.
+const Magic = "SQLite format 3\x00"
+
+// ErrNotDatabase is returned when a file does not start with Magic.
+var ErrNotDatabase = errors.New("dbfile: not an SQLite database file")
+
+// Encoding is the text encoding of a database.
+type Encoding int
+
+const (
+	ENCODING_UTF8    Encoding = 1
+	ENCODING_UTF16LE Encoding = 2
+	ENCODING_UTF16BE Encoding = 3
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case ENCODING_UTF8:
+		return "UTF-8"
+	case ENCODING_UTF16LE:
+		return "UTF-16le"
+	case ENCODING_UTF16BE:
+		return "UTF-16be"
+	}
+	return fmt.Sprintf("Encoding(%d)", int(e))
+}
+
+// Header holds the fields of the database header the reader depends on.
+type Header struct {
+	PageSize      int
+	ReservedBytes int
+	// PageCount is the size of the database in pages, from the header when
+	// it is valid, otherwise from the size of the file.
+	PageCount     int
+	SchemaCookie  uint32
+	SchemaFormat  int
+	TextEncoding  Encoding
+	UserVersion   uint32
+	ApplicationID uint32
+}
+
+// UsableSize is the number of bytes of a page available to the B-tree.
+func (h *Header) UsableSize() int {
+	return h.PageSize - h.ReservedBytes
+}
+
+// ParseHeader validates the 100-byte database header.
+func ParseHeader(data []byte, fileSize int64) (*Header, error) {
+	if len(data) < HeaderSize || string(data[:len(Magic)]) != Magic {
+		return nil, ErrNotDatabase
+	}
+	var h Header
+	h.PageSize = int(binary.BigEndian.Uint16(data[16:]))
+	if h.PageSize == 1 {
+		h.PageSize = 65536
+	}
+	if h.PageSize < 512 || h.PageSize&(h.PageSize-1) != 0 {
+		return nil, fmt.Errorf("dbfile: invalid page size %d", h.PageSize)
+	}
+	if write, read := data[18], data[19]; write < 1 || write > 2 || read < 1 || read > 2 {
+		return nil, fmt.Errorf("dbfile: unsupported file format version %d/%d", write, read)
+	}
+	h.ReservedBytes = int(data[20])
+	if h.UsableSize() < 480 {
+		return nil, fmt.Errorf("dbfile: %d reserved bytes leave too small pages", h.ReservedBytes)
+	}
+	if !bytes.Equal(data[21:24], []byte{64, 32, 32}) {
+		return nil, fmt.Errorf("dbfile: invalid payload fractions %v", data[21:24])
+	}
+
+	h.PageCount = int(binary.BigEndian.Uint32(data[28:]))
+	if h.PageCount == 0 || binary.BigEndian.Uint32(data[24:]) != binary.BigEndian.Uint32(data[92:]) {
+		h.PageCount = int(fileSize / int64(h.PageSize))
+	}
+	if int64(h.PageCount)*int64(h.PageSize) > fileSize {
+		return nil, fmt.Errorf("dbfile: header claims %d pages of %d bytes, file has %d bytes", h.PageCount, h.PageSize, fileSize)
+	}
+
+	h.SchemaCookie = binary.BigEndian.Uint32(data[40:])
+	h.SchemaFormat = int(binary.BigEndian.Uint32(data[44:]))
+	if h.SchemaFormat > 4 {
+		return nil, fmt.Errorf("dbfile: unsupported schema format %d", h.SchemaFormat)
+	}
+	h.TextEncoding = Encoding(binary.BigEndian.Uint32(data[56:]))
+	switch h.TextEncoding {
+	case 0:
+		// Set when the first table is created; an empty database has none.
+		h.TextEncoding = ENCODING_UTF8
+	case ENCODING_UTF8, ENCODING_UTF16LE, ENCODING_UTF16BE:
+	default:
+		return nil, fmt.Errorf("dbfile: invalid text encoding %d", int(h.TextEncoding))
+	}
+	h.UserVersion = binary.BigEndian.Uint32(data[60:])
+	h.ApplicationID = binary.BigEndian.Uint32(data[68:])
+	return &h, nil
+}
+
+// File is an open database file.
+type File struct {
+	Header Header
+	r      io.ReaderAt
+	closer io.Closer
+}
+
+// Open opens the database file at path.
+func Open(path string) (*File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	f, err := NewFile(file, info.Size())
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	f.closer = file
+	return f, nil
+}
+
+// NewFile reads a database of the given size from r.
+func NewFile(r io.ReaderAt, size int64) (*File, error) {
+	data := make([]byte, HeaderSize)
+	if _, err := r.ReadAt(data, 0); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrNotDatabase
+		}
+		return nil, err
+	}
+	header, err := ParseHeader(data, size)
+	if err != nil {
+		return nil, err
+	}
+	return &File{Header: *header, r: r}, nil
+}
+
+// Close closes the file opened by Open.
+func (f *File) Close() error {
+	if f.closer == nil {
+		return nil
+	}
+	return f.closer.Close()
+}
+
+// Object is a row of the sqlite_schema table. SQL is empty for the indexes
+// SQLite creates for UNIQUE and PRIMARY KEY constraints.
+type Object struct {
+	Type      string
+	Name      string
+	TableName string
+	RootPage  int
+	SQL       string
+}
+
+// Objects returns the rows of the sqlite_schema table in rowid order.
+func (f *File) Objects() ([]Object, error) {
+	var objects []Object
+	err := f.walk(1, func(rowid int64, values []interface{}) error {
+		if len(values) < 5 {
+			return fmt.Errorf("dbfile: schema row %d has %d columns", rowid, len(values))
+		}
+		var object Object
+		var ok [5]bool
+		object.Type, ok[0] = textValue(values[0])
+		object.Name, ok[1] = textValue(values[1])
+		object.TableName, ok[2] = textValue(values[2])
+		rootPage, isInt := values[3].(int64)
+		object.RootPage, ok[3] = int(rootPage), isInt || values[3] == nil
+		object.SQL, ok[4] = textValue(values[4])
+		for i := range ok {
+			if !ok[i] {
+				return fmt.Errorf("dbfile: schema row %d: unexpected value %#v in column %d", rowid, values[i], i+1)
+			}
+		}
+		objects = append(objects, object)
+		return nil
+	})
+	return objects, err
+}
+
+func textValue(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case nil:
+		return "", true
+	case string:
+		return value, true
+	}
+	return "", false
+}
+
+// ParseError reports an object of the sqlite_schema table whose SQL text
+// cannot be parsed.
+type ParseError struct {
+	Type string
+	Name string
+	Code parser.ErrorCode
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("dbfile: cannot parse %s %s: %s error", e.Type, e.Name, e.Code)
+}
+
+// ParseErrors lists every object Schema could not parse, in rowid order.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Schema parses the SQL text of every object of the database. Internal
+// indexes, which have no SQL, and virtual tables, which the parser does not
+// model, are skipped. An object that cannot be parsed does not stop the
+// others: the schema holds every object that parsed, and the error is a
+// ParseErrors listing the ones that did not.
+func (f *File) Schema() (*parser.Schema, error) {
+	objects, err := f.Objects()
+	if err != nil {
+		return nil, err
+	}
+	var schema parser.Schema
+	var failures ParseErrors
+	for _, object := range objects {
+		if object.SQL == "" {
+			continue
+		}
+		if object.Type == "table" && isVirtual(object.SQL) {
+			continue
+		}
+		if errCode := schema.Add(object.SQL); errCode != parser.ERROR_NONE {
+			failures = append(failures, &ParseError{Type: object.Type, Name: object.Name, Code: errCode})
+		}
+	}
+	if failures != nil {
+		return &schema, failures
+	}
+	return &schema, nil
+}
+
+func isVirtual(sql string) bool {
+	fields := strings.Fields(sql)
+	return len(fields) > 1 && strings.EqualFold(fields[1], "VIRTUAL")
+}
+
+// ReadSchema opens the database file at path and returns its schema.
+func ReadSchema(path string) (*parser.Schema, error) {
+	f, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Schema()
+}
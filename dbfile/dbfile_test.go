@@ -0,0 +1,190 @@
+package dbfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The fixtures are created by the sqlite3 shell from the script of the same
+// name, for example: sqlite3 testdata/schema.db < testdata/schema.sql
+
+func TestReadSchema(t *testing.T) {
+	schema, err := ReadSchema("testdata/schema.db")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// users, sqlite_sequence, wide and t00 to t29.
+	assert.Len(t, schema.Tables, 33)
+	users := schema.Table("users")
+	if assert.NotNil(t, users) {
+		assert.Equal(t, 3, users.NumColumns)
+		assert.True(t, users.Columns[0].IsAutoincrement)
+		assert.Equal(t, "NOCASE", users.Columns[1].CollateName)
+	}
+	assert.NotNil(t, schema.Table("sqlite_sequence"))
+	assert.NotNil(t, schema.Table("t29"))
+
+	// Longer than a page, the statement continues on overflow pages.
+	wide := schema.Table("wide")
+	if assert.NotNil(t, wide) {
+		assert.Equal(t, 30, wide.NumColumns)
+		assert.Equal(t, "column_with_a_rather_long_name_29", wide.Columns[29].Name)
+	}
+
+	if assert.Len(t, schema.Indexes, 1) {
+		assert.Equal(t, "users_name", schema.Indexes[0].Name)
+		assert.Equal(t, "name IS NOT NULL", schema.Indexes[0].Where)
+	}
+	if assert.Len(t, schema.Views, 1) {
+		assert.Equal(t, "named_users", schema.Views[0].Name)
+	}
+	if assert.Len(t, schema.Triggers, 1) {
+		assert.Equal(t, "users", schema.Triggers[0].Table)
+	}
+}
+
+func TestObjects(t *testing.T) {
+	f, err := Open("testdata/schema.db")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+
+	assert.Equal(t, 512, f.Header.PageSize)
+	assert.Equal(t, ENCODING_UTF8, f.Header.TextEncoding)
+
+	objects, err := f.Objects()
+	if !assert.NoError(t, err) || !assert.Len(t, objects, 37) {
+		return
+	}
+	assert.Equal(t, "users", objects[0].Name)
+	assert.Equal(t, "table", objects[0].Type)
+	assert.Equal(t, 2, objects[0].RootPage)
+	assert.Equal(t, Object{Type: "index", Name: "sqlite_autoindex_users_1", TableName: "users", RootPage: 3}, objects[1])
+
+	var view Object
+	for _, object := range objects {
+		if object.Type == "view" {
+			view = object
+		}
+	}
+	assert.Equal(t, 0, view.RootPage)
+	assert.Equal(t, "CREATE VIEW named_users AS SELECT id, name FROM users WHERE name IS NOT NULL", view.SQL)
+}
+
+func TestUTF16(t *testing.T) {
+	f, err := Open("testdata/utf16.db")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer f.Close()
+	assert.Equal(t, ENCODING_UTF16BE, f.Header.TextEncoding)
+
+	schema, err := f.Schema()
+	if assert.NoError(t, err) && assert.Len(t, schema.Tables, 1) {
+		table := schema.Tables[0]
+		assert.Equal(t, "café", table.Name)
+		assert.Equal(t, "naïve", table.Columns[0].Name)
+		assert.Equal(t, "日本", table.Columns[1].Name)
+	}
+}
+
+func readFixture(t *testing.T) []byte {
+	data, err := os.ReadFile("testdata/schema.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestInvalidHeader(t *testing.T) {
+	for name, corrupt := range map[string]func(data []byte) []byte{
+		"not a database":  func(data []byte) []byte { return []byte("CREATE TABLE a (x);") },
+		"empty":           func(data []byte) []byte { return nil },
+		"page size":       func(data []byte) []byte { binary.BigEndian.PutUint16(data[16:], 1000); return data },
+		"format version":  func(data []byte) []byte { data[19] = 3; return data },
+		"reserved bytes":  func(data []byte) []byte { data[20] = 100; return data },
+		"fractions":       func(data []byte) []byte { data[21] = 65; return data },
+		"encoding":        func(data []byte) []byte { binary.BigEndian.PutUint32(data[56:], 4); return data },
+		"truncated pages": func(data []byte) []byte { return data[:len(data)-512] },
+	} {
+		data := corrupt(readFixture(t))
+		_, err := NewFile(bytes.NewReader(data), int64(len(data)))
+		assert.Error(t, err, name)
+	}
+
+	data := []byte("CREATE TABLE a (x);")
+	_, err := NewFile(bytes.NewReader(data), int64(len(data)))
+	assert.Equal(t, ErrNotDatabase, err)
+}
+
+func TestCorruptBTree(t *testing.T) {
+	// The right-most child of page 1 pointing back to page 1.
+	data := readFixture(t)
+	binary.BigEndian.PutUint32(data[HeaderSize+8:], 1)
+	f, err := NewFile(bytes.NewReader(data), int64(len(data)))
+	if assert.NoError(t, err) {
+		_, err = f.Objects()
+		assert.Error(t, err)
+	}
+
+	// A page that is not a table B-tree page.
+	data = readFixture(t)
+	data[HeaderSize] = 0x02
+	f, err = NewFile(bytes.NewReader(data), int64(len(data)))
+	if assert.NoError(t, err) {
+		_, err = f.Objects()
+		assert.Error(t, err)
+	}
+}
+
+func TestSchemaParseErrors(t *testing.T) {
+	data := readFixture(t)
+	for _, name := range []string{"t05", "t17"} {
+		old := []byte("CREATE TABLE " + name + " (")
+		if !assert.Equal(t, 1, bytes.Count(data, old), name) {
+			return
+		}
+		data = bytes.Replace(data, old, []byte("CREATE TABLE "+name+" ,"), 1)
+	}
+	f, err := NewFile(bytes.NewReader(data), int64(len(data)))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	schema, err := f.Schema()
+	var failures ParseErrors
+	if assert.ErrorAs(t, err, &failures) && assert.Len(t, failures, 2) {
+		assert.Equal(t, "t05", failures[0].Name)
+		assert.Equal(t, "t17", failures[1].Name)
+		assert.Equal(t, "dbfile: cannot parse table t05: SYNTAX error", failures[0].Error())
+	}
+	// Every other object is still parsed, including those after the failures.
+	assert.Len(t, schema.Tables, 31)
+	assert.Nil(t, schema.Table("t05"))
+	assert.NotNil(t, schema.Table("t29"))
+	assert.Len(t, schema.Views, 1)
+}
+
+func TestVarint(t *testing.T) {
+	for _, test := range []struct {
+		data  []byte
+		value uint64
+		n     int
+	}{
+		{[]byte{0x00}, 0, 1},
+		{[]byte{0x7f}, 127, 1},
+		{[]byte{0x81, 0x00}, 128, 2},
+		{[]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 1<<64 - 1, 9},
+		{[]byte{0x81}, 0, 0},
+	} {
+		value, n := varint(test.data)
+		assert.Equal(t, test.value, value)
+		assert.Equal(t, test.n, n)
+	}
+}
@@ -0,0 +1,75 @@
+PRAGMA page_size = 512;
+CREATE TABLE users (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  email TEXT NOT NULL UNIQUE COLLATE NOCASE,
+  name TEXT
+);
+CREATE INDEX users_name ON users (name DESC) WHERE name IS NOT NULL;
+CREATE VIEW named_users AS SELECT id, name FROM users WHERE name IS NOT NULL;
+CREATE TRIGGER users_lower AFTER INSERT ON users BEGIN
+  UPDATE users SET email = lower(new.email) WHERE id = new.id;
+END;
+-- Stored in overflow pages: longer than a 512-byte page.
+CREATE TABLE wide (
+  column_with_a_rather_long_name_00 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_01 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_02 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_03 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_04 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_05 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_06 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_07 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_08 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_09 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_10 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_11 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_12 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_13 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_14 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_15 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_16 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_17 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_18 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_19 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_20 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_21 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_22 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_23 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_24 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_25 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_26 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_27 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_28 TEXT NOT NULL DEFAULT 'x',
+  column_with_a_rather_long_name_29 TEXT NOT NULL DEFAULT 'x'
+);
+-- Enough rows for sqlite_schema to need an interior page.
+CREATE TABLE t00 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t01 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t02 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t03 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t04 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t05 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t06 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t07 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t08 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t09 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t10 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t11 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t12 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t13 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t14 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t15 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t16 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t17 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t18 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t19 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t20 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t21 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t22 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t23 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t24 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t25 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t26 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t27 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t28 (id INTEGER PRIMARY KEY, value TEXT);
+CREATE TABLE t29 (id INTEGER PRIMARY KEY, value TEXT);
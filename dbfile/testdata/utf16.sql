@@ -0,0 +1,2 @@
+PRAGMA encoding = 'UTF-16be';
+CREATE TABLE "café" (naïve TEXT, "日本" BLOB);
@@ -0,0 +1,149 @@
+// Package dialect translates parsed SQLite CREATE TABLE statements to the
+// DDL of other databases, and the CREATE TABLE statements of PostgreSQL and
+// MySQL to the SQLite table model.
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// Warning reports a construct that could not be translated exactly. Feature
+// names the construct, such as "ON CONFLICT" or "COLLATE NOCASE", so that
+// warnings can be filtered; Column is empty for table-level constructs.
+type Warning struct {
+	Table   string `json:"table"`
+	Column  string `json:"column,omitempty"`
+	Feature string `json:"feature"`
+	Message string `json:"message"`
+}
+
+func (w Warning) String() string {
+	if w.Table == "" {
+		return w.Message
+	}
+	if w.Column != "" {
+		return fmt.Sprintf("%s.%s: %s", w.Table, w.Column, w.Message)
+	}
+	return fmt.Sprintf("%s: %s", w.Table, w.Message)
+}
+
+// translator collects the warnings of one translation.
+type translator struct {
+	warnings []Warning
+	// conflictHint tells what replaces ON CONFLICT clauses in the target.
+	conflictHint string
+	// tables are the tables translated together, in which foreign keys
+	// look up their parent tables.
+	tables []*parser.Table
+	// indexes are the CREATE INDEX statements translated with the tables.
+	indexes []*parser.Index
+}
+
+// table returns the translated table with the given name, compared
+// case-insensitively, or nil.
+func (t *translator) table(name string) *parser.Table {
+	for _, table := range t.tables {
+		if strings.EqualFold(table.Name, name) {
+			return table
+		}
+	}
+	return nil
+}
+
+func (t *translator) warn(table *parser.Table, column, feature, format string, args ...interface{}) {
+	t.warnObject(table.Name, column, feature, format, args...)
+}
+
+// warnObject reports a warning about an index, view or trigger, or a column
+// of the table named name.
+func (t *translator) warnObject(name, column, feature, format string, args ...interface{}) {
+	t.warnings = append(t.warnings, Warning{Table: name, Column: column, Feature: feature, Message: fmt.Sprintf(format, args...)})
+}
+
+// skipUntranslated reports the views and triggers of schema, which are
+// written in SQLite's SQL and are not translated.
+func (t *translator) skipUntranslated(schema *parser.Schema) {
+	for _, view := range schema.Views {
+		t.warnObject(view.Name, "", "CREATE VIEW", "views are not translated; the view is skipped")
+	}
+	for _, trigger := range schema.Triggers {
+		t.warnObject(trigger.Table, "", "CREATE TRIGGER", "triggers are not translated; trigger %s is skipped", trigger.Name)
+	}
+}
+
+// qualifiedName returns the name of a table or index with its schema, unless
+// that is SQLite's main or temp database.
+func qualifiedName(schema, name string, quote func(string) string) string {
+	if schema != "" && !strings.EqualFold(schema, "main") && !strings.EqualFold(schema, "temp") {
+		return quote(schema) + "." + quote(name)
+	}
+	return quote(name)
+}
+
+// conflict reports an ON CONFLICT clause, which other databases only support
+// on INSERT statements.
+func (t *translator) conflict(table *parser.Table, column string, clause parser.ConflictClause) {
+	if clause != parser.CONFLICT_NONE {
+		t.warn(table, column, "ON CONFLICT", "%s is dropped; %s", clause.SQL(), t.conflictHint)
+	}
+}
+
+// isRowidAlias reports whether column is the INTEGER PRIMARY KEY aliasing
+// the rowid, which SQLite fills in when an INSERT omits it.
+func isRowidAlias(table *parser.Table, column *parser.Column) bool {
+	key := table.PrimaryKey()
+	return !table.IsWithoutRowid && strings.EqualFold(column.Type, "INTEGER") &&
+		len(key) == 1 && strings.EqualFold(key[0], column.Name) && column.PkOrder != parser.ORDER_DESC
+}
+
+// typeLength returns the arguments of a declared type, such as "10,2".
+func typeLength(column *parser.Column) string {
+	return strings.ReplaceAll(column.Length, " ", "")
+}
+
+// literal writes a DEFAULT value: keywords and numbers as is, anything else
+// as a string literal.
+func literal(value string) string {
+	switch strings.ToUpper(value) {
+	case "CURRENT_TIME", "CURRENT_DATE", "CURRENT_TIMESTAMP", "TRUE", "FALSE", "NULL":
+		return strings.ToUpper(value)
+	}
+	if isNumber(value) {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func isNumber(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	digits, dot := 0, false
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '.' && !dot:
+			dot = true
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// join writes the column definitions and table constraints of a CREATE
+// TABLE body, one per line.
+func join(lines []string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		b.WriteString("  ")
+		b.WriteString(line)
+		if i < len(lines)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
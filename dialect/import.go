@@ -0,0 +1,1021 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// FromPostgres parses the CREATE TABLE statements of a PostgreSQL script
+// into the SQLite table model. Enum types created with CREATE TYPE ... AS
+// ENUM become CHECK constraints on the columns using them. Constructs
+// without an SQLite equivalent, and statements other than CREATE TABLE and
+// CREATE TYPE, are dropped with a warning; the error reports syntax the
+// importer does not understand.
+func FromPostgres(sql string) (*parser.Schema, []Warning, error) {
+	return importSchema(sql, false)
+}
+
+// FromMySQL parses the CREATE TABLE statements of a MySQL or MariaDB script
+// into the SQLite table model, like FromPostgres. ENUM columns become CHECK
+// constraints and secondary indexes are dropped with a warning.
+func FromMySQL(sql string) (*parser.Schema, []Warning, error) {
+	return importSchema(sql, true)
+}
+
+type importer struct {
+	s        scanner
+	tokens   []token
+	pos      int
+	warnings []Warning
+	// enums holds the values of the enum types created so far.
+	enums map[string][]string
+	table *parser.Table
+	// identity lists the columns whose value the database generates.
+	identity map[string]bool
+}
+
+func importSchema(sql string, mysql bool) (*parser.Schema, []Warning, error) {
+	p := &importer{s: scanner{src: sql, mysql: mysql}, enums: map[string][]string{}}
+	tokens, err := p.s.scan()
+	if err != nil {
+		return nil, nil, err
+	}
+	p.tokens = tokens
+
+	schema := &parser.Schema{}
+	for p.peek().kind != tokenEOF {
+		if p.accept(";") {
+			continue
+		}
+		start := p.pos
+		table, err := p.statement()
+		if err != nil {
+			return nil, nil, err
+		}
+		if table != nil {
+			schema.Tables = append(schema.Tables, table)
+		}
+		if p.pos == start {
+			p.pos++
+		}
+		p.skipTo(";")
+	}
+	return schema, p.warnings, nil
+}
+
+func (p *importer) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *importer) peekAt(n int) token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *importer) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the given keywords or punctuation if they come next.
+func (p *importer) accept(words ...string) bool {
+	for i, word := range words {
+		if !p.peekAt(i).is(word) {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+func (p *importer) expect(words ...string) error {
+	if !p.accept(words...) {
+		return p.errorf("expected %s", strings.Join(words, " "))
+	}
+	return nil
+}
+
+func (p *importer) errorf(format string, args ...interface{}) error {
+	t := p.peek()
+	found := t.text
+	if t.kind == tokenEOF {
+		found = "end of input"
+	}
+	return p.s.errorf(t.offset, "%s, found %q", fmt.Sprintf(format, args...), found)
+}
+
+func (p *importer) warn(column, feature, format string, args ...interface{}) {
+	table := ""
+	if p.table != nil {
+		table = p.table.Name
+	}
+	p.warnings = append(p.warnings, Warning{Table: table, Column: column, Feature: feature, Message: fmt.Sprintf(format, args...)})
+}
+
+// skipTo advances to the next of the given punctuation at the current
+// nesting level, stopping early at a closing parenthesis of an enclosing
+// level or at the end of the statement.
+func (p *importer) skipTo(stops ...string) {
+	depth := 0
+	for t := p.peek(); t.kind != tokenEOF && !t.is(";"); t = p.peek() {
+		if depth == 0 {
+			for _, stop := range stops {
+				if t.is(stop) {
+					return
+				}
+			}
+		}
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			if depth == 0 {
+				return
+			}
+			depth--
+		}
+		p.next()
+	}
+}
+
+// text returns the source from token start up to the current token.
+func (p *importer) text(start int) string {
+	if start >= p.pos {
+		return ""
+	}
+	first, last := p.tokens[start], p.tokens[p.pos-1]
+	return p.s.src[first.offset : last.offset+len(last.text)]
+}
+
+// parenthesized reads "( ... )" and returns the source between the
+// parentheses.
+func (p *importer) parenthesized() (string, error) {
+	if err := p.expect("("); err != nil {
+		return "", err
+	}
+	start := p.pos
+	p.skipTo(")")
+	text := p.text(start)
+	return text, p.expect(")")
+}
+
+// name reads an identifier, bare or quoted.
+func (p *importer) name() (string, error) {
+	switch t := p.peek(); t.kind {
+	case tokenWord:
+		p.next()
+		return t.text, nil
+	case tokenQuoted:
+		p.next()
+		return t.value, nil
+	case tokenString:
+		// MySQL accepts strings in some identifier positions.
+		if p.s.mysql {
+			p.next()
+			return t.value, nil
+		}
+	}
+	return "", p.errorf("expected a name")
+}
+
+// qualifiedName reads [schema.]name.
+func (p *importer) qualifiedName() (string, string, error) {
+	name, err := p.name()
+	if err != nil || !p.accept(".") {
+		return "", name, err
+	}
+	table, err := p.name()
+	return name, table, err
+}
+
+func (p *importer) nameList() ([]string, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var names []string
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.accept(",") {
+			return names, p.expect(")")
+		}
+	}
+}
+
+func (p *importer) statement() (*parser.Table, error) {
+	p.table = nil
+	start := p.pos
+	if !p.accept("CREATE") {
+		p.warn("", "statement", "%s statement ignored", strings.ToUpper(p.peek().text))
+		return nil, nil
+	}
+	p.accept("OR", "REPLACE")
+	temporary := false
+	for modifier := true; modifier; {
+		switch {
+		case p.accept("TEMP"), p.accept("TEMPORARY"):
+			temporary = true
+		case p.accept("GLOBAL"), p.accept("LOCAL"):
+		case p.accept("UNLOGGED"):
+			p.warn("", "UNLOGGED", "UNLOGGED is dropped")
+		default:
+			modifier = false
+		}
+	}
+	switch {
+	case p.accept("TABLE"):
+		return p.createTable(temporary)
+	case !p.s.mysql && p.accept("TYPE"):
+		return nil, p.createType()
+	}
+	p.warn("", "statement", "CREATE %s statement ignored", strings.ToUpper(p.peek().text))
+	p.pos = start
+	return nil, nil
+}
+
+// createType records the values of CREATE TYPE name AS ENUM (...).
+func (p *importer) createType() error {
+	_, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if !p.accept("AS", "ENUM") {
+		p.warn("", "CREATE TYPE", "type %s is not an enum and is ignored", name)
+		return nil
+	}
+	values, err := p.stringList()
+	if err == nil {
+		p.enums[strings.ToLower(name)] = values
+	}
+	return err
+}
+
+func (p *importer) stringList() ([]string, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var values []string
+	for !p.accept(")") {
+		t := p.next()
+		if t.kind != tokenString {
+			return nil, p.errorf("expected a string")
+		}
+		values = append(values, t.value)
+		p.accept(",")
+	}
+	return values, nil
+}
+
+func (p *importer) createTable(temporary bool) (*parser.Table, error) {
+	table := &parser.Table{IsTemporary: temporary}
+	p.table = table
+	p.identity = map[string]bool{}
+	table.IsIfNotExists = p.accept("IF", "NOT", "EXISTS")
+	schema, name, err := p.qualifiedName()
+	if err != nil {
+		return nil, err
+	}
+	table.Name = name
+	if schema != "" && !strings.EqualFold(schema, "public") {
+		table.Schema = schema
+	}
+	if !p.peek().is("(") {
+		p.warn("", "CREATE TABLE", "only CREATE TABLE with column definitions is supported; table ignored")
+		return nil, nil
+	}
+	p.next()
+
+	for {
+		if err := p.tableElement(); err != nil {
+			return nil, err
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	p.tableOptions()
+	p.finishTable()
+	return table, nil
+}
+
+// constraintStarts are the words starting a table element other than a
+// column definition; they are reserved where they apply.
+var constraintStarts = map[string]bool{"CONSTRAINT": true, "PRIMARY": true, "UNIQUE": true, "CHECK": true, "FOREIGN": true}
+
+var (
+	mysqlConstraintStarts    = map[string]bool{"KEY": true, "INDEX": true, "FULLTEXT": true, "SPATIAL": true}
+	postgresConstraintStarts = map[string]bool{"EXCLUDE": true, "LIKE": true}
+)
+
+func (p *importer) tableElement() error {
+	if t := p.peek(); t.kind == tokenWord {
+		word := strings.ToUpper(t.text)
+		if constraintStarts[word] || (p.s.mysql && mysqlConstraintStarts[word]) || (!p.s.mysql && postgresConstraintStarts[word]) {
+			return p.tableConstraint()
+		}
+	}
+	return p.column()
+}
+
+func (p *importer) column() error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	column := parser.Column{Name: name}
+	if err := p.columnType(&column); err != nil {
+		return err
+	}
+
+	for {
+		t := p.peek()
+		if t.is(",") || t.is(")") || t.kind == tokenEOF {
+			break
+		}
+		if err := p.columnConstraint(&column); err != nil {
+			return err
+		}
+	}
+	p.table.Columns = append(p.table.Columns, column)
+	return nil
+}
+
+// typeStops are the words ending a type name.
+var typeStops = map[string]bool{
+	"CONSTRAINT": true, "PRIMARY": true, "KEY": true, "NOT": true, "NULL": true, "UNIQUE": true,
+	"CHECK": true, "DEFAULT": true, "COLLATE": true, "REFERENCES": true, "GENERATED": true,
+	"AUTO_INCREMENT": true, "COMMENT": true, "ON": true, "CHARSET": true, "AS": true,
+	"VISIBLE": true, "INVISIBLE": true, "STORAGE": true, "COLUMN_FORMAT": true,
+}
+
+func (p *importer) columnType(column *parser.Column) error {
+	var words []string
+	args := ""
+	array := false
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokenWord && !typeStops[strings.ToUpper(t.text)] && !(t.is("CHARACTER") && p.peekAt(1).is("SET")):
+			words = append(words, strings.ToLower(t.text))
+			p.next()
+			continue
+		case t.kind == tokenQuoted && len(words) == 0:
+			words = append(words, strings.ToLower(t.value))
+			p.next()
+			continue
+		case t.is(".") && len(words) > 0:
+			// A schema-qualified type name: keep the type.
+			p.next()
+			words = words[:len(words)-1]
+			continue
+		case t.is("(") && args == "":
+			text, err := p.parenthesized()
+			if err != nil {
+				return err
+			}
+			args = strings.TrimSpace(text)
+			if args == "" {
+				args = " "
+			}
+			continue
+		case t.is("["):
+			p.next()
+			p.skipTo("]")
+			if err := p.expect("]"); err != nil {
+				return err
+			}
+			array = true
+			continue
+		}
+		break
+	}
+	typeName := strings.Join(words, " ")
+	if array {
+		p.warn(column.Name, "array", "the %s[] array is stored as TEXT", typeName)
+		column.Type = "TEXT"
+		return nil
+	}
+	if p.s.mysql {
+		p.mysqlType(column, typeName, strings.TrimSpace(args))
+	} else {
+		p.postgresType(column, typeName, strings.TrimSpace(args))
+	}
+	return nil
+}
+
+// setType sets the declared type of column, with arguments such as "10,2".
+func setType(column *parser.Column, typ, args string) {
+	column.Type = typ
+	column.Length = strings.ReplaceAll(args, " ", "")
+}
+
+func (p *importer) postgresType(column *parser.Column, typeName, args string) {
+	switch typeName {
+	case "smallserial", "serial", "bigserial", "serial2", "serial4", "serial8":
+		p.identity[strings.ToLower(column.Name)] = true
+		setType(column, "INTEGER", "")
+	case "smallint", "integer", "int", "bigint", "int2", "int4", "int8":
+		setType(column, "INTEGER", "")
+	case "character varying", "varchar":
+		setType(column, "VARCHAR", args)
+	case "character", "char", "bpchar":
+		setType(column, "CHAR", args)
+	case "text", "name":
+		setType(column, "TEXT", "")
+	case "citext":
+		setType(column, "TEXT", "")
+		column.CollateName = "NOCASE"
+		p.warn(column.Name, "citext", "citext is stored as TEXT COLLATE NOCASE, which only folds the case of ASCII letters")
+	case "numeric", "decimal":
+		setType(column, "NUMERIC", args)
+	case "real", "float4", "double precision", "float8", "float":
+		setType(column, "REAL", "")
+	case "boolean", "bool":
+		setType(column, "BOOLEAN", "")
+	case "date":
+		setType(column, "DATE", "")
+	case "timestamp", "timestamp without time zone":
+		setType(column, "DATETIME", "")
+	case "timestamptz", "timestamp with time zone":
+		setType(column, "DATETIME", "")
+		p.warn(column.Name, "time zone", "%s is stored as DATETIME, without a time zone", typeName)
+	case "time", "time without time zone":
+		setType(column, "TIME", "")
+	case "bytea":
+		setType(column, "BLOB", "")
+	case "uuid", "json", "jsonb", "xml", "inet", "cidr", "macaddr":
+		setType(column, "TEXT", "")
+	default:
+		if values, ok := p.enums[typeName]; ok {
+			setType(column, "TEXT", "")
+			column.CheckExpr = inList(column.Name, values)
+			return
+		}
+		setType(column, "TEXT", "")
+		p.warn(column.Name, "type", "type %s has no SQLite equivalent and is stored as TEXT", typeName)
+	}
+}
+
+func (p *importer) mysqlType(column *parser.Column, typeName, args string) {
+	words := strings.Fields(typeName)
+	base := ""
+	if len(words) > 0 {
+		base = words[0]
+	}
+	switch base {
+	case "tinyint":
+		if args == "1" {
+			setType(column, "BOOLEAN", "")
+			return
+		}
+		setType(column, "INTEGER", "")
+	case "bool", "boolean":
+		setType(column, "BOOLEAN", "")
+	case "smallint", "mediumint", "int", "integer", "bigint", "year", "bit":
+		setType(column, "INTEGER", "")
+	case "serial":
+		p.identity[strings.ToLower(column.Name)] = true
+		setType(column, "INTEGER", "")
+	case "decimal", "dec", "numeric", "fixed":
+		setType(column, "NUMERIC", args)
+	case "float", "double", "real":
+		setType(column, "REAL", "")
+	case "char", "nchar":
+		setType(column, "CHAR", args)
+	case "varchar", "nvarchar":
+		setType(column, "VARCHAR", args)
+	case "tinytext", "text", "mediumtext", "longtext", "json":
+		setType(column, "TEXT", "")
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		setType(column, "BLOB", "")
+	case "date":
+		setType(column, "DATE", "")
+	case "datetime", "timestamp":
+		setType(column, "DATETIME", "")
+	case "time":
+		setType(column, "TIME", "")
+	case "enum":
+		setType(column, "TEXT", "")
+		values, ok := stringValues(args)
+		if !ok {
+			p.warn(column.Name, "ENUM", "the ENUM values could not be read and are not checked")
+			return
+		}
+		column.CheckExpr = inList(column.Name, values)
+	case "set":
+		setType(column, "TEXT", "")
+		p.warn(column.Name, "SET", "SET is stored as TEXT without checking its values")
+	default:
+		setType(column, "BLOB", "")
+		p.warn(column.Name, "type", "type %s has no SQLite equivalent and is stored as BLOB", typeName)
+	}
+	if strings.Contains(typeName, "unsigned") || strings.Contains(typeName, "zerofill") {
+		p.warn(column.Name, "UNSIGNED", "SQLite has no unsigned types; the column accepts negative values")
+	}
+}
+
+// stringValues reads the values of an ENUM type argument list.
+func stringValues(args string) ([]string, bool) {
+	s := &scanner{src: args, mysql: true}
+	tokens, err := s.scan()
+	if err != nil {
+		return nil, false
+	}
+	var values []string
+	for i, t := range tokens[:len(tokens)-1] {
+		switch {
+		case i%2 == 0 && t.kind == tokenString:
+			values = append(values, t.value)
+		case i%2 == 1 && t.is(","):
+		default:
+			return nil, false
+		}
+	}
+	return values, len(values) > 0
+}
+
+func inList(column string, values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	return parser.QuoteIdentifier(column) + " IN (" + strings.Join(quoted, ", ") + ")"
+}
+
+func (p *importer) columnConstraint(column *parser.Column) error {
+	switch {
+	case p.accept("CONSTRAINT"):
+		name, err := p.name()
+		column.ConstraintName = name
+		return err
+	case p.accept("NOT", "NULL"):
+		column.IsNotnull = true
+	case p.accept("NULL"):
+	case p.accept("PRIMARY", "KEY"), p.s.mysql && p.accept("KEY"):
+		column.IsPrimaryKey = true
+		if p.accept("DESC") {
+			column.PkOrder = parser.ORDER_DESC
+		}
+		p.accept("ASC")
+	case p.accept("UNIQUE"):
+		column.IsUnique = true
+		if !p.accept("KEY") {
+			p.accept("INDEX")
+		}
+	case p.accept("CHECK"):
+		expr, err := p.parenthesized()
+		if err != nil {
+			return err
+		}
+		p.checkExpr(column.Name, expr)
+		p.accept("NOT")
+		p.accept("ENFORCED")
+		if column.CheckExpr != "" {
+			expr = "(" + column.CheckExpr + ") AND (" + expr + ")"
+		}
+		column.CheckExpr = strings.TrimSpace(expr)
+	case p.accept("DEFAULT"):
+		return p.defaultValue(column)
+	case p.accept("COLLATE"):
+		name, err := p.name()
+		column.CollateName = p.collation(column.Name, name)
+		return err
+	case p.accept("CHARACTER", "SET"), p.accept("CHARSET"):
+		_, err := p.name()
+		return err
+	case p.accept("COMMENT"):
+		p.next()
+	case p.accept("AUTO_INCREMENT"):
+		p.identity[strings.ToLower(column.Name)] = true
+	case p.accept("GENERATED", "ALWAYS", "AS", "IDENTITY"), p.accept("GENERATED", "BY", "DEFAULT", "AS", "IDENTITY"):
+		p.identity[strings.ToLower(column.Name)] = true
+		if p.peek().is("(") {
+			_, err := p.parenthesized()
+			return err
+		}
+	case p.accept("GENERATED", "ALWAYS", "AS"), p.accept("AS"):
+		if _, err := p.parenthesized(); err != nil {
+			return err
+		}
+		if !p.accept("STORED") && !p.accept("VIRTUAL") {
+			p.accept("PERSISTENT")
+		}
+		p.warn(column.Name, "generated column", "the generated column expression is dropped")
+	case p.accept("ON", "UPDATE"):
+		p.skipTo(",", ")")
+		p.warn(column.Name, "ON UPDATE", "ON UPDATE defaults need a trigger in SQLite and are dropped")
+	case p.accept("REFERENCES"):
+		fk, err := p.references(column.Name)
+		column.ForeignKeyClause = fk
+		return err
+	case p.accept("VISIBLE"), p.accept("INVISIBLE"):
+	default:
+		start := p.pos
+		p.next()
+		p.skipTo(",", ")")
+		p.warn(column.Name, "column option", "%s is not supported and is dropped", p.text(start))
+	}
+	return nil
+}
+
+// checkExpr warns about PostgreSQL casts in a CHECK expression.
+func (p *importer) checkExpr(column, expr string) {
+	if strings.Contains(expr, "::") {
+		p.warn(column, "cast", "the CHECK expression %s uses PostgreSQL casts and must be rewritten", expr)
+	}
+}
+
+func (p *importer) defaultValue(column *parser.Column) error {
+	start := p.pos
+	parenthesized := p.accept("(")
+	t := p.next()
+	value, ok, numeric := "", true, false
+	switch {
+	case t.kind == tokenString:
+		value = t.value
+	case t.kind == tokenNumber:
+		value, numeric = t.text, true
+	case t.is("-") || t.is("+"):
+		n := p.next()
+		value, ok, numeric = t.text+n.text, n.kind == tokenNumber, true
+	case t.is("TRUE"), t.is("FALSE"), t.is("CURRENT_DATE"), t.is("CURRENT_TIME"):
+		value = strings.ToUpper(t.text)
+	case t.is("CURRENT_TIMESTAMP"), t.is("LOCALTIMESTAMP"), t.is("NOW"):
+		value = "CURRENT_TIMESTAMP"
+		if p.peek().is("(") {
+			if _, err := p.parenthesized(); err != nil {
+				return err
+			}
+		}
+	case t.is("NULL"):
+	case t.is("NEXTVAL"):
+		// The default of a column declared with its own sequence.
+		p.identity[strings.ToLower(column.Name)] = true
+		_, err := p.parenthesized()
+		return err
+	default:
+		ok = false
+	}
+	// PostgreSQL casts of literals, such as 'a'::character varying.
+	for ok && p.accept("::") {
+		var scratch parser.Column
+		if err := p.columnType(&scratch); err != nil {
+			return err
+		}
+	}
+	if ok && parenthesized {
+		ok = p.accept(")")
+	}
+	next := p.peek()
+	if !ok || !(next.is(",") || next.is(")") || next.kind == tokenEOF || (next.kind == tokenWord && typeStops[strings.ToUpper(next.text)])) {
+		p.pos = start
+		if p.accept("(") {
+			p.skipTo(")")
+			p.accept(")")
+		}
+		for next := p.peek(); !(next.is(",") || next.is(")") || next.kind == tokenEOF || (next.kind == tokenWord && typeStops[strings.ToUpper(next.text)])); next = p.peek() {
+			if next.is("(") {
+				p.parenthesized()
+				continue
+			}
+			p.next()
+		}
+		p.warn(column.Name, "DEFAULT", "the DEFAULT expression %s is dropped", p.text(start))
+		return nil
+	}
+	column.DefaultExpr = value
+	if numeric {
+		// The parser only takes string literals and names as defaults.
+		p.warn(column.Name, "numeric DEFAULT", "the DEFAULT %s is written as the string '%s', which only columns with INTEGER, REAL or NUMERIC affinity store as a number", value, value)
+	}
+	return nil
+}
+
+// collation maps a collation name to SQLite: binary collations to the
+// default, case-insensitive ones to NOCASE.
+func (p *importer) collation(column, name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case lower == "c", lower == "posix", lower == "binary", strings.HasSuffix(lower, "_bin"):
+		return ""
+	case strings.HasSuffix(lower, "_ci"):
+		p.warn(column, "COLLATE", "%s is mapped to NOCASE, which only folds the case of ASCII letters", name)
+		return "NOCASE"
+	}
+	p.warn(column, "COLLATE", "collation %s has no SQLite equivalent and is dropped", name)
+	return ""
+}
+
+func (p *importer) references(column string) (*parser.ForeignKey, error) {
+	_, table, err := p.qualifiedName()
+	if err != nil {
+		return nil, err
+	}
+	fk := &parser.ForeignKey{Table: table}
+	if p.peek().is("(") {
+		if fk.ColumnName, err = p.nameList(); err != nil {
+			return nil, err
+		}
+		fk.NumColumns = len(fk.ColumnName)
+	}
+	for {
+		switch {
+		case p.accept("MATCH"):
+			match, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			fk.Match = strings.ToUpper(match)
+		case p.accept("ON", "DELETE"):
+			if fk.OnDelete, err = p.fkAction(column); err != nil {
+				return nil, err
+			}
+		case p.accept("ON", "UPDATE"):
+			if fk.OnUpdate, err = p.fkAction(column); err != nil {
+				return nil, err
+			}
+		case p.accept("DEFERRABLE"):
+			fk.Deferrable = parser.DEFTYPE_DEFERRABLE
+		case p.accept("NOT", "DEFERRABLE"):
+			fk.Deferrable = parser.DEFTYPE_NOTDEFERRABLE
+		case p.accept("INITIALLY", "DEFERRED"):
+			if fk.Deferrable == parser.DEFTYPE_NOTDEFERRABLE {
+				fk.Deferrable = parser.DEFTYPE_NOTDEFERRABLE_INITIALLY_DEFERRED
+			} else {
+				fk.Deferrable = parser.DEFTYPE_DEFERRABLE_INITIALLY_DEFERRED
+			}
+		case p.accept("INITIALLY", "IMMEDIATE"):
+			if fk.Deferrable == parser.DEFTYPE_NOTDEFERRABLE {
+				fk.Deferrable = parser.DEFTYPE_NOTDEFERRABLE_INITIALLY_IMMEDIATE
+			} else {
+				fk.Deferrable = parser.DEFTYPE_DEFERRABLE_INITIALLY_IMMEDIATE
+			}
+		default:
+			return fk, nil
+		}
+	}
+}
+
+func (p *importer) fkAction(column string) (parser.FkAction, error) {
+	switch {
+	case p.accept("CASCADE"):
+		return parser.FKACTION_CASCADE, nil
+	case p.accept("RESTRICT"):
+		return parser.FKACTION_RESTRICT, nil
+	case p.accept("NO", "ACTION"):
+		return parser.FKACTION_NOACTION, nil
+	case p.accept("SET", "NULL"):
+		if p.peek().is("(") {
+			p.parenthesized()
+			p.warn(column, "SET NULL columns", "SET NULL applies to all the foreign key columns in SQLite")
+		}
+		return parser.FKACTION_SETNULL, nil
+	case p.accept("SET", "DEFAULT"):
+		return parser.FKACTION_SETDEFAULT, nil
+	}
+	return parser.FKACTION_NONE, p.errorf("expected a foreign key action")
+}
+
+func (p *importer) tableConstraint() error {
+	constraint := parser.TableConstraint{}
+	if p.accept("CONSTRAINT") {
+		if !p.peek().is("PRIMARY") && !p.peek().is("UNIQUE") && !p.peek().is("CHECK") && !p.peek().is("FOREIGN") {
+			name, err := p.name()
+			if err != nil {
+				return err
+			}
+			constraint.Name = name
+		}
+	}
+
+	var err error
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		constraint.Type = parser.TABLECONSTRAINT_PRIMARYKEY
+		err = p.indexedColumns(&constraint)
+	case p.accept("UNIQUE"):
+		constraint.Type = parser.TABLECONSTRAINT_UNIQUE
+		if !p.accept("KEY") {
+			p.accept("INDEX")
+		}
+		if p.accept("NULLS", "NOT", "DISTINCT") {
+			p.warn("", "NULLS NOT DISTINCT", "SQLite treats NULLs in UNIQUE constraints as distinct")
+		}
+		p.accept("NULLS", "DISTINCT")
+		if name := p.peek(); name.kind == tokenWord && !name.is("USING") || name.kind == tokenQuoted {
+			// MySQL index name.
+			n, _ := p.name()
+			if constraint.Name == "" {
+				constraint.Name = n
+			}
+		}
+		err = p.indexedColumns(&constraint)
+	case p.accept("CHECK"):
+		constraint.Type = parser.TABLECONSTRAINT_CHECK
+		constraint.CheckExpr, err = p.parenthesized()
+		constraint.CheckExpr = strings.TrimSpace(constraint.CheckExpr)
+		p.checkExpr("", constraint.CheckExpr)
+		p.accept("NOT")
+		p.accept("ENFORCED")
+		p.accept("NO", "INHERIT")
+	case p.accept("FOREIGN", "KEY"):
+		constraint.Type = parser.TABLECONSTRAINT_FOREIGNKEY
+		if !p.peek().is("(") {
+			// MySQL index name.
+			if _, err := p.name(); err != nil {
+				return err
+			}
+		}
+		if constraint.ForeignKeyName, err = p.nameList(); err != nil {
+			return err
+		}
+		constraint.ForeignKeyNum = len(constraint.ForeignKeyName)
+		if err := p.expect("REFERENCES"); err != nil {
+			return err
+		}
+		constraint.ForeignKeyClause, err = p.references("")
+	default:
+		start := p.pos
+		p.skipTo(",", ")")
+		feature := strings.ToUpper(p.tokens[start].text)
+		if feature == "KEY" || feature == "INDEX" || feature == "FULLTEXT" || feature == "SPATIAL" {
+			p.warn("", "INDEX", "%s is dropped; create the index with CREATE INDEX", p.text(start))
+		} else {
+			p.warn("", feature, "%s is not supported and is dropped", p.text(start))
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	p.table.Constraints = append(p.table.Constraints, constraint)
+	return nil
+}
+
+func (p *importer) indexedColumns(constraint *parser.TableConstraint) error {
+	p.indexOptions()
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for {
+		if p.peek().is("(") {
+			start := p.pos
+			p.parenthesized()
+			p.warn("", "expression index", "the indexed expression %s is dropped", p.text(start))
+		} else {
+			name, err := p.name()
+			if err != nil {
+				return err
+			}
+			column := parser.IdxColumn{Name: name}
+			if p.peek().is("(") {
+				start := p.pos
+				p.parenthesized()
+				p.warn(name, "prefix length", "the key prefix length %s is dropped", p.text(start))
+			}
+			if p.accept("COLLATE") {
+				collation, err := p.name()
+				if err != nil {
+					return err
+				}
+				column.CollateName = p.collation(name, collation)
+			}
+			if p.accept("ASC") {
+				column.Order = parser.ORDER_ASC
+			} else if p.accept("DESC") {
+				column.Order = parser.ORDER_DESC
+			}
+			if p.accept("NULLS", "FIRST") || p.accept("NULLS", "LAST") {
+				p.warn(name, "NULLS FIRST", "SQLite always sorts NULLs first; the NULLS clause is dropped")
+			}
+			constraint.IndexedColumns = append(constraint.IndexedColumns, column)
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return err
+	}
+	constraint.NumIndexed = len(constraint.IndexedColumns)
+	p.indexOptions()
+	return nil
+}
+
+// indexOptions skips the index options of PostgreSQL and MySQL key
+// definitions, which have no meaning in SQLite.
+func (p *importer) indexOptions() {
+	for {
+		switch {
+		case p.accept("USING", "INDEX", "TABLESPACE"), p.accept("USING"), p.accept("KEY_BLOCK_SIZE"), p.accept("COMMENT"):
+			p.accept("=")
+			p.next()
+		case p.accept("INCLUDE"), p.accept("WITH"):
+			p.parenthesized()
+		case p.accept("VISIBLE"), p.accept("INVISIBLE"):
+		default:
+			return
+		}
+	}
+}
+
+// silentOptions are the MySQL table options without consequence for an
+// SQLite table.
+var silentOptions = map[string]bool{
+	"ENGINE": true, "CHARSET": true, "CHARACTER": true, "AUTO_INCREMENT": true, "COMMENT": true,
+	"ROW_FORMAT": true, "STATS_PERSISTENT": true, "STATS_AUTO_RECALC": true, "STATS_SAMPLE_PAGES": true,
+	"KEY_BLOCK_SIZE": true, "AVG_ROW_LENGTH": true, "CHECKSUM": true, "PACK_KEYS": true,
+}
+
+func (p *importer) tableOptions() {
+	for {
+		t := p.peek()
+		if t.is(";") || t.kind == tokenEOF {
+			return
+		}
+		start := p.pos
+		switch {
+		case p.accept("WITHOUT", "OIDS"):
+			continue
+		case p.accept(","), p.accept("DEFAULT"):
+			continue
+		case p.accept("COLLATE"):
+			p.accept("=")
+			if name, err := p.name(); err == nil && strings.HasSuffix(strings.ToLower(name), "_ci") {
+				p.warn("", "COLLATE", "the table compares text with %s; SQLite columns default to case-sensitive BINARY", name)
+			}
+			continue
+		case p.s.mysql && silentOptions[strings.ToUpper(t.text)] && t.kind == tokenWord:
+			p.next()
+			p.accept("SET")
+			p.accept("=")
+			p.next()
+			continue
+		case p.accept("PARTITION", "BY"), p.accept("INHERITS"), p.accept("AS"):
+			// Everything up to the end of the statement.
+			p.skipTo(";")
+		default:
+			p.next()
+			if p.accept("=") || p.peek().kind != tokenWord {
+				if p.peek().is("(") {
+					p.parenthesized()
+				} else if !p.peek().is(";") {
+					p.next()
+				}
+			}
+		}
+		p.warn("", "table option", "%s is dropped", p.text(start))
+	}
+}
+
+// finishTable turns the integer primary key generated by the database into
+// the SQLite rowid alias, marks the primary key columns NOT NULL as the
+// other databases do, and fills in the counts of the table model.
+func (p *importer) finishTable() {
+	table := p.table
+	key := table.PrimaryKey()
+	for i := range table.Columns {
+		column := &table.Columns[i]
+		if !p.identity[strings.ToLower(column.Name)] {
+			continue
+		}
+		if len(key) != 1 || !strings.EqualFold(key[0], column.Name) || column.Type != "INTEGER" {
+			p.warn(column.Name, "identity", "SQLite only generates values for the INTEGER PRIMARY KEY; the column must be filled in")
+			continue
+		}
+		if !column.IsPrimaryKey {
+			// Move a PRIMARY KEY table constraint onto the column, where
+			// AUTOINCREMENT is allowed.
+			for j, constraint := range table.Constraints {
+				if constraint.Type == parser.TABLECONSTRAINT_PRIMARYKEY {
+					column.IsPrimaryKey = true
+					column.PkOrder = constraint.IndexedColumns[0].Order
+					table.Constraints = append(table.Constraints[:j], table.Constraints[j+1:]...)
+					break
+				}
+			}
+		}
+		column.IsAutoincrement = true
+	}
+
+	for _, name := range table.PrimaryKey() {
+		if column := table.Column(name); column != nil && !column.IsAutoincrement {
+			column.IsNotnull = true
+		}
+	}
+	table.NumColumns = len(table.Columns)
+	table.NumConstraint = len(table.Constraints)
+}
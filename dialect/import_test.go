@@ -0,0 +1,146 @@
+package dialect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+func formatAll(schema *parser.Schema) []string {
+	var list []string
+	for _, table := range schema.Tables {
+		list = append(list, parser.Format(table))
+	}
+	return list
+}
+
+func TestFromPostgres(t *testing.T) {
+	schema, warnings, err := FromPostgres(`
+SET statement_timeout = 0;
+CREATE TYPE public.mood AS ENUM ('sad', 'ok', 'happy');
+CREATE TABLE public.users (
+    id bigserial PRIMARY KEY,
+    email citext NOT NULL UNIQUE,
+    name character varying(100) DEFAULT 'anon'::character varying NOT NULL,
+    mood mood,
+    tags text[],
+    balance numeric(10,2) DEFAULT 0.00,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    flags integer DEFAULT (1 + 2),
+    CONSTRAINT users_name_check CHECK ((char_length(name) > 0))
+);
+CREATE UNLOGGED TABLE IF NOT EXISTS "Order Items" (
+    order_id integer NOT NULL,
+    user_id bigint REFERENCES users(id) ON DELETE SET NULL DEFERRABLE INITIALLY DEFERRED,
+    item text COLLATE "C",
+    qty int GENERATED ALWAYS AS IDENTITY,
+    PRIMARY KEY (order_id, item DESC NULLS LAST),
+    EXCLUDE USING gist (item WITH =)
+) PARTITION BY RANGE (order_id);
+CREATE TABLE t2 (id integer GENERATED BY DEFAULT AS IDENTITY (START WITH 10), PRIMARY KEY (id)) WITH (fillfactor=70);
+CREATE INDEX users_email ON users (email);
+CREATE FUNCTION f() RETURNS trigger AS $$ BEGIN; RETURN NEW; END; $$ LANGUAGE plpgsql;
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`CREATE TABLE users (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  email TEXT NOT NULL UNIQUE COLLATE NOCASE,
+  name VARCHAR(100) NOT NULL DEFAULT 'anon',
+  mood TEXT CHECK (mood IN ('sad', 'ok', 'happy')),
+  tags TEXT,
+  balance NUMERIC(10,2) DEFAULT '0.00',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  flags INTEGER,
+  CONSTRAINT users_name_check CHECK ((char_length(name) > 0))
+);`, `CREATE TABLE IF NOT EXISTS "Order Items" (
+  order_id INTEGER NOT NULL,
+  user_id INTEGER REFERENCES users (id) ON DELETE SET NULL DEFERRABLE INITIALLY DEFERRED,
+  item TEXT NOT NULL,
+  qty INTEGER,
+  PRIMARY KEY (order_id, item DESC)
+);`, `CREATE TABLE t2 (
+  id INTEGER PRIMARY KEY AUTOINCREMENT
+);`}, formatAll(schema))
+	assert.Equal(t, []string{
+		"statement", "citext", "array", "numeric DEFAULT", "time zone", "DEFAULT",
+		"UNLOGGED", "NULLS FIRST", "EXCLUDE", "table option", "identity",
+		"table option", "statement", "statement",
+	}, features(warnings))
+	assert.Equal(t, "CREATE INDEX statement ignored", warnings[12].String())
+	assert.Equal(t, 8, schema.Table("users").NumColumns)
+	assert.Equal(t, 2, schema.Table("Order Items").Constraints[0].NumIndexed)
+}
+
+func TestFromMySQL(t *testing.T) {
+	schema, warnings, err := FromMySQL("CREATE TABLE `users` (\n" +
+		"  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,\n" +
+		"  `email` varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'login',\n" +
+		"  `status` enum('active','it''s \\'x\\'') NOT NULL DEFAULT 'active',\n" +
+		"  `is_admin` tinyint(1) NOT NULL DEFAULT '0',\n" +
+		"  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n" +
+		"  `perms` set('r','w'),\n" +
+		"  `geo` point,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  UNIQUE KEY `users_email_unique` (`email`(191)),\n" +
+		"  KEY `idx_status` (`status`),\n" +
+		"  CONSTRAINT `fk_x` FOREIGN KEY (`status`) REFERENCES `statuses` (`name`) ON UPDATE CASCADE\n" +
+		") ENGINE=InnoDB AUTO_INCREMENT=42 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci ROW_FORMAT=DYNAMIC COMMENT='users';\n" +
+		"# comment\nINSERT INTO users VALUES (1);\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`CREATE TABLE users (
+  id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+  email VARCHAR(255) NOT NULL COLLATE NOCASE,
+  status TEXT NOT NULL CHECK (status IN ('active', 'it''s ''x''')) DEFAULT 'active',
+  is_admin BOOLEAN NOT NULL DEFAULT '0',
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  perms TEXT,
+  geo BLOB,
+  CONSTRAINT users_email_unique UNIQUE (email),
+  CONSTRAINT fk_x FOREIGN KEY (status) REFERENCES statuses (name) ON UPDATE CASCADE
+);`}, formatAll(schema))
+	assert.Equal(t, []string{
+		"UNSIGNED", "COLLATE", "ON UPDATE", "SET", "type", "prefix length", "INDEX", "COLLATE", "statement",
+	}, features(warnings))
+
+	users := schema.Tables[0]
+	assert.Equal(t, 7, users.NumColumns)
+	assert.Equal(t, 2, users.NumConstraint)
+	assert.Equal(t, 1, users.Constraints[1].ForeignKeyNum)
+	assert.Equal(t, 1, users.Constraints[1].ForeignKeyClause.NumColumns)
+}
+
+func TestImportNumericDefaults(t *testing.T) {
+	schema, warnings, err := FromMySQL("CREATE TABLE t (a int DEFAULT 0, b double DEFAULT -1.5, c blob DEFAULT '0');")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`CREATE TABLE t (
+  a INTEGER DEFAULT '0',
+  b REAL DEFAULT '-1.5',
+  c BLOB DEFAULT '0'
+);`}, formatAll(schema))
+	assert.Equal(t, []string{"numeric DEFAULT", "numeric DEFAULT"}, features(warnings))
+	assert.Equal(t, "t.b: the DEFAULT -1.5 is written as the string '-1.5', which only columns with INTEGER, REAL or NUMERIC affinity store as a number", warnings[1].String())
+}
+
+func TestImportErrors(t *testing.T) {
+	for _, test := range []struct {
+		sql, err string
+		mysql    bool
+	}{
+		{"CREATE TABLE t (a int", `line 1: expected ), found "end of input"`, false},
+		{"CREATE TABLE t (\n a int REFERENCES u ON DELETE explode)", `line 2: expected a foreign key action, found "explode"`, false},
+		{"CREATE TABLE t (a text DEFAULT 'x)", "line 1: unterminated '", true},
+		{"/* open", "line 1: unterminated comment", false},
+	} {
+		var err error
+		if test.mysql {
+			_, _, err = FromMySQL(test.sql)
+		} else {
+			_, _, err = FromPostgres(test.sql)
+		}
+		if assert.Error(t, err, test.sql) {
+			assert.Equal(t, test.err, err.Error())
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+type MySQLOptions struct {
+	// Engine, Charset and Collation are the table options; "InnoDB",
+	// "utf8mb4" and Charset + "_bin" if empty. A binary collation keeps the
+	// comparisons of SQLite's default BINARY collation.
+	Engine    string
+	Charset   string
+	Collation string
+}
+
+// keyTextLength is the length of the VARCHAR replacing a TEXT column that is
+// part of a key, since MySQL cannot index TEXT without a prefix length.
+const keyTextLength = 255
+
+// ToMySQL returns MySQL (or MariaDB) CREATE TABLE statements for tables and
+// the warnings for every construct that was dropped or changed in meaning.
+func ToMySQL(options MySQLOptions, tables ...*parser.Table) (string, []Warning) {
+	return ToMySQLSchema(options, &parser.Schema{Tables: tables})
+}
+
+// ToMySQLSchema is ToMySQL for a whole schema: the CREATE INDEX statements
+// of schema follow the tables, and its views and triggers, which are not
+// translated, are reported.
+func ToMySQLSchema(options MySQLOptions, schema *parser.Schema) (string, []Warning) {
+	if options.Engine == "" {
+		options.Engine = "InnoDB"
+	}
+	if options.Charset == "" {
+		options.Charset = "utf8mb4"
+	}
+	if options.Collation == "" {
+		options.Collation = options.Charset + "_bin"
+	}
+
+	t := translator{conflictHint: "use INSERT IGNORE, REPLACE or ON DUPLICATE KEY UPDATE in MySQL", tables: schema.Tables, indexes: schema.Indexes}
+	var b strings.Builder
+	for i, table := range schema.Tables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		t.mysqlTable(&b, table, &options)
+	}
+	for i, index := range schema.Indexes {
+		if i == 0 && b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		t.mysqlIndex(&b, index)
+	}
+	t.skipUntranslated(schema)
+	return b.String(), t.warnings
+}
+
+func (t *translator) mysqlIndex(b *strings.Builder, index *parser.Index) {
+	table := &parser.Table{Name: index.Table}
+	if index.Where != "" {
+		if index.IsUnique {
+			t.warn(table, "", "partial index", "MySQL has no partial indexes; unique index %s is skipped, since it would reject more rows", index.Name)
+			return
+		}
+		t.warn(table, "", "partial index", "MySQL has no partial indexes; index %s covers every row", index.Name)
+	}
+	if index.IsIfNotExists {
+		t.warn(table, "", "IF NOT EXISTS", "MySQL has no CREATE INDEX IF NOT EXISTS; it is dropped from index %s", index.Name)
+	}
+	b.WriteString("CREATE ")
+	if index.IsUnique {
+		b.WriteString("UNIQUE ")
+	}
+	fmt.Fprintf(b, "INDEX %s ON %s (%s);\n", myQuote(index.Name), qualifiedName(index.Schema, index.Table, myQuote),
+		t.mysqlKeyColumns(table, index.Columns))
+}
+
+func (t *translator) mysqlTable(b *strings.Builder, table *parser.Table, options *MySQLOptions) {
+	keys := map[string]bool{}
+	for _, name := range table.PrimaryKey() {
+		keys[strings.ToLower(name)] = true
+	}
+	for _, column := range table.Columns {
+		if column.IsUnique || column.ForeignKeyClause != nil {
+			keys[strings.ToLower(column.Name)] = true
+		}
+	}
+	for _, constraint := range table.Constraints {
+		for _, column := range constraint.IndexedColumns {
+			keys[strings.ToLower(column.Name)] = true
+		}
+		for _, name := range constraint.ForeignKeyName {
+			keys[strings.ToLower(name)] = true
+		}
+	}
+	for _, index := range t.indexes {
+		if strings.EqualFold(index.Table, table.Name) {
+			for _, column := range index.Columns {
+				if column.Expr == nil {
+					keys[strings.ToLower(column.Name)] = true
+				}
+			}
+		}
+	}
+	if table.PrimaryKey() == nil && !table.IsWithoutRowid {
+		t.warn(table, "", "rowid", "the table has no primary key, and MySQL has no rowid to identify its rows")
+	}
+
+	var lines []string
+	for i := range table.Columns {
+		column := &table.Columns[i]
+		lines = append(lines, t.mysqlColumn(table, column, keys[strings.ToLower(column.Name)], options))
+	}
+	// InnoDB ignores REFERENCES clauses on columns: they must be written as
+	// table constraints.
+	for i := range table.Columns {
+		column := &table.Columns[i]
+		for _, constraint := range column.ConstraintList() {
+			if constraint.Type != parser.COLUMNCONSTRAINT_FOREIGNKEY || constraint.ForeignKeyClause == nil {
+				continue
+			}
+			line := fmt.Sprintf("FOREIGN KEY (%s) %s", myQuote(column.Name), t.mysqlForeignKey(table, column.Name, constraint.ForeignKeyClause))
+			if constraint.Name != "" {
+				line = "CONSTRAINT " + myQuote(constraint.Name) + " " + line
+			}
+			lines = append(lines, line)
+		}
+	}
+	for i := range table.Constraints {
+		lines = append(lines, t.mysqlConstraint(table, &table.Constraints[i]))
+	}
+
+	b.WriteString("CREATE ")
+	if table.IsTemporary {
+		b.WriteString("TEMPORARY ")
+	}
+	b.WriteString("TABLE ")
+	if table.IsIfNotExists {
+		b.WriteString("IF NOT EXISTS ")
+	}
+	b.WriteString(qualifiedName(table.Schema, table.Name, myQuote))
+	b.WriteString(" (\n")
+	b.WriteString(join(lines))
+	fmt.Fprintf(b, ") ENGINE=%s DEFAULT CHARSET=%s COLLATE=%s;\n", options.Engine, options.Charset, options.Collation)
+}
+
+func (t *translator) mysqlColumn(table *parser.Table, column *parser.Column, key bool, options *MySQLOptions) string {
+	var b strings.Builder
+	b.WriteString(myQuote(column.Name))
+	b.WriteString(" ")
+	typ := t.mysqlType(table, column, key)
+	b.WriteString(typ)
+
+	if collation := t.mysqlCollation(table, column.Name, column.CollateName, options); collation != "" {
+		b.WriteString(" COLLATE ")
+		b.WriteString(collation)
+	}
+	if column.IsNotnull || isRowidAlias(table, column) {
+		b.WriteString(" NOT NULL")
+		t.conflict(table, column.Name, column.NotNullConflictClause)
+	} else if column.IsPrimaryKey && !table.IsWithoutRowid {
+		t.warn(table, column.Name, "nullable PRIMARY KEY", "SQLite allows NULL in the primary key of a rowid table, MySQL does not")
+	}
+	if column.DefaultExpr != "" {
+		b.WriteString(" DEFAULT ")
+		b.WriteString(mysqlDefault(typ, column.DefaultExpr))
+	}
+	if isRowidAlias(table, column) {
+		b.WriteString(" AUTO_INCREMENT")
+	}
+	// Foreign keys, with their names, become table constraints, and CHECK
+	// constraints keep theirs.
+	constraints := column.ConstraintList()
+	for _, constraint := range constraints {
+		if constraint.Name != "" && constraint.Type != parser.COLUMNCONSTRAINT_FOREIGNKEY && constraint.Type != parser.COLUMNCONSTRAINT_CHECK {
+			t.warn(table, column.Name, "CONSTRAINT name", "MySQL does not name column constraints; %s is dropped", constraint.Name)
+		}
+	}
+	if column.IsPrimaryKey {
+		b.WriteString(" PRIMARY KEY")
+		t.conflict(table, column.Name, column.PkConflictClause)
+	}
+	if column.IsUnique {
+		b.WriteString(" UNIQUE")
+		t.conflict(table, column.Name, column.UniqueConflictClause)
+	}
+	for _, constraint := range constraints {
+		if constraint.Type != parser.COLUMNCONSTRAINT_CHECK {
+			continue
+		}
+		if constraint.Name != "" {
+			b.WriteString(" CONSTRAINT ")
+			b.WriteString(myQuote(constraint.Name))
+		}
+		b.WriteString(" CHECK (")
+		b.WriteString(constraint.Expr)
+		b.WriteString(")")
+	}
+	return b.String()
+}
+
+// mysqlType maps the declared type of column to a MySQL type. TEXT and BLOB
+// columns take the LONG variants, which hold as much as SQLite allows, or a
+// VARCHAR or VARBINARY when they are part of a key.
+func (t *translator) mysqlType(table *parser.Table, column *parser.Column, key bool) string {
+	args := typeLength(column)
+	withArgs := func(name string) string {
+		if args == "" {
+			return name
+		}
+		return name + "(" + args + ")"
+	}
+	text := func() string {
+		if key {
+			t.warn(table, column.Name, "TEXT key", "MySQL cannot index TEXT without a length; using VARCHAR(%d)", keyTextLength)
+			return fmt.Sprintf("VARCHAR(%d)", keyTextLength)
+		}
+		return "LONGTEXT"
+	}
+	blob := func() string {
+		if key {
+			t.warn(table, column.Name, "BLOB key", "MySQL cannot index BLOB without a length; using VARBINARY(%d)", keyTextLength)
+			return fmt.Sprintf("VARBINARY(%d)", keyTextLength)
+		}
+		return "LONGBLOB"
+	}
+
+	switch strings.ToUpper(column.Type) {
+	case "INT", "INTEGER", "BIGINT", "INT8":
+		return "BIGINT"
+	case "UNSIGNED BIG INT":
+		return "BIGINT UNSIGNED"
+	case "MEDIUMINT", "INT4":
+		return "MEDIUMINT"
+	case "SMALLINT", "INT2":
+		return "SMALLINT"
+	case "TINYINT":
+		return "TINYINT"
+	case "VARCHAR", "CHARACTER VARYING", "VARYING CHARACTER", "NVARCHAR", "NATIVE CHARACTER":
+		if args == "" {
+			return text()
+		}
+		return withArgs("VARCHAR")
+	case "CHAR", "CHARACTER", "NCHAR":
+		if args == "" {
+			return text()
+		}
+		return withArgs("CHAR")
+	case "TEXT", "CLOB":
+		return text()
+	case "BLOB":
+		return blob()
+	case "REAL", "DOUBLE", "DOUBLE PRECISION", "FLOAT":
+		return "DOUBLE"
+	case "NUMERIC", "DECIMAL":
+		if args == "" {
+			t.warn(table, column.Name, "NUMERIC precision", "MySQL defaults DECIMAL to no fractional digits; using DECIMAL(65,30)")
+			return "DECIMAL(65,30)"
+		}
+		return withArgs("DECIMAL")
+	case "BOOLEAN", "BOOL":
+		return "BOOLEAN"
+	case "DATE":
+		return "DATE"
+	case "DATETIME", "TIMESTAMP":
+		return "DATETIME"
+	case "TIME":
+		return "TIME"
+	case "JSON":
+		return "JSON"
+	case "UUID":
+		return "CHAR(36)"
+	case "":
+		t.warn(table, column.Name, "untyped column", "the column has no type, so SQLite accepts any value; using a text type")
+		return text()
+	}
+	var typ string
+	switch column.Affinity() {
+	case parser.AFFINITY_INTEGER:
+		typ = "BIGINT"
+	case parser.AFFINITY_TEXT:
+		typ = text()
+	case parser.AFFINITY_BLOB:
+		typ = blob()
+	case parser.AFFINITY_REAL:
+		typ = "DOUBLE"
+	default:
+		typ = "DECIMAL(65,30)"
+	}
+	t.warn(table, column.Name, "type", "unknown type %s mapped to %s by its %s affinity", column.Type, typ, column.Affinity())
+	return typ
+}
+
+// mysqlDefault writes a DEFAULT value. MySQL only accepts defaults for TEXT
+// and BLOB columns as expressions, in parentheses.
+func mysqlDefault(typ, value string) string {
+	if strings.HasPrefix(typ, "LONG") {
+		return "(" + literal(value) + ")"
+	}
+	return literal(value)
+}
+
+// mysqlCollation returns the MySQL collation for a SQLite collation, or ""
+// to use the table collation.
+func (t *translator) mysqlCollation(table *parser.Table, column, collation string, options *MySQLOptions) string {
+	switch strings.ToUpper(collation) {
+	case "", "BINARY":
+		return ""
+	case "NOCASE":
+		t.warn(table, column, "COLLATE NOCASE", "%s_general_ci also ignores the case of non-ASCII letters and trailing spaces", options.Charset)
+		return options.Charset + "_general_ci"
+	case "RTRIM":
+		// PAD SPACE collations such as utf8mb4_bin ignore trailing spaces.
+		return options.Charset + "_bin"
+	}
+	t.warn(table, column, "COLLATE", "collation %s is kept as is and must exist in MySQL", collation)
+	return collation
+}
+
+func (t *translator) mysqlConstraint(table *parser.Table, constraint *parser.TableConstraint) string {
+	var b strings.Builder
+	if constraint.Name != "" {
+		b.WriteString("CONSTRAINT ")
+		b.WriteString(myQuote(constraint.Name))
+		b.WriteString(" ")
+	}
+	b.WriteString(constraint.Type.SQL())
+	switch constraint.Type {
+	case parser.TABLECONSTRAINT_PRIMARYKEY, parser.TABLECONSTRAINT_UNIQUE:
+		b.WriteString(" (")
+		b.WriteString(t.mysqlKeyColumns(table, constraint.IndexedColumns))
+		b.WriteString(")")
+		t.conflict(table, "", constraint.ConflictClause)
+	case parser.TABLECONSTRAINT_CHECK:
+		b.WriteString(" (")
+		b.WriteString(constraint.CheckExpr)
+		b.WriteString(")")
+	case parser.TABLECONSTRAINT_FOREIGNKEY:
+		b.WriteString(" (")
+		b.WriteString(myQuoteAll(constraint.ForeignKeyName))
+		b.WriteString(")")
+		if constraint.ForeignKeyClause != nil {
+			b.WriteString(" ")
+			b.WriteString(t.mysqlForeignKey(table, "", constraint.ForeignKeyClause))
+		}
+	}
+	return b.String()
+}
+
+// mysqlKeyColumns writes the columns of a key or index. Expressions are
+// written as the functional key parts of MySQL 8.0.13.
+func (t *translator) mysqlKeyColumns(table *parser.Table, columns []parser.IdxColumn) string {
+	list := make([]string, len(columns))
+	for i, column := range columns {
+		if column.Expr != nil {
+			list[i] = "(" + column.Name + ")"
+		} else {
+			list[i] = myQuote(column.Name)
+		}
+		if column.CollateName != "" {
+			t.warn(table, column.Name, "key column COLLATE", "MySQL key columns use the column collation; COLLATE %s is dropped", column.CollateName)
+		}
+		if column.Order != parser.ORDER_NONE {
+			list[i] += " " + column.Order.SQL()
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
+func (t *translator) mysqlForeignKey(table *parser.Table, column string, fk *parser.ForeignKey) string {
+	var b strings.Builder
+	b.WriteString("REFERENCES ")
+	b.WriteString(myQuote(fk.Table))
+	// MySQL requires the referenced columns, which SQLite defaults to the
+	// primary key of the parent table.
+	columns := fk.ColumnName
+	if len(columns) == 0 {
+		if parent := t.table(fk.Table); parent == nil {
+			t.warn(table, column, "implicit REFERENCES columns", "MySQL requires the referenced columns; add the primary key of %s", fk.Table)
+		} else if columns = parent.PrimaryKey(); columns == nil {
+			t.warn(table, column, "implicit REFERENCES columns", "MySQL requires the referenced columns, and %s has no primary key", fk.Table)
+		}
+	}
+	if len(columns) > 0 {
+		b.WriteString(" (")
+		b.WriteString(myQuoteAll(columns))
+		b.WriteString(")")
+	}
+	for _, action := range []struct {
+		clause string
+		action parser.FkAction
+	}{{"ON DELETE", fk.OnDelete}, {"ON UPDATE", fk.OnUpdate}} {
+		switch action.action {
+		case parser.FKACTION_NONE:
+		case parser.FKACTION_SETDEFAULT:
+			t.warn(table, column, "SET DEFAULT", "InnoDB rejects %s SET DEFAULT; it is dropped", action.clause)
+		default:
+			b.WriteString(" ")
+			b.WriteString(action.clause)
+			b.WriteString(" ")
+			b.WriteString(action.action.SQL())
+		}
+	}
+	if fk.Deferrable == parser.DEFTYPE_DEFERRABLE_INITIALLY_DEFERRED {
+		t.warn(table, column, "DEFERRABLE", "MySQL checks foreign keys immediately; DEFERRABLE INITIALLY DEFERRED is dropped")
+	}
+	return b.String()
+}
+
+// myQuote quotes an identifier with backticks, which is always allowed and
+// avoids tracking the reserved words of every MySQL and MariaDB version.
+func myQuote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func myQuoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = myQuote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
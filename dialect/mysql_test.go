@@ -0,0 +1,123 @@
+package dialect
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToMySQL(t *testing.T) {
+	sql, warnings := ToMySQL(MySQLOptions{}, parseTables(t, sqliteSchema)...)
+	assert.Equal(t, "CREATE TABLE `users` (\n"+
+		"  `id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,\n"+
+		"  `email` VARCHAR(255) COLLATE utf8mb4_general_ci NOT NULL UNIQUE,\n"+
+		"  `name` VARCHAR(100),\n"+
+		"  `active` BOOLEAN DEFAULT 'yes',\n"+
+		"  `score` DECIMAL(65,30),\n"+
+		"  `data` LONGTEXT\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;\n"+
+		"\n"+
+		"CREATE TABLE `order` (\n"+
+		"  `user_id` BIGINT,\n"+
+		"  `code` VARCHAR(255),\n"+
+		"  `at` DATETIME DEFAULT CURRENT_TIMESTAMP,\n"+
+		"  FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,\n"+
+		"  PRIMARY KEY (`user_id`, `code` DESC),\n"+
+		"  UNIQUE (`code`, `at`),\n"+
+		"  FOREIGN KEY (`code`) REFERENCES `codes` (`code`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;\n", sql)
+	assert.Equal(t, []string{
+		"TEXT key", "COLLATE NOCASE", "ON CONFLICT", "type", "untyped column",
+		"TEXT key", "key column COLLATE", "ON CONFLICT", "DEFERRABLE",
+	}, features(warnings))
+	assert.Equal(t, "order: ON CONFLICT REPLACE is dropped; use INSERT IGNORE, REPLACE or ON DUPLICATE KEY UPDATE in MySQL", warnings[7].String())
+
+	data, err := json.Marshal(warnings[8])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"table": "order", "feature": "DEFERRABLE", "message": "MySQL checks foreign keys immediately; DEFERRABLE INITIALLY DEFERRED is dropped"}`, string(data))
+}
+
+func TestMySQLOptions(t *testing.T) {
+	tables := parseTables(t, `CREATE TABLE notes (
+ id TEXT CONSTRAINT pk PRIMARY KEY,
+ body TEXT COLLATE RTRIM,
+ raw BLOB,
+ parent TEXT REFERENCES notes ON DELETE SET DEFAULT
+);
+CREATE TABLE log (line TEXT);`)
+	tables[0].Columns[1].DefaultExpr = "empty"
+	sql, warnings := ToMySQL(MySQLOptions{Engine: "Aria", Charset: "latin1"}, tables...)
+	assert.Equal(t, "CREATE TABLE `notes` (\n"+
+		"  `id` VARCHAR(255) PRIMARY KEY,\n"+
+		"  `body` LONGTEXT COLLATE latin1_bin DEFAULT ('empty'),\n"+
+		"  `raw` LONGBLOB,\n"+
+		"  `parent` VARCHAR(255),\n"+
+		"  FOREIGN KEY (`parent`) REFERENCES `notes` (`id`)\n"+
+		") ENGINE=Aria DEFAULT CHARSET=latin1 COLLATE=latin1_bin;\n"+
+		"\n"+
+		"CREATE TABLE `log` (\n"+
+		"  `line` LONGTEXT\n"+
+		") ENGINE=Aria DEFAULT CHARSET=latin1 COLLATE=latin1_bin;\n", sql)
+	assert.Equal(t, []string{
+		"TEXT key", "nullable PRIMARY KEY", "CONSTRAINT name", "TEXT key",
+		"SET DEFAULT", "rowid",
+	}, features(warnings))
+}
+
+func TestMySQLImplicitReferences(t *testing.T) {
+	tables := parseTables(t, `CREATE TABLE orders (id INTEGER, region TEXT, PRIMARY KEY (region, id));
+CREATE TABLE items (
+ order_id INTEGER,
+ region TEXT,
+ user_id INTEGER REFERENCES users,
+ log_id INTEGER REFERENCES log,
+ FOREIGN KEY (region, order_id) REFERENCES Orders
+);
+CREATE TABLE log (line TEXT);`)
+	sql, warnings := ToMySQL(MySQLOptions{}, tables...)
+	assert.Contains(t, sql, "  FOREIGN KEY (`user_id`) REFERENCES `users`,\n"+
+		"  FOREIGN KEY (`log_id`) REFERENCES `log`,\n"+
+		"  FOREIGN KEY (`region`, `order_id`) REFERENCES `Orders` (`region`, `id`)\n")
+	var messages []string
+	for _, warning := range warnings {
+		if warning.Feature == "implicit REFERENCES columns" {
+			messages = append(messages, warning.String())
+		}
+	}
+	assert.Equal(t, []string{
+		"items.user_id: MySQL requires the referenced columns; add the primary key of users",
+		"items.log_id: MySQL requires the referenced columns, and log has no primary key",
+	}, messages)
+}
+
+func TestToMySQLSchema(t *testing.T) {
+	sql, warnings := ToMySQLSchema(MySQLOptions{}, parseSchema(t, sqliteObjects))
+	assert.Equal(t, "CREATE TABLE `users` (\n"+
+		"  `id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,\n"+
+		"  `email` VARCHAR(255),\n"+
+		"  `name` LONGTEXT\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;\n"+
+		"\n"+
+		"CREATE UNIQUE INDEX `users_email` ON `users` (`email`);\n"+
+		"CREATE INDEX `users_name` ON `users` ((lower(name)), `id` DESC);\n", sql)
+	assert.Equal(t, []string{"TEXT key", "key column COLLATE", "partial index", "IF NOT EXISTS", "CREATE VIEW", "CREATE TRIGGER"}, features(warnings))
+}
+
+func TestMySQLColumnConstraints(t *testing.T) {
+	sql, warnings := ToMySQL(MySQLOptions{}, parseTables(t, `CREATE TABLE t (
+ a INTEGER PRIMARY KEY,
+ b INT CONSTRAINT b_fk REFERENCES p (x) ON DELETE CASCADE CONSTRAINT b_q REFERENCES q (y) CONSTRAINT nn NOT NULL,
+ c INT CONSTRAINT positive CHECK (c > 0) CHECK (c < 10)
+);`)...)
+	assert.Equal(t, "CREATE TABLE `t` (\n"+
+		"  `a` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,\n"+
+		"  `b` BIGINT NOT NULL,\n"+
+		"  `c` BIGINT CONSTRAINT `positive` CHECK (c > 0) CHECK (c < 10),\n"+
+		"  CONSTRAINT `b_fk` FOREIGN KEY (`b`) REFERENCES `p` (`x`) ON DELETE CASCADE,\n"+
+		"  CONSTRAINT `b_q` FOREIGN KEY (`b`) REFERENCES `q` (`y`)\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;\n", sql)
+	if assert.Len(t, warnings, 1) {
+		assert.Equal(t, "t.b: MySQL does not name column constraints; nn is dropped", warnings[0].String())
+	}
+}
@@ -0,0 +1,469 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// ToPostgres returns PostgreSQL CREATE TABLE statements for tables, each
+// followed by the CREATE INDEX statements replacing the UNIQUE constraints
+// PostgreSQL cannot express inline, and the warnings for every construct
+// that was dropped or changed in meaning.
+func ToPostgres(tables ...*parser.Table) (string, []Warning) {
+	return ToPostgresSchema(&parser.Schema{Tables: tables})
+}
+
+// ToPostgresSchema is ToPostgres for a whole schema: the CREATE INDEX
+// statements of schema follow the tables, and its views and triggers, which
+// are not translated, are reported.
+func ToPostgresSchema(schema *parser.Schema) (string, []Warning) {
+	t := translator{conflictHint: "use INSERT ... ON CONFLICT in PostgreSQL"}
+	var b strings.Builder
+	for i, table := range schema.Tables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		t.postgresTable(&b, table)
+	}
+	for i, index := range schema.Indexes {
+		if i == 0 && b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		t.postgresIndex(&b, index)
+	}
+	t.skipUntranslated(schema)
+	return b.String(), t.warnings
+}
+
+func (t *translator) postgresTable(b *strings.Builder, table *parser.Table) {
+	name := qualifiedName(table.Schema, table.Name, pgQuote)
+
+	var lines, indexes []string
+	for i := range table.Columns {
+		line, indexName, index := t.postgresColumn(table, &table.Columns[i])
+		lines = append(lines, line)
+		if index != "" {
+			if indexName == "" {
+				indexName = table.Name + "_" + table.Columns[i].Name + "_key"
+			}
+			indexes = append(indexes, fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s);", pgQuote(indexName), name, index))
+		}
+	}
+	for i := range table.Constraints {
+		constraint := &table.Constraints[i]
+		if constraint.Type == parser.TABLECONSTRAINT_UNIQUE && needsIndex(constraint) {
+			indexName := constraint.Name
+			if indexName == "" {
+				names := []string{table.Name}
+				for _, column := range constraint.IndexedColumns {
+					names = append(names, column.Name)
+				}
+				indexName = strings.Join(append(names, "key"), "_")
+			}
+			t.conflict(table, "", constraint.ConflictClause)
+			indexes = append(indexes, fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s);",
+				pgQuote(indexName), name, t.postgresIndexedColumns(table, constraint.IndexedColumns)))
+			continue
+		}
+		lines = append(lines, t.postgresConstraint(table, constraint))
+	}
+
+	b.WriteString("CREATE ")
+	if table.IsTemporary {
+		b.WriteString("TEMPORARY ")
+	}
+	b.WriteString("TABLE ")
+	if table.IsIfNotExists {
+		b.WriteString("IF NOT EXISTS ")
+	}
+	b.WriteString(name)
+	b.WriteString(" (\n")
+	b.WriteString(join(lines))
+	b.WriteString(");\n")
+	for _, index := range indexes {
+		b.WriteString(index)
+		b.WriteString("\n")
+	}
+}
+
+func (t *translator) postgresIndex(b *strings.Builder, index *parser.Index) {
+	b.WriteString("CREATE ")
+	if index.IsUnique {
+		b.WriteString("UNIQUE ")
+	}
+	b.WriteString("INDEX ")
+	if index.IsIfNotExists {
+		b.WriteString("IF NOT EXISTS ")
+	}
+	// PostgreSQL creates an index in the schema of its table.
+	fmt.Fprintf(b, "%s ON %s (%s)", pgQuote(index.Name), qualifiedName(index.Schema, index.Table, pgQuote),
+		t.postgresIndexedColumns(&parser.Table{Name: index.Table}, index.Columns))
+	if index.Where != "" {
+		b.WriteString(" WHERE ")
+		b.WriteString(index.Where)
+	}
+	b.WriteString(";\n")
+}
+
+// postgresColumn returns the column definition and, when a UNIQUE constraint
+// has to become an index, its name and indexed expression. The constraints
+// are written in order, each with its own name.
+func (t *translator) postgresColumn(table *parser.Table, column *parser.Column) (string, string, string) {
+	var b strings.Builder
+	b.WriteString(pgQuote(column.Name))
+	b.WriteString(" ")
+	typ := t.postgresType(table, column)
+	b.WriteString(typ)
+	if isRowidAlias(table, column) {
+		b.WriteString(" GENERATED BY DEFAULT AS IDENTITY")
+	}
+
+	// The collation is looked up once, when a UNIQUE or COLLATE constraint
+	// first needs it.
+	var collation, expr string
+	collationDone := false
+	collate := func() {
+		if !collationDone {
+			collation, expr = t.postgresCollation(table, column.Name, column.CollateName, column.IsUnique)
+			collationDone = true
+		}
+	}
+
+	constraints := column.ConstraintList()
+	// PostgreSQL rejects a repeated DEFAULT or COLLATE; SQLite uses the
+	// last one, which is the one written.
+	last := map[parser.ColumnConstraintType]int{}
+	for i, constraint := range constraints {
+		last[constraint.Type] = i
+	}
+	var indexName, index string
+	for i := range constraints {
+		constraint := &constraints[i]
+		writeName := func() {
+			if constraint.Name != "" {
+				b.WriteString(" CONSTRAINT ")
+				b.WriteString(pgQuote(constraint.Name))
+			}
+		}
+		dropName := func(reason string) {
+			if constraint.Name != "" {
+				t.warn(table, column.Name, "CONSTRAINT name", "%s; the name %s is dropped", reason, constraint.Name)
+			}
+		}
+		switch constraint.Type {
+		case parser.COLUMNCONSTRAINT_PRIMARYKEY:
+			writeName()
+			b.WriteString(" PRIMARY KEY")
+			if constraint.Order == parser.ORDER_DESC {
+				t.warn(table, column.Name, "PRIMARY KEY DESC", "the primary key order is dropped")
+			}
+			t.conflict(table, column.Name, constraint.ConflictClause)
+		case parser.COLUMNCONSTRAINT_NOTNULL:
+			writeName()
+			b.WriteString(" NOT NULL")
+			t.conflict(table, column.Name, constraint.ConflictClause)
+		case parser.COLUMNCONSTRAINT_NULL:
+			dropName("NULL is the default and is not written")
+		case parser.COLUMNCONSTRAINT_UNIQUE:
+			collate()
+			t.conflict(table, column.Name, constraint.ConflictClause)
+			if expr == "" {
+				writeName()
+				b.WriteString(" UNIQUE")
+			} else if index == "" {
+				indexName, index = constraint.Name, expr
+			}
+		case parser.COLUMNCONSTRAINT_CHECK:
+			writeName()
+			b.WriteString(" CHECK (")
+			b.WriteString(constraint.Expr)
+			b.WriteString(")")
+		case parser.COLUMNCONSTRAINT_DEFAULT:
+			if i != last[constraint.Type] {
+				t.warn(table, column.Name, "DEFAULT", "SQLite uses the last DEFAULT; DEFAULT %s is dropped", constraint.Expr)
+				continue
+			}
+			writeName()
+			b.WriteString(" DEFAULT ")
+			b.WriteString(postgresDefault(typ, constraint.Expr))
+		case parser.COLUMNCONSTRAINT_COLLATE:
+			dropName("PostgreSQL does not name COLLATE clauses")
+			if i != last[constraint.Type] {
+				continue
+			}
+			collate()
+			if collation != "" {
+				b.WriteString(" COLLATE ")
+				b.WriteString(collation)
+			}
+		case parser.COLUMNCONSTRAINT_FOREIGNKEY:
+			if constraint.ForeignKeyClause != nil {
+				writeName()
+				b.WriteString(" ")
+				b.WriteString(t.postgresForeignKey(table, column.Name, constraint.ForeignKeyClause))
+			}
+		}
+	}
+	return b.String(), indexName, index
+}
+
+// postgresType maps the declared type of column to a PostgreSQL type: known
+// type names to their closest equivalent, anything else by affinity.
+func (t *translator) postgresType(table *parser.Table, column *parser.Column) string {
+	args := typeLength(column)
+	withArgs := func(name string) string {
+		if args == "" {
+			return name
+		}
+		return name + "(" + args + ")"
+	}
+	switch strings.ToUpper(column.Type) {
+	case "INT", "INTEGER", "BIGINT", "INT8", "UNSIGNED BIG INT":
+		return "bigint"
+	case "MEDIUMINT", "INT4":
+		return "integer"
+	case "SMALLINT", "TINYINT", "INT2":
+		return "smallint"
+	case "VARCHAR", "CHARACTER VARYING", "VARYING CHARACTER", "NVARCHAR", "NATIVE CHARACTER":
+		return withArgs("varchar")
+	case "CHAR", "CHARACTER", "NCHAR":
+		if args == "" {
+			return "text"
+		}
+		return withArgs("char")
+	case "TEXT", "CLOB":
+		return "text"
+	case "BLOB":
+		return "bytea"
+	case "REAL", "DOUBLE", "DOUBLE PRECISION", "FLOAT":
+		return "double precision"
+	case "NUMERIC", "DECIMAL":
+		return withArgs("numeric")
+	case "BOOLEAN", "BOOL":
+		return "boolean"
+	case "DATE":
+		return "date"
+	case "DATETIME", "TIMESTAMP":
+		return "timestamp"
+	case "TIME":
+		return "time"
+	case "JSON":
+		return "jsonb"
+	case "UUID":
+		return "uuid"
+	case "":
+		t.warn(table, column.Name, "untyped column", "the column has no type, so SQLite accepts any value; using text")
+		return "text"
+	}
+	typ := map[parser.Affinity]string{
+		parser.AFFINITY_INTEGER: "bigint",
+		parser.AFFINITY_TEXT:    "text",
+		parser.AFFINITY_BLOB:    "bytea",
+		parser.AFFINITY_REAL:    "double precision",
+		parser.AFFINITY_NUMERIC: "numeric",
+	}[column.Affinity()]
+	t.warn(table, column.Name, "type", "unknown type %s mapped to %s by its %s affinity", column.Type, typ, column.Affinity())
+	return typ
+}
+
+// postgresDefault adapts SQLite's integer booleans to PostgreSQL.
+func postgresDefault(typ, value string) string {
+	if typ == "boolean" {
+		switch value {
+		case "0":
+			return "FALSE"
+		case "1":
+			return "TRUE"
+		}
+	}
+	return literal(value)
+}
+
+// postgresCollation returns the COLLATE name for a column, or, for the
+// SQLite collations without a PostgreSQL equivalent, the expression an index
+// must use to keep uniqueness.
+func (t *translator) postgresCollation(table *parser.Table, column, collation string, unique bool) (string, string) {
+	var expr, message string
+	switch strings.ToUpper(collation) {
+	case "":
+		return "", ""
+	case "BINARY":
+		return `"C"`, ""
+	case "NOCASE":
+		expr = "lower(" + pgQuote(column) + ")"
+		message = "PostgreSQL has no case-insensitive collation by default, so comparisons become case-sensitive"
+	case "RTRIM":
+		expr = "rtrim(" + pgQuote(column) + ")"
+		message = "PostgreSQL has no collation ignoring trailing spaces, so comparisons take them into account"
+	}
+	if expr != "" {
+		if unique {
+			message += "; uniqueness is enforced with an index on " + expr
+		}
+		t.warn(table, column, "COLLATE "+strings.ToUpper(collation), "%s", message)
+		return "", expr
+	}
+	t.warn(table, column, "COLLATE", "collation %s must be created in PostgreSQL", collation)
+	return pgQuote(collation), ""
+}
+
+func needsIndex(constraint *parser.TableConstraint) bool {
+	for _, column := range constraint.IndexedColumns {
+		if column.CollateName != "" || column.Order != parser.ORDER_NONE {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *translator) postgresIndexedColumns(table *parser.Table, columns []parser.IdxColumn) string {
+	list := make([]string, len(columns))
+	for i, column := range columns {
+		if column.Expr != nil {
+			// PostgreSQL needs parentheses around index expressions.
+			list[i] = "(" + column.Name + ")"
+			if column.CollateName != "" {
+				t.warn(table, "", "COLLATE", "the collation %s of index expression %s is dropped", column.CollateName, column.Name)
+			}
+			if column.Order != parser.ORDER_NONE {
+				list[i] += " " + column.Order.SQL()
+			}
+			continue
+		}
+		collation, expr := t.postgresCollation(table, column.Name, column.CollateName, true)
+		switch {
+		case expr != "":
+			list[i] = expr
+		case collation != "":
+			list[i] = pgQuote(column.Name) + " COLLATE " + collation
+		default:
+			list[i] = pgQuote(column.Name)
+		}
+		if column.Order != parser.ORDER_NONE {
+			list[i] += " " + column.Order.SQL()
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
+func (t *translator) postgresConstraint(table *parser.Table, constraint *parser.TableConstraint) string {
+	var b strings.Builder
+	if constraint.Name != "" {
+		b.WriteString("CONSTRAINT ")
+		b.WriteString(pgQuote(constraint.Name))
+		b.WriteString(" ")
+	}
+	b.WriteString(constraint.Type.SQL())
+	switch constraint.Type {
+	case parser.TABLECONSTRAINT_PRIMARYKEY, parser.TABLECONSTRAINT_UNIQUE:
+		names := make([]string, len(constraint.IndexedColumns))
+		for i, column := range constraint.IndexedColumns {
+			names[i] = column.Name
+			if column.CollateName != "" || column.Order != parser.ORDER_NONE {
+				t.warn(table, column.Name, "PRIMARY KEY column options", "the collation and order of primary key columns are dropped")
+			}
+		}
+		b.WriteString(" (")
+		b.WriteString(pgQuoteAll(names))
+		b.WriteString(")")
+		t.conflict(table, "", constraint.ConflictClause)
+	case parser.TABLECONSTRAINT_CHECK:
+		b.WriteString(" (")
+		b.WriteString(constraint.CheckExpr)
+		b.WriteString(")")
+	case parser.TABLECONSTRAINT_FOREIGNKEY:
+		b.WriteString(" (")
+		b.WriteString(pgQuoteAll(constraint.ForeignKeyName))
+		b.WriteString(")")
+		if constraint.ForeignKeyClause != nil {
+			b.WriteString(" ")
+			b.WriteString(t.postgresForeignKey(table, "", constraint.ForeignKeyClause))
+		}
+	}
+	return b.String()
+}
+
+func (t *translator) postgresForeignKey(table *parser.Table, column string, fk *parser.ForeignKey) string {
+	var b strings.Builder
+	b.WriteString("REFERENCES ")
+	b.WriteString(pgQuote(fk.Table))
+	if len(fk.ColumnName) > 0 {
+		b.WriteString(" (")
+		b.WriteString(pgQuoteAll(fk.ColumnName))
+		b.WriteString(")")
+	}
+	switch strings.ToUpper(fk.Match) {
+	case "":
+	case "SIMPLE", "FULL":
+		b.WriteString(" MATCH ")
+		b.WriteString(strings.ToUpper(fk.Match))
+	default:
+		t.warn(table, column, "MATCH", "MATCH %s is not supported by PostgreSQL and is dropped", fk.Match)
+	}
+	if fk.OnDelete != parser.FKACTION_NONE {
+		b.WriteString(" ON DELETE ")
+		b.WriteString(fk.OnDelete.SQL())
+	}
+	if fk.OnUpdate != parser.FKACTION_NONE {
+		b.WriteString(" ON UPDATE ")
+		b.WriteString(fk.OnUpdate.SQL())
+	}
+	switch fk.Deferrable {
+	case parser.DEFTYPE_NONE:
+	case parser.DEFTYPE_NOTDEFERRABLE_INITIALLY_DEFERRED:
+		// PostgreSQL rejects INITIALLY DEFERRED on a constraint that is not
+		// deferrable; SQLite treats it as NOT DEFERRABLE.
+		t.warn(table, column, "NOT DEFERRABLE INITIALLY DEFERRED", "written as NOT DEFERRABLE, which is how SQLite enforces it")
+		b.WriteString(" NOT DEFERRABLE")
+	default:
+		b.WriteString(" ")
+		b.WriteString(fk.Deferrable.SQL())
+	}
+	return b.String()
+}
+
+// pgReserved lists the PostgreSQL keywords that cannot be used as column
+// names without quotes.
+var pgReserved = map[string]bool{}
+
+func init() {
+	for _, word := range strings.Fields(`ALL ANALYSE ANALYZE AND ANY ARRAY AS ASC ASYMMETRIC
+		AUTHORIZATION BINARY BOTH CASE CAST CHECK COLLATE COLLATION COLUMN CONCURRENTLY
+		CONSTRAINT CREATE CROSS CURRENT_CATALOG CURRENT_DATE CURRENT_ROLE CURRENT_SCHEMA
+		CURRENT_TIME CURRENT_TIMESTAMP CURRENT_USER DEFAULT DEFERRABLE DESC DISTINCT DO ELSE
+		END EXCEPT FALSE FETCH FOR FOREIGN FREEZE FROM FULL GRANT GROUP HAVING ILIKE IN
+		INITIALLY INNER INTERSECT INTO IS ISNULL JOIN LATERAL LEADING LEFT LIKE LIMIT
+		LOCALTIME LOCALTIMESTAMP NATURAL NOT NOTNULL NULL OFFSET ON ONLY OR ORDER OUTER
+		OVERLAPS PLACING PRIMARY REFERENCES RETURNING RIGHT SELECT SESSION_USER SIMILAR SOME
+		SYMMETRIC SYSTEM_USER TABLE TABLESAMPLE THEN TO TRAILING TRUE UNION UNIQUE USER USING
+		VARIADIC VERBOSE WHEN WHERE WINDOW WITH`) {
+		pgReserved[word] = true
+	}
+}
+
+// pgQuote quotes name unless it is a plain identifier. Plain identifiers are
+// left unquoted even in mixed case: PostgreSQL folds them to lower case, so
+// queries keep matching them case-insensitively as in SQLite.
+func pgQuote(name string) string {
+	plain := name != "" && !pgReserved[strings.ToUpper(name)]
+	for i, r := range name {
+		letter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if !letter && (i == 0 || r < '0' || r > '9') {
+			plain = false
+		}
+	}
+	if plain {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func pgQuoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = pgQuote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
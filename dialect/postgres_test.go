@@ -0,0 +1,144 @@
+package dialect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+func parseSchema(t *testing.T, sql string) *parser.Schema {
+	schema, errCode := parser.ParseSchema(sql)
+	assert.Equal(t, parser.ERROR_NONE, errCode)
+	return schema
+}
+
+func parseTables(t *testing.T, sql string) []*parser.Table {
+	return parseSchema(t, sql).Tables
+}
+
+func features(warnings []Warning) []string {
+	list := make([]string, len(warnings))
+	for i, warning := range warnings {
+		list[i] = warning.Feature
+	}
+	return list
+}
+
+const sqliteSchema = `
+CREATE TABLE users (
+ id INTEGER PRIMARY KEY AUTOINCREMENT,
+ email TEXT NOT NULL ON CONFLICT ABORT UNIQUE COLLATE NOCASE,
+ name VARCHAR(100) COLLATE BINARY,
+ active BOOLEAN DEFAULT 'yes',
+ score MONEY,
+ data
+);
+CREATE TABLE "order" (
+ user_id INTEGER REFERENCES users (id) ON DELETE CASCADE NOT DEFERRABLE INITIALLY DEFERRED,
+ code TEXT,
+ at DATETIME DEFAULT CURRENT_TIMESTAMP,
+ PRIMARY KEY (user_id, code DESC),
+ UNIQUE (code COLLATE NOCASE, at) ON CONFLICT REPLACE,
+ FOREIGN KEY (code) REFERENCES codes (code) MATCH PARTIAL DEFERRABLE INITIALLY DEFERRED
+) WITHOUT ROWID;
+`
+
+func TestToPostgres(t *testing.T) {
+	sql, warnings := ToPostgres(parseTables(t, sqliteSchema)...)
+	assert.Equal(t, `CREATE TABLE users (
+  id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  email text NOT NULL,
+  name varchar(100) COLLATE "C",
+  active boolean DEFAULT 'yes',
+  score numeric,
+  data text
+);
+CREATE UNIQUE INDEX users_email_key ON users (lower(email));
+
+CREATE TABLE "order" (
+  user_id bigint REFERENCES users (id) ON DELETE CASCADE NOT DEFERRABLE,
+  code text,
+  at timestamp DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id, code),
+  FOREIGN KEY (code) REFERENCES codes (code) DEFERRABLE INITIALLY DEFERRED
+);
+CREATE UNIQUE INDEX order_code_at_key ON "order" (lower(code), at);
+`, sql)
+	assert.Equal(t, []string{
+		"ON CONFLICT", "COLLATE NOCASE", "type", "untyped column",
+		"NOT DEFERRABLE INITIALLY DEFERRED", "PRIMARY KEY column options",
+		"ON CONFLICT", "COLLATE NOCASE", "MATCH",
+	}, features(warnings))
+	assert.Equal(t, "users.score: unknown type MONEY mapped to numeric by its NUMERIC affinity", warnings[2].String())
+}
+
+func TestPostgresTypes(t *testing.T) {
+	tables := parseTables(t, `CREATE TEMP TABLE IF NOT EXISTS t (
+ a INT, b SMALLINT, c NCHAR(2), d DOUBLE, e DECIMAL(10, 2), f BLOB, g DATE, h JSON,
+ "Mixed Case" TEXT, "select" TEXT
+);`)
+	tables[0].Columns[3].DefaultExpr = "0"
+	tables[0].Columns[0].DefaultExpr = "-1.5"
+	sql, warnings := ToPostgres(tables...)
+	assert.Empty(t, warnings)
+	assert.Equal(t, `CREATE TEMPORARY TABLE IF NOT EXISTS t (
+  a bigint DEFAULT -1.5,
+  b smallint,
+  c char(2),
+  d double precision DEFAULT 0,
+  e numeric(10,2),
+  f bytea,
+  g date,
+  h jsonb,
+  "Mixed Case" text,
+  "select" text
+);
+`, sql)
+}
+
+func TestPostgresBooleanDefault(t *testing.T) {
+	assert.Equal(t, "FALSE", postgresDefault("boolean", "0"))
+	assert.Equal(t, "'it''s'", postgresDefault("text", "it's"))
+}
+
+func TestPostgresColumnConstraints(t *testing.T) {
+	sql, warnings := ToPostgres(parseTables(t, `CREATE TABLE t (
+ a INTEGER CONSTRAINT pk PRIMARY KEY CONSTRAINT nn NOT NULL,
+ c INT CONSTRAINT c_p REFERENCES p (x) REFERENCES q (y),
+ d TEXT CONSTRAINT d_key UNIQUE COLLATE NOCASE CONSTRAINT d_collation COLLATE RTRIM
+);`)...)
+	assert.Equal(t, `CREATE TABLE t (
+  a bigint GENERATED BY DEFAULT AS IDENTITY CONSTRAINT pk PRIMARY KEY CONSTRAINT nn NOT NULL,
+  c bigint CONSTRAINT c_p REFERENCES p (x) REFERENCES q (y),
+  d text
+);
+CREATE UNIQUE INDEX d_key ON t (rtrim(d));
+`, sql)
+	assert.Equal(t, []string{"COLLATE RTRIM", "CONSTRAINT name"}, features(warnings))
+	assert.Equal(t, "t.d: PostgreSQL does not name COLLATE clauses; the name d_collation is dropped", warnings[1].String())
+}
+
+const sqliteObjects = `
+CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT, name TEXT);
+CREATE UNIQUE INDEX users_email ON users (email COLLATE NOCASE);
+CREATE INDEX IF NOT EXISTS users_name ON users (lower(name), id DESC) WHERE name IS NOT NULL;
+CREATE VIEW named AS SELECT * FROM users WHERE name IS NOT NULL;
+CREATE TRIGGER users_delete AFTER DELETE ON users BEGIN SELECT 1; END;
+`
+
+func TestToPostgresSchema(t *testing.T) {
+	sql, warnings := ToPostgresSchema(parseSchema(t, sqliteObjects))
+	assert.Equal(t, `CREATE TABLE users (
+  id bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+  email text,
+  name text
+);
+
+CREATE UNIQUE INDEX users_email ON users (lower(email));
+CREATE INDEX IF NOT EXISTS users_name ON users ((lower(name)), id DESC) WHERE name IS NOT NULL;
+`, sql)
+	assert.Equal(t, []string{"COLLATE NOCASE", "CREATE VIEW", "CREATE TRIGGER"}, features(warnings))
+	assert.Equal(t, "users: triggers are not translated; trigger users_delete is skipped", warnings[2].String())
+}
@@ -0,0 +1,179 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	// tokenWord is a bare identifier or keyword.
+	tokenWord
+	// tokenQuoted is a quoted identifier; value holds the name.
+	tokenQuoted
+	// tokenString is a string literal; value holds the unescaped text.
+	tokenString
+	tokenNumber
+	// tokenPunct is any other character, or "::".
+	tokenPunct
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	value  string
+	offset int
+}
+
+// is reports whether the token is the given keyword or punctuation.
+func (t token) is(text string) bool {
+	return (t.kind == tokenWord || t.kind == tokenPunct) && strings.EqualFold(t.text, text)
+}
+
+// scanner splits PostgreSQL or MySQL source into tokens, dropping
+// whitespace and comments.
+type scanner struct {
+	src    string
+	mysql  bool
+	offset int
+}
+
+func (s *scanner) errorf(offset int, format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", strings.Count(s.src[:offset], "\n")+1, fmt.Sprintf(format, args...))
+}
+
+func (s *scanner) scan() ([]token, error) {
+	var tokens []token
+	for {
+		t, err := s.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+		if t.kind == tokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (s *scanner) next() (token, error) {
+	src := s.src
+	for s.offset < len(src) {
+		switch {
+		case unicode.IsSpace(rune(src[s.offset])):
+			s.offset++
+		case strings.HasPrefix(src[s.offset:], "--"), s.mysql && src[s.offset] == '#':
+			for s.offset < len(src) && src[s.offset] != '\n' {
+				s.offset++
+			}
+		case strings.HasPrefix(src[s.offset:], "/*"):
+			end := strings.Index(src[s.offset+2:], "*/")
+			if end < 0 {
+				return token{}, s.errorf(s.offset, "unterminated comment")
+			}
+			s.offset += end + 4
+		default:
+			return s.token()
+		}
+	}
+	return token{kind: tokenEOF, offset: s.offset}, nil
+}
+
+func (s *scanner) token() (token, error) {
+	src, start := s.src, s.offset
+	c := src[start]
+	switch {
+	case c == '\'' || (c == '"' && s.mysql):
+		value, err := s.quoted(c, s.mysql)
+		return token{kind: tokenString, text: src[start:s.offset], value: value, offset: start}, err
+	case (c == 'E' || c == 'e') && !s.mysql && strings.HasPrefix(src[start+1:], "'"):
+		s.offset++
+		value, err := s.quoted('\'', true)
+		return token{kind: tokenString, text: src[start:s.offset], value: value, offset: start}, err
+	case c == '"' || (c == '`' && s.mysql):
+		value, err := s.quoted(c, false)
+		return token{kind: tokenQuoted, text: src[start:s.offset], value: value, offset: start}, err
+	case c == '$' && !s.mysql:
+		// Dollar-quoted string: $tag$ ... $tag$.
+		if end := strings.IndexByte(src[start+1:], '$'); end >= 0 && isWord(src[start+1:start+1+end]) {
+			tag := src[start : start+end+2]
+			close := strings.Index(src[start+len(tag):], tag)
+			if close < 0 {
+				return token{}, s.errorf(start, "unterminated dollar-quoted string")
+			}
+			s.offset = start + len(tag) + close + len(tag)
+			return token{kind: tokenString, text: src[start:s.offset], value: src[start+len(tag) : start+len(tag)+close], offset: start}, nil
+		}
+	case c >= '0' && c <= '9' || (c == '.' && start+1 < len(src) && src[start+1] >= '0' && src[start+1] <= '9'):
+		for s.offset < len(src) && (isWordByte(src[s.offset]) || src[s.offset] == '.' ||
+			((src[s.offset] == '+' || src[s.offset] == '-') && (src[s.offset-1] == 'e' || src[s.offset-1] == 'E'))) {
+			s.offset++
+		}
+		return token{kind: tokenNumber, text: src[start:s.offset], offset: start}, nil
+	case isWordByte(c):
+		for s.offset < len(src) && (isWordByte(src[s.offset]) || src[s.offset] == '$') {
+			s.offset++
+		}
+		return token{kind: tokenWord, text: src[start:s.offset], offset: start}, nil
+	case c == ':' && strings.HasPrefix(src[start:], "::"):
+		s.offset += 2
+		return token{kind: tokenPunct, text: "::", offset: start}, nil
+	}
+	s.offset++
+	return token{kind: tokenPunct, text: src[start:s.offset], offset: start}, nil
+}
+
+// quoted reads a string or identifier delimited by quote, where a doubled
+// quote stands for itself and, with backslash set, backslash escapes the
+// next character.
+func (s *scanner) quoted(quote byte, backslash bool) (string, error) {
+	start := s.offset
+	var b strings.Builder
+	for s.offset++; s.offset < len(s.src); s.offset++ {
+		c := s.src[s.offset]
+		switch {
+		case backslash && c == '\\' && s.offset+1 < len(s.src):
+			s.offset++
+			b.WriteByte(unescape(s.src[s.offset]))
+		case c != quote:
+			b.WriteByte(c)
+		case s.offset+1 < len(s.src) && s.src[s.offset+1] == quote:
+			b.WriteByte(quote)
+			s.offset++
+		default:
+			s.offset++
+			return b.String(), nil
+		}
+	}
+	return "", s.errorf(start, "unterminated %c", quote)
+}
+
+func unescape(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	case '0':
+		return 0
+	}
+	return c
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 0x80 || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isWord(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isWordByte(s[i]) || (s[i] >= '0' && s[i] <= '9' && i == 0) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,350 @@
+// Package dictionary documents a schema as a data dictionary: for every
+// table its columns, constraints, indexes, triggers and the tables it
+// references or is referenced by, written as Markdown or as a static HTML
+// site.
+package dictionary
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// Dictionary is the data dictionary of a schema, with its tables in schema
+// order.
+type Dictionary struct {
+	Tables []*Table
+}
+
+// Table documents a table. Comment is the text of the comments written
+// just before its CREATE TABLE statement, or after the opening parenthesis
+// on the same line.
+type Table struct {
+	Name         string
+	Comment      string
+	WithoutRowid bool
+	Columns      []Column
+	Constraints  []Constraint
+	Indexes      []*parser.Index
+	Triggers     []*parser.Trigger
+	References   []Reference
+	ReferencedBy []Reference
+
+	slug string
+}
+
+// Column documents a column. Type is the declared type, NotNull also holds
+// for the columns SQLite keeps from being NULL without a NOT NULL
+// constraint, and Keys lists the keys the column belongs to, among PK, FK
+// and UK (a unique key on its own). Comment is the text of the comments
+// written on the lines before the column definition or after it on the
+// same line.
+type Column struct {
+	Name      string
+	Type      string
+	Affinity  parser.Affinity
+	NotNull   bool
+	Default   string
+	Collation string
+	Keys      []string
+	Comment   string
+}
+
+// Constraint is a table constraint, or a CHECK constraint of the column
+// Column, written as SQL.
+type Constraint struct {
+	Column string
+	SQL    string
+}
+
+// Reference is a foreign key from the Columns of Table to the
+// ParentColumns of ParentTable. ParentColumns is empty when the foreign key
+// references the primary key without naming its columns.
+type Reference struct {
+	Table         string
+	Columns       []string
+	ParentTable   string
+	ParentColumns []string
+}
+
+// New returns the dictionary of schema, without comments.
+func New(schema *parser.Schema) *Dictionary {
+	d := &Dictionary{}
+	// index.html is the table list of WriteHTML.
+	slugs := map[string]bool{"index": true}
+	for _, table := range schema.Tables {
+		doc := &Table{
+			Name:         table.Name,
+			WithoutRowid: table.IsWithoutRowid,
+			slug:         uniqueSlug(slugs, table.Name),
+		}
+		for i := range table.Columns {
+			doc.Columns = append(doc.Columns, newColumn(table, &table.Columns[i]))
+		}
+		for i := range table.Columns {
+			for _, constraint := range table.Columns[i].Constraints {
+				if constraint.Type == parser.COLUMNCONSTRAINT_CHECK {
+					doc.Constraints = append(doc.Constraints, Constraint{
+						Column: table.Columns[i].Name,
+						SQL:    parser.FormatColumnConstraint(&constraint),
+					})
+				}
+			}
+		}
+		for i := range table.Constraints {
+			doc.Constraints = append(doc.Constraints, Constraint{SQL: parser.FormatTableConstraint(&table.Constraints[i])})
+		}
+		for _, index := range schema.Indexes {
+			if strings.EqualFold(index.Table, table.Name) {
+				doc.Indexes = append(doc.Indexes, index)
+			}
+		}
+		for _, trigger := range schema.Triggers {
+			if strings.EqualFold(trigger.Table, table.Name) {
+				doc.Triggers = append(doc.Triggers, trigger)
+			}
+		}
+		for _, fk := range table.ForeignKeys() {
+			doc.References = append(doc.References, Reference{
+				Table:         table.Name,
+				Columns:       fk.Columns,
+				ParentTable:   fk.Clause.Table,
+				ParentColumns: fk.Clause.ColumnName,
+			})
+		}
+		d.Tables = append(d.Tables, doc)
+	}
+	for _, table := range d.Tables {
+		for _, ref := range table.References {
+			if parent := d.Table(ref.ParentTable); parent != nil {
+				parent.ReferencedBy = append(parent.ReferencedBy, ref)
+			}
+		}
+	}
+	return d
+}
+
+// FromSQL parses the statements of sql and returns their dictionary, with
+// the comments of the tables and columns.
+func FromSQL(sql string) (*Dictionary, error) {
+	schema := &parser.Schema{}
+	comments := map[*parser.Table]tableComments{}
+	for _, statement := range parser.SplitStatements(sql) {
+		s, errCode := parser.ParseSchema(statement.Text)
+		if errCode != parser.ERROR_NONE {
+			return nil, fmt.Errorf("dictionary: line %d: cannot parse statement: %s error", statement.Line, errCode)
+		}
+		for _, table := range s.Tables {
+			comments[table] = findComments(statement.Text)
+		}
+		schema.Tables = append(schema.Tables, s.Tables...)
+		schema.Indexes = append(schema.Indexes, s.Indexes...)
+		schema.Views = append(schema.Views, s.Views...)
+		schema.Triggers = append(schema.Triggers, s.Triggers...)
+	}
+
+	d := New(schema)
+	for i, table := range schema.Tables {
+		c := comments[table]
+		d.Tables[i].Comment = c.table
+		for j := range d.Tables[i].Columns {
+			if j < len(c.columns) {
+				d.Tables[i].Columns[j].Comment = c.columns[j]
+			}
+		}
+	}
+	return d, nil
+}
+
+// Table returns the table with the given name, compared case-insensitively
+// like SQLite does, or nil.
+func (d *Dictionary) Table(name string) *Table {
+	for _, table := range d.Tables {
+		if strings.EqualFold(table.Name, name) {
+			return table
+		}
+	}
+	return nil
+}
+
+// uniqueSlug returns a lower-case name made of letters, digits, '-' and
+// '_' for the anchors and file names of a table, unique among slugs.
+func uniqueSlug(slugs map[string]bool, name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	base := b.String()
+	if base == "" {
+		base = "table"
+	}
+	slug := base
+	for i := 2; slugs[slug]; i++ {
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+	slugs[slug] = true
+	return slug
+}
+
+func newColumn(table *parser.Table, column *parser.Column) Column {
+	doc := Column{
+		Name:      column.Name,
+		Type:      columnType(column),
+		Affinity:  column.Affinity(),
+		NotNull:   column.IsNotnull,
+		Default:   column.DefaultExpr,
+		Collation: column.CollateName,
+	}
+
+	primaryKey := table.PrimaryKey()
+	if contains(primaryKey, column.Name) {
+		doc.Keys = append(doc.Keys, "PK")
+		// SQLite keeps the rowid alias and the primary key of a WITHOUT
+		// ROWID table from being NULL.
+		if table.IsWithoutRowid || len(primaryKey) == 1 && strings.EqualFold(column.Type, "INTEGER") {
+			doc.NotNull = true
+		}
+	}
+	for _, fk := range table.ForeignKeys() {
+		if contains(fk.Columns, column.Name) {
+			doc.Keys = append(doc.Keys, "FK")
+			break
+		}
+	}
+	if !contains(primaryKey, column.Name) && isUnique(table, column) {
+		doc.Keys = append(doc.Keys, "UK")
+	}
+	return doc
+}
+
+func columnType(column *parser.Column) string {
+	if column.TypeName != nil {
+		return column.TypeName.String()
+	}
+	if column.Length != "" {
+		return column.Type + "(" + column.Length + ")"
+	}
+	return column.Type
+}
+
+func contains(names []string, name string) bool {
+	for _, s := range names {
+		if strings.EqualFold(s, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnique reports whether the column is UNIQUE on its own.
+func isUnique(table *parser.Table, column *parser.Column) bool {
+	if column.IsUnique {
+		return true
+	}
+	for _, constraint := range table.Constraints {
+		if constraint.Type == parser.TABLECONSTRAINT_UNIQUE && len(constraint.IndexedColumns) == 1 &&
+			constraint.IndexedColumns[0].Expr == nil && strings.EqualFold(constraint.IndexedColumns[0].Name, column.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+type tableComments struct {
+	table   string
+	columns []string
+}
+
+// findComments returns the comments of a CREATE TABLE statement: those
+// before the parenthesis opening its definitions or after it on the same
+// line, for the table, and those of each column definition. A comment on
+// its own lines belongs to the next definition and one after a definition
+// on the same line to that definition. Lint suppression comments are left
+// out.
+func findComments(sql string) tableComments {
+	var c tableComments
+	var pending []string
+	depth, item := 0, -1
+	started := false // whether a token of the current item was seen
+	// The line the last significant token ends on, and the item it
+	// belongs to, or -1 for the parenthesis opening the definitions.
+	lastLine, lastItem := 0, -1
+
+	attach := func(i int, text string) {
+		for len(c.columns) <= i {
+			c.columns = append(c.columns, "")
+		}
+		if c.columns[i] != "" {
+			c.columns[i] += "\n"
+		}
+		c.columns[i] += text
+	}
+
+	for _, token := range parser.Tokenize(sql) {
+		switch token.Kind {
+		case parser.TOKEN_WHITESPACE:
+			continue
+		case parser.TOKEN_COMMENT:
+			text := commentText(token.Text)
+			switch {
+			case text == "" || strings.HasPrefix(text, "lint:"):
+			case depth == 0 && item < 0, depth == 1 && lastItem < 0 && token.Line == lastLine:
+				if c.table != "" {
+					c.table += "\n"
+				}
+				c.table += text
+			case depth >= 1 && lastItem >= 0 && token.Line == lastLine:
+				attach(lastItem, text)
+			case depth >= 1:
+				pending = append(pending, text)
+			}
+			continue
+		}
+
+		lastItem = item
+		if depth == 1 && token.Text == "," {
+			item, started = item+1, false
+		} else if depth == 1 && token.Text != ")" && !started {
+			started = true
+			for _, text := range pending {
+				attach(item, text)
+			}
+			pending = nil
+		}
+		switch token.Text {
+		case "(":
+			depth++
+			if depth == 1 && item < 0 {
+				item, lastItem = 0, -1
+			}
+		case ")":
+			depth--
+		}
+		lastLine = token.Line + strings.Count(token.Text, "\n")
+	}
+	return c
+}
+
+// commentText returns the text of a comment without its delimiters and
+// the leading stars of the lines of a block comment.
+func commentText(comment string) string {
+	if strings.HasPrefix(comment, "--") {
+		return strings.TrimSpace(comment[2:])
+	}
+	comment = strings.TrimPrefix(comment, "/*")
+	comment = strings.TrimSuffix(comment, "*/")
+	var lines []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
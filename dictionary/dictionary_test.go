@@ -0,0 +1,195 @@
+package dictionary
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+const schema = `
+-- People who place orders.
+-- lint:ignore require-primary-key
+CREATE TABLE customers (
+  id INTEGER PRIMARY KEY, -- Row id.
+  /* Login e-mail,
+   * lower case. */
+  email TEXT NOT NULL UNIQUE COLLATE nocase
+);
+
+CREATE TABLE orders ( -- Orders placed by customers.
+  id INTEGER PRIMARY KEY,
+  customer_id INTEGER NOT NULL REFERENCES customers (id),
+  -- Pending or shipped.
+  status TEXT DEFAULT 'pending',
+  total NUMERIC(10, 2), -- In cents.
+  -- ignored
+  UNIQUE (customer_id, id)
+);
+CREATE INDEX orders_status ON orders (status DESC) WHERE status <> 'shipped';
+CREATE TRIGGER orders_shipped AFTER UPDATE OF status ON orders WHEN new.status = 'shipped' BEGIN SELECT 1; END;
+`
+
+func TestFromSQL(t *testing.T) {
+	d, err := FromSQL(schema)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, d.Tables, 2) {
+		return
+	}
+
+	customers, orders := d.Tables[0], d.Tables[1]
+	assert.Equal(t, "People who place orders.", customers.Comment)
+	assert.Equal(t, []Column{
+		{Name: "id", Type: "INTEGER", Affinity: 3, NotNull: true, Keys: []string{"PK"}, Comment: "Row id."},
+		{Name: "email", Type: "TEXT", Affinity: 1, NotNull: true, Collation: "nocase", Keys: []string{"UK"}, Comment: "Login e-mail,\nlower case."},
+	}, customers.Columns)
+	assert.Equal(t, []Reference{{"orders", []string{"customer_id"}, "customers", []string{"id"}}}, customers.ReferencedBy)
+
+	assert.Equal(t, "Orders placed by customers.", orders.Comment)
+	var comments []string
+	for _, column := range orders.Columns {
+		comments = append(comments, column.Comment)
+	}
+	assert.Equal(t, []string{"", "", "Pending or shipped.", "In cents."}, comments)
+	assert.Equal(t, "NUMERIC(10, 2)", orders.Columns[3].Type)
+	assert.Equal(t, []string{"FK"}, orders.Columns[1].Keys)
+	assert.Equal(t, "pending", orders.Columns[2].Default)
+	assert.Equal(t, []Constraint{{"", "UNIQUE (customer_id, id)"}}, orders.Constraints)
+	assert.Len(t, orders.Indexes, 1)
+	assert.Len(t, orders.Triggers, 1)
+	assert.Equal(t, d.Table("ORDERS"), orders)
+
+	// Column CHECK constraints are listed with the table constraints.
+	d = New(&parser.Schema{Tables: []*parser.Table{{
+		Name: "t",
+		Columns: []parser.Column{{Name: "a", Constraints: []parser.ColumnConstraint{
+			{Type: parser.COLUMNCONSTRAINT_CHECK, Expr: "a > 0"},
+		}}},
+	}}})
+	assert.Equal(t, []Constraint{{"a", "CHECK (a > 0)"}}, d.Tables[0].Constraints)
+
+	_, err = FromSQL("CREATE TABLE t (a,")
+	assert.EqualError(t, err, "dictionary: line 1: cannot parse statement: SYNTAX error")
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	d, err := FromSQL(schema + "CREATE TABLE \"Orders|2\" (a REFERENCES missing);")
+	if !assert.NoError(t, err) {
+		return
+	}
+	var b bytes.Buffer
+	assert.NoError(t, WriteMarkdown(&b, d))
+	assert.Equal(t, `# Data dictionary
+
+| Table | Columns | Description |
+|---|---|---|
+| [customers](#table-customers) | 2 | People who place orders. |
+| [orders](#table-orders) | 4 | Orders placed by customers. |
+| [Orders\|2](#table-orders-2) | 1 |  |
+
+<a id="table-customers"></a>
+
+## customers
+
+People who place orders.
+
+| Column | Type | Affinity | Nullable | Default | Collation | Key | Description |
+|---|---|---|---|---|---|---|---|
+| `+"`id`"+` | INTEGER | INTEGER | no |  |  | PK | Row id. |
+| `+"`email`"+` | TEXT | TEXT | no |  | nocase | UK | Login e-mail,<br>lower case. |
+
+### Referenced by
+
+- [orders](#table-orders) (`+"`customer_id`"+`)
+
+<a id="table-orders"></a>
+
+## orders
+
+Orders placed by customers.
+
+| Column | Type | Affinity | Nullable | Default | Collation | Key | Description |
+|---|---|---|---|---|---|---|---|
+| `+"`id`"+` | INTEGER | INTEGER | no |  |  | PK |  |
+| `+"`customer_id`"+` | INTEGER | INTEGER | no |  |  | FK |  |
+| `+"`status`"+` | TEXT | TEXT | yes | pending |  |  | Pending or shipped. |
+| `+"`total`"+` | NUMERIC(10, 2) | NUMERIC | yes |  |  |  | In cents. |
+
+### Constraints
+
+- `+"`UNIQUE (customer_id, id)`"+`
+
+### Indexes
+
+- `+"`orders_status`"+` on `+"`status DESC`"+` where `+"`status <> 'shipped'`"+`
+
+### References
+
+- `+"`customer_id`"+` → [customers](#table-customers) (`+"`id`"+`)
+
+### Triggers
+
+- `+"`orders_shipped`"+`: AFTER UPDATE OF status WHEN new.status = 'shipped'
+
+<a id="table-orders-2"></a>
+
+## Orders\|2
+
+| Column | Type | Affinity | Nullable | Default | Collation | Key | Description |
+|---|---|---|---|---|---|---|---|
+| `+"`a`"+` |  | BLOB | yes |  |  | FK |  |
+
+### References
+
+- `+"`a`"+` → missing
+`, b.String())
+}
+
+func TestWriteHTML(t *testing.T) {
+	d, err := FromSQL(schema)
+	if !assert.NoError(t, err) {
+		return
+	}
+	dir := filepath.Join(t.TempDir(), "site")
+	assert.NoError(t, WriteHTML(dir, d))
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(index), `<tr><td><a href="customers.html">customers</a></td><td>2</td><td>People who place orders.</td></tr>`)
+
+	page, err := os.ReadFile(filepath.Join(dir, "orders.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `<nav><a href="index.html">Data dictionary</a></nav>`)
+	assert.Contains(t, string(page), `<li><code>customer_id</code> → <a href="customers.html">customers</a> (<code>id</code>)</li>`)
+	assert.Contains(t, string(page), `<li><code>orders_status</code> on <code>status DESC</code> where <code>status &lt;&gt; &#39;shipped&#39;</code></li>`)
+	assert.Contains(t, string(page), `<td class="comment">Pending or shipped.</td>`)
+	assert.NotContains(t, string(page), "<link")
+
+	page, err = os.ReadFile(filepath.Join(dir, "customers.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `<li><a href="orders.html">orders</a> (<code>customer_id</code>)</li>`)
+}
+
+func TestWriteHTMLIndexTable(t *testing.T) {
+	d, err := FromSQL(`CREATE TABLE "index" (a); CREATE TABLE "Index-2" (b);`)
+	if !assert.NoError(t, err) {
+		return
+	}
+	dir := t.TempDir()
+	assert.NoError(t, WriteHTML(dir, d))
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(index), `<a href="index-2.html">index</a>`)
+	assert.Contains(t, string(index), `<a href="index-2-2.html">Index-2</a>`)
+
+	page, err := os.ReadFile(filepath.Join(dir, "index-2.html"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `<code>a</code>`)
+}
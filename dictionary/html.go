@@ -0,0 +1,151 @@
+package dictionary
+
+import (
+	"bytes"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const htmlStyle = `
+body { font-family: system-ui, sans-serif; margin: 2em auto; max-width: 72em; padding: 0 1em; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: .3em .6em; text-align: left; vertical-align: top; }
+th { background: #f3f3f3; }
+code { font-family: ui-monospace, monospace; }
+.comment { white-space: pre-line; }
+nav { margin-bottom: 1em; }
+`
+
+var htmlTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"join":         strings.Join,
+	"indexColumns": indexColumns,
+	"triggerEvent": triggerEvent,
+	"summary":      summary,
+}).Parse(`
+{{define "head"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.}}</title>
+<style>` + htmlStyle + `</style>
+</head>
+<body>
+{{end}}
+
+{{define "index"}}{{template "head" "Data dictionary"}}<h1>Data dictionary</h1>
+<table>
+<tr><th>Table</th><th>Columns</th><th>Description</th></tr>
+{{range .Tables}}<tr><td><a href="{{.File}}">{{.Name}}</a></td><td>{{len .Columns}}</td><td>{{summary .Comment}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+{{end}}
+
+{{define "link"}}{{if .File}}<a href="{{.File}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}{{end}}
+
+{{define "table"}}{{template "head" .Table.Name}}<nav><a href="index.html">Data dictionary</a></nav>
+{{with .Table}}<h1>{{.Name}}</h1>
+{{if .Comment}}<p class="comment">{{.Comment}}</p>
+{{end}}{{if .WithoutRowid}}<p>WITHOUT ROWID table.</p>
+{{end}}<table>
+<tr><th>Column</th><th>Type</th><th>Affinity</th><th>Nullable</th><th>Default</th><th>Collation</th><th>Key</th><th>Description</th></tr>
+{{range .Columns}}<tr><td><code>{{.Name}}</code></td><td>{{.Type}}</td><td>{{.Affinity}}</td><td>{{if .NotNull}}no{{else}}yes{{end}}</td><td>{{.Default}}</td><td>{{.Collation}}</td><td>{{join .Keys ", "}}</td><td class="comment">{{.Comment}}</td></tr>
+{{end}}</table>
+{{if .Constraints}}<h2>Constraints</h2>
+<ul>
+{{range .Constraints}}<li>{{if .Column}}<code>{{.Column}}</code>: {{end}}<code>{{.SQL}}</code></li>
+{{end}}</ul>
+{{end}}{{if .Indexes}}<h2>Indexes</h2>
+<ul>
+{{range .Indexes}}<li><code>{{.Name}}</code>{{if .IsUnique}} (unique){{end}} on <code>{{indexColumns .}}</code>{{if .Where}} where <code>{{.Where}}</code>{{end}}</li>
+{{end}}</ul>
+{{end}}{{end}}{{if .References}}<h2>References</h2>
+<ul>
+{{range .References}}<li><code>{{join .Columns ", "}}</code> → {{template "link" .Parent}}{{if .ParentColumns}} (<code>{{join .ParentColumns ", "}}</code>){{end}}</li>
+{{end}}</ul>
+{{end}}{{if .ReferencedBy}}<h2>Referenced by</h2>
+<ul>
+{{range .ReferencedBy}}<li>{{template "link" .Child}} (<code>{{join .Columns ", "}}</code>)</li>
+{{end}}</ul>
+{{end}}{{with .Table}}{{if .Triggers}}<h2>Triggers</h2>
+<ul>
+{{range .Triggers}}<li><code>{{.Name}}</code>: {{triggerEvent .}}</li>
+{{end}}</ul>
+{{end}}{{end}}</body>
+</html>
+{{end}}
+`))
+
+// htmlLink is a table name and the page of the table, if the dictionary
+// has it.
+type htmlLink struct {
+	Name string
+	File string
+}
+
+type htmlReference struct {
+	Reference
+	Parent htmlLink
+	Child  htmlLink
+}
+
+func (t *Table) file() string {
+	return t.slug + ".html"
+}
+
+func link(d *Dictionary, name string) htmlLink {
+	if table := d.Table(name); table != nil {
+		return htmlLink{table.Name, table.file()}
+	}
+	return htmlLink{Name: name}
+}
+
+// WriteHTML writes the dictionary as a static site in dir, which is
+// created if needed: index.html lists the tables and links to a page per
+// table, whose references link to each other. The pages have their style
+// inline and load nothing else.
+func WriteHTML(dir string, d *Dictionary) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	type indexEntry struct {
+		*Table
+		File string
+	}
+	var entries []indexEntry
+	for _, table := range d.Tables {
+		entries = append(entries, indexEntry{table, table.file()})
+	}
+	var page bytes.Buffer
+	if err := htmlTemplates.ExecuteTemplate(&page, "index", struct{ Tables []indexEntry }{entries}); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), page.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	for _, table := range d.Tables {
+		data := struct {
+			Table        *Table
+			References   []htmlReference
+			ReferencedBy []htmlReference
+		}{Table: table}
+		for _, ref := range table.References {
+			data.References = append(data.References, htmlReference{ref, link(d, ref.ParentTable), link(d, ref.Table)})
+		}
+		for _, ref := range table.ReferencedBy {
+			data.ReferencedBy = append(data.ReferencedBy, htmlReference{ref, link(d, ref.ParentTable), link(d, ref.Table)})
+		}
+		page.Reset()
+		if err := htmlTemplates.ExecuteTemplate(&page, "table", data); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, table.file()), page.Bytes(), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
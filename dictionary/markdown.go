@@ -0,0 +1,185 @@
+package dictionary
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// WriteMarkdown writes the dictionary as one Markdown document: a list of
+// the tables, then a section per table whose references link to the
+// sections of the other tables.
+func WriteMarkdown(w io.Writer, d *Dictionary) error {
+	b := bufio.NewWriter(w)
+	b.WriteString("# Data dictionary\n\n")
+	b.WriteString("| Table | Columns | Description |\n|---|---|---|\n")
+	for _, table := range d.Tables {
+		fmt.Fprintf(b, "| [%s](#%s) | %d | %s |\n", markdownEscape(table.Name), table.anchor(), len(table.Columns), cell(summary(table.Comment)))
+	}
+
+	for _, table := range d.Tables {
+		fmt.Fprintf(b, "\n<a id=\"%s\"></a>\n\n## %s\n\n", table.anchor(), markdownEscape(table.Name))
+		if table.Comment != "" {
+			fmt.Fprintf(b, "%s\n\n", table.Comment)
+		}
+		if table.WithoutRowid {
+			b.WriteString("WITHOUT ROWID table.\n\n")
+		}
+		b.WriteString("| Column | Type | Affinity | Nullable | Default | Collation | Key | Description |\n")
+		b.WriteString("|---|---|---|---|---|---|---|---|\n")
+		for _, column := range table.Columns {
+			fmt.Fprintf(b, "| %s | %s | %s | %s | %s | %s | %s | %s |\n", codeCell(column.Name), cell(column.Type), column.Affinity,
+				yesNo(!column.NotNull), cell(column.Default), cell(column.Collation), strings.Join(column.Keys, ", "), cell(column.Comment))
+		}
+
+		if len(table.Constraints) > 0 {
+			b.WriteString("\n### Constraints\n\n")
+			for _, constraint := range table.Constraints {
+				if constraint.Column != "" {
+					fmt.Fprintf(b, "- %s: %s\n", code(constraint.Column), code(constraint.SQL))
+				} else {
+					fmt.Fprintf(b, "- %s\n", code(constraint.SQL))
+				}
+			}
+		}
+		if len(table.Indexes) > 0 {
+			b.WriteString("\n### Indexes\n\n")
+			for _, index := range table.Indexes {
+				fmt.Fprintf(b, "- %s", code(index.Name))
+				if index.IsUnique {
+					b.WriteString(" (unique)")
+				}
+				fmt.Fprintf(b, " on %s", code(indexColumns(index)))
+				if index.Where != "" {
+					fmt.Fprintf(b, " where %s", code(index.Where))
+				}
+				b.WriteString("\n")
+			}
+		}
+		if len(table.References) > 0 {
+			b.WriteString("\n### References\n\n")
+			for _, ref := range table.References {
+				fmt.Fprintf(b, "- %s → %s", code(strings.Join(ref.Columns, ", ")), markdownLink(d, ref.ParentTable))
+				if len(ref.ParentColumns) > 0 {
+					fmt.Fprintf(b, " (%s)", code(strings.Join(ref.ParentColumns, ", ")))
+				}
+				b.WriteString("\n")
+			}
+		}
+		if len(table.ReferencedBy) > 0 {
+			b.WriteString("\n### Referenced by\n\n")
+			for _, ref := range table.ReferencedBy {
+				fmt.Fprintf(b, "- %s (%s)\n", markdownLink(d, ref.Table), code(strings.Join(ref.Columns, ", ")))
+			}
+		}
+		if len(table.Triggers) > 0 {
+			b.WriteString("\n### Triggers\n\n")
+			for _, trigger := range table.Triggers {
+				fmt.Fprintf(b, "- %s: %s\n", code(trigger.Name), markdownEscape(triggerEvent(trigger)))
+			}
+		}
+	}
+	return b.Flush()
+}
+
+func (t *Table) anchor() string {
+	return "table-" + t.slug
+}
+
+// markdownLink returns a link to the section of the table name, or its
+// name when the dictionary does not have it.
+func markdownLink(d *Dictionary, name string) string {
+	if table := d.Table(name); table != nil {
+		return fmt.Sprintf("[%s](#%s)", markdownEscape(table.Name), table.anchor())
+	}
+	return markdownEscape(name)
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "[", `\[`, "]", `\]`, "<", "&lt;", "|", `\|`)
+
+func markdownEscape(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// cell returns text escaped for a table cell, where lines are separated
+// by <br>.
+func cell(s string) string {
+	return strings.ReplaceAll(markdownEscape(s), "\n", "<br>")
+}
+
+// code returns s as an inline code span, fenced with enough backticks for
+// the backticks of s.
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+	fence := "`"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	if len(fence) > 1 || strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + strings.ReplaceAll(s, "\n", " ") + fence
+}
+
+// codeCell returns s as an inline code span for a table cell.
+func codeCell(s string) string {
+	return strings.ReplaceAll(code(s), "|", `\|`)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// summary returns the first line of a comment.
+func summary(comment string) string {
+	if i := strings.IndexByte(comment, '\n'); i >= 0 {
+		return comment[:i]
+	}
+	return comment
+}
+
+// indexColumns returns the columns of an index as SQL.
+func indexColumns(index *parser.Index) string {
+	columns := make([]string, len(index.Columns))
+	for i, column := range index.Columns {
+		s := column.Name
+		if column.Expr == nil {
+			s = parser.QuoteIdentifier(column.Name)
+		}
+		if column.CollateName != "" {
+			s += " COLLATE " + column.CollateName
+		}
+		if column.Order == parser.ORDER_DESC {
+			s += " DESC"
+		}
+		columns[i] = s
+	}
+	return strings.Join(columns, ", ")
+}
+
+// triggerEvent describes when a trigger fires, such as "AFTER UPDATE OF
+// status FOR EACH ROW".
+func triggerEvent(trigger *parser.Trigger) string {
+	s := trigger.Event
+	if trigger.Timing != "" {
+		s = trigger.Timing + " " + s
+	}
+	if len(trigger.Columns) > 0 {
+		s += " OF " + strings.Join(trigger.Columns, ", ")
+	}
+	if trigger.ForEachRow {
+		s += " FOR EACH ROW"
+	}
+	if trigger.When != "" {
+		s += " WHEN " + trigger.When
+	}
+	return s
+}
@@ -0,0 +1,267 @@
+// Package erd draws entity-relationship diagrams of parsed CREATE TABLE
+// statements as Mermaid, PlantUML or Graphviz DOT.
+package erd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// ColumnMode selects the columns drawn in each entity.
+type ColumnMode int
+
+const (
+	// COLUMNS_ALL draws every column.
+	COLUMNS_ALL ColumnMode = iota
+	// COLUMNS_KEYS draws the primary key, foreign key and unique columns.
+	COLUMNS_KEYS
+	// COLUMNS_NONE collapses the entities to their names.
+	COLUMNS_NONE
+)
+
+// Options selects what a diagram shows. Include and Exclude are patterns
+// in the syntax of path.Match, matched against table names ignoring case:
+// when Include is empty every table is drawn, minus those matching
+// Exclude. Relationships are drawn between the drawn tables only.
+type Options struct {
+	Include []string
+	Exclude []string
+	Columns ColumnMode
+}
+
+// Cardinality is how many rows of one side of a relationship go with a
+// row of the other side.
+type Cardinality int
+
+const (
+	CARDINALITY_ZERO_OR_ONE Cardinality = iota
+	CARDINALITY_EXACTLY_ONE
+	CARDINALITY_ZERO_OR_MANY
+)
+
+// Attribute is a column of an entity. NotNull includes the columns SQLite
+// keeps from being NULL without a NOT NULL constraint: the rowid alias and
+// the primary key of a WITHOUT ROWID table. Unique is set for the columns
+// that are a unique key on their own, other than the primary key.
+type Attribute struct {
+	Name       string
+	Type       string
+	PrimaryKey bool
+	ForeignKey bool
+	NotNull    bool
+	Unique     bool
+}
+
+// Entity is a table of the diagram.
+type Entity struct {
+	Name       string
+	Attributes []Attribute
+}
+
+// Relationship is a foreign key from the Child table to the Parent table.
+// ParentCardinality is how many parent rows a child row references: one,
+// or zero or one when a child column is nullable. ChildCardinality is how
+// many child rows reference a parent row: zero or one when the child
+// columns are a unique key of the child table, zero or many otherwise.
+type Relationship struct {
+	Child             string
+	Parent            string
+	Columns           []string
+	ParentColumns     []string
+	ChildCardinality  Cardinality
+	ParentCardinality Cardinality
+}
+
+// Diagram is the content of an entity-relationship diagram, in the order
+// of the tables it was built from.
+type Diagram struct {
+	Entities      []Entity
+	Relationships []Relationship
+}
+
+// New returns the diagram of the tables selected by options. A malformed
+// pattern is reported as an error.
+func New(options Options, tables ...*parser.Table) (*Diagram, error) {
+	var selected []*parser.Table
+	for _, table := range tables {
+		ok, err := selects(options, table.Name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			selected = append(selected, table)
+		}
+	}
+
+	d := &Diagram{}
+	for _, table := range selected {
+		entity := Entity{Name: table.Name}
+		if options.Columns != COLUMNS_NONE {
+			for _, attr := range attributes(table) {
+				if options.Columns == COLUMNS_ALL || attr.PrimaryKey || attr.ForeignKey || attr.Unique {
+					entity.Attributes = append(entity.Attributes, attr)
+				}
+			}
+		}
+		d.Entities = append(d.Entities, entity)
+	}
+	for _, table := range selected {
+		for _, fk := range table.ForeignKeys() {
+			parent := findTable(selected, fk.Clause.Table)
+			if parent == nil {
+				continue
+			}
+			rel := Relationship{
+				Child:             table.Name,
+				Parent:            parent.Name,
+				Columns:           fk.Columns,
+				ParentColumns:     fk.Clause.ColumnName,
+				ChildCardinality:  CARDINALITY_ZERO_OR_MANY,
+				ParentCardinality: CARDINALITY_EXACTLY_ONE,
+			}
+			if len(rel.ParentColumns) == 0 {
+				rel.ParentColumns = parent.PrimaryKey()
+			}
+			if isUniqueKey(table, fk.Columns) {
+				rel.ChildCardinality = CARDINALITY_ZERO_OR_ONE
+			}
+			for _, name := range fk.Columns {
+				if column := table.Column(name); column == nil || !notNull(table, column) {
+					rel.ParentCardinality = CARDINALITY_ZERO_OR_ONE
+				}
+			}
+			d.Relationships = append(d.Relationships, rel)
+		}
+	}
+	return d, nil
+}
+
+func selects(options Options, name string) (bool, error) {
+	name = strings.ToLower(name)
+	match := func(patterns []string) (bool, error) {
+		for _, pattern := range patterns {
+			ok, err := path.Match(strings.ToLower(pattern), name)
+			if err != nil {
+				return false, fmt.Errorf("erd: bad pattern %q", pattern)
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	if len(options.Include) > 0 {
+		if ok, err := match(options.Include); !ok || err != nil {
+			return false, err
+		}
+	}
+	excluded, err := match(options.Exclude)
+	return !excluded, err
+}
+
+func findTable(tables []*parser.Table, name string) *parser.Table {
+	for _, table := range tables {
+		if strings.EqualFold(table.Name, name) {
+			return table
+		}
+	}
+	return nil
+}
+
+func attributes(table *parser.Table) []Attribute {
+	primaryKey := table.PrimaryKey()
+	foreign := map[string]bool{}
+	for _, fk := range table.ForeignKeys() {
+		for _, name := range fk.Columns {
+			foreign[strings.ToLower(name)] = true
+		}
+	}
+
+	attrs := make([]Attribute, 0, len(table.Columns))
+	for i := range table.Columns {
+		column := &table.Columns[i]
+		attr := Attribute{
+			Name:       column.Name,
+			Type:       columnType(column),
+			PrimaryKey: contains(primaryKey, column.Name),
+			ForeignKey: foreign[strings.ToLower(column.Name)],
+			NotNull:    notNull(table, column),
+		}
+		attr.Unique = !attr.PrimaryKey && isUniqueKey(table, []string{column.Name})
+		attrs = append(attrs, attr)
+	}
+	return attrs
+}
+
+func columnType(column *parser.Column) string {
+	if column.TypeName != nil {
+		return column.TypeName.String()
+	}
+	if column.Length != "" {
+		return column.Type + "(" + column.Length + ")"
+	}
+	return column.Type
+}
+
+func contains(names []string, name string) bool {
+	for _, s := range names {
+		if strings.EqualFold(s, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// notNull reports whether SQLite keeps the column from being NULL.
+func notNull(table *parser.Table, column *parser.Column) bool {
+	if column.IsNotnull {
+		return true
+	}
+	primaryKey := table.PrimaryKey()
+	if !contains(primaryKey, column.Name) {
+		return false
+	}
+	return table.IsWithoutRowid || len(primaryKey) == 1 && strings.EqualFold(column.Type, "INTEGER")
+}
+
+// isUniqueKey reports whether the columns are exactly the primary key or a
+// UNIQUE constraint of table, in any order.
+func isUniqueKey(table *parser.Table, columns []string) bool {
+	keys := [][]string{table.PrimaryKey()}
+	for _, column := range table.Columns {
+		if column.IsUnique {
+			keys = append(keys, []string{column.Name})
+		}
+	}
+	for _, constraint := range table.Constraints {
+		if constraint.Type != parser.TABLECONSTRAINT_UNIQUE {
+			continue
+		}
+		var names []string
+		for _, column := range constraint.IndexedColumns {
+			if column.Expr != nil {
+				names = nil
+				break
+			}
+			names = append(names, column.Name)
+		}
+		keys = append(keys, names)
+	}
+
+	for _, key := range keys {
+		if len(key) == 0 || len(key) != len(columns) {
+			continue
+		}
+		same := true
+		for _, name := range columns {
+			same = same && contains(key, name)
+		}
+		if same {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package erd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+const schema = `
+CREATE TABLE customers (
+  id INTEGER PRIMARY KEY,
+  email VARCHAR(255) NOT NULL UNIQUE
+);
+CREATE TABLE orders (
+  id INTEGER PRIMARY KEY,
+  customer_id INTEGER NOT NULL REFERENCES customers (id),
+  coupon_id INTEGER REFERENCES coupons,
+  note TEXT
+);
+CREATE TABLE invoices (
+  order_id INTEGER NOT NULL,
+  "total amount" NUMERIC(10, 2),
+  PRIMARY KEY (order_id),
+  FOREIGN KEY (order_id) REFERENCES orders
+) WITHOUT ROWID;
+CREATE TABLE coupons (code TEXT PRIMARY KEY);
+`
+
+func parseTables(t *testing.T, sql string) []*parser.Table {
+	s, errCode := parser.ParseSchema(sql)
+	if !assert.Equal(t, parser.ERROR_NONE, errCode) {
+		t.FailNow()
+	}
+	return s.Tables
+}
+
+func TestNew(t *testing.T) {
+	tables := parseTables(t, schema)
+	d, err := New(Options{}, tables...)
+	assert.NoError(t, err)
+
+	assert.Len(t, d.Entities, 4)
+	assert.Equal(t, []Attribute{
+		{Name: "id", Type: "INTEGER", PrimaryKey: true, NotNull: true},
+		{Name: "email", Type: "VARCHAR(255)", NotNull: true, Unique: true},
+	}, d.Entities[0].Attributes)
+	// A TEXT primary key of a rowid table may be NULL.
+	assert.Equal(t, Attribute{Name: "code", Type: "TEXT", PrimaryKey: true}, d.Entities[3].Attributes[0])
+
+	assert.Equal(t, []Relationship{
+		{"orders", "customers", []string{"customer_id"}, []string{"id"}, CARDINALITY_ZERO_OR_MANY, CARDINALITY_EXACTLY_ONE},
+		{"orders", "coupons", []string{"coupon_id"}, []string{"code"}, CARDINALITY_ZERO_OR_MANY, CARDINALITY_ZERO_OR_ONE},
+		{"invoices", "orders", []string{"order_id"}, []string{"id"}, CARDINALITY_ZERO_OR_ONE, CARDINALITY_EXACTLY_ONE},
+	}, d.Relationships)
+
+	d, err = New(Options{Include: []string{"*S"}, Exclude: []string{"coup*"}, Columns: COLUMNS_KEYS}, tables...)
+	assert.NoError(t, err)
+	var names []string
+	for _, entity := range d.Entities {
+		names = append(names, entity.Name)
+	}
+	assert.Equal(t, []string{"customers", "orders", "invoices"}, names)
+	assert.Len(t, d.Entities[1].Attributes, 3)
+	assert.Len(t, d.Relationships, 2)
+
+	d, err = New(Options{Columns: COLUMNS_NONE}, tables...)
+	assert.NoError(t, err)
+	assert.Empty(t, d.Entities[0].Attributes)
+
+	_, err = New(Options{Exclude: []string{"["}}, tables...)
+	assert.EqualError(t, err, `erd: bad pattern "["`)
+}
+
+func TestWrite(t *testing.T) {
+	tables := parseTables(t, schema)
+	d, err := New(Options{Include: []string{"orders", "invoices", "coupons"}}, tables...)
+	assert.NoError(t, err)
+
+	var b bytes.Buffer
+	assert.NoError(t, WriteMermaid(&b, d))
+	assert.Equal(t, `erDiagram
+    orders {
+        INTEGER id PK "NOT NULL"
+        INTEGER customer_id FK "NOT NULL"
+        INTEGER coupon_id FK
+        TEXT note
+    }
+    invoices {
+        INTEGER order_id PK, FK "NOT NULL"
+        NUMERIC(10_2) total_amount
+    }
+    coupons {
+        TEXT code PK
+    }
+    coupons |o--o{ orders : "coupon_id"
+    orders ||--o| invoices : "order_id"
+`, b.String())
+
+	b.Reset()
+	assert.NoError(t, WritePlantUML(&b, d))
+	assert.Equal(t, `@startuml
+hide circle
+skinparam linetype ortho
+
+entity "orders" as e0 {
+  * id : INTEGER <<PK>>
+  --
+  * customer_id : INTEGER <<FK>>
+  coupon_id : INTEGER <<FK>>
+  note : TEXT
+}
+
+entity "invoices" as e1 {
+  * order_id : INTEGER <<PK>> <<FK>>
+  --
+  total amount : NUMERIC(10, 2)
+}
+
+entity "coupons" as e2 {
+  code : TEXT <<PK>>
+}
+
+e2 |o--o{ e0 : coupon_id
+e0 ||--o| e1 : order_id
+@enduml
+`, b.String())
+
+	d, err = New(Options{Include: []string{"orders", "invoices"}, Columns: COLUMNS_KEYS}, tables...)
+	assert.NoError(t, err)
+	b.Reset()
+	assert.NoError(t, WriteDOT(&b, d))
+	assert.Equal(t, `digraph erd {
+	rankdir=LR;
+	node [shape=plaintext];
+	"orders" [label=<<table border="0" cellborder="1" cellspacing="0"><tr><td colspan="3" bgcolor="lightgrey"><b>orders</b></td></tr><tr><td port="c0" align="left">id</td><td align="left">INTEGER</td><td align="left">PK, NN</td></tr><tr><td port="c1" align="left">customer_id</td><td align="left">INTEGER</td><td align="left">FK, NN</td></tr><tr><td port="c2" align="left">coupon_id</td><td align="left">INTEGER</td><td align="left">FK</td></tr></table>>];
+	"invoices" [label=<<table border="0" cellborder="1" cellspacing="0"><tr><td colspan="3" bgcolor="lightgrey"><b>invoices</b></td></tr><tr><td port="c0" align="left">order_id</td><td align="left">INTEGER</td><td align="left">PK, FK, NN</td></tr></table>>];
+	"invoices":c0 -> "orders":c0 [dir=both, arrowtail=teeodot, arrowhead=teetee, label="order_id"];
+}
+`, b.String())
+}
@@ -0,0 +1,198 @@
+package erd
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/internal/quote"
+)
+
+// keys returns the key markers of an attribute: PK, FK and UK.
+func (a Attribute) keys() []string {
+	var keys []string
+	if a.PrimaryKey {
+		keys = append(keys, "PK")
+	}
+	if a.ForeignKey {
+		keys = append(keys, "FK")
+	}
+	if a.Unique {
+		keys = append(keys, "UK")
+	}
+	return keys
+}
+
+// parentMarks and childMarks are the crow's foot ends of a relationship
+// in Mermaid and PlantUML, written "parent ||--o{ child".
+var (
+	parentMarks = []string{"|o", "||", "}o"}
+	childMarks  = []string{"o|", "||", "o{"}
+)
+
+var (
+	mermaidName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+	mermaidWord = regexp.MustCompile(`[^A-Za-z0-9_()\[\]-]`)
+)
+
+// mermaidEntity returns name as a Mermaid entity name, quoted unless it is
+// a plain word.
+func mermaidEntity(name string) string {
+	if mermaidName.MatchString(name) {
+		return name
+	}
+	return quote.Mermaid(name)
+}
+
+// mermaidAttribute returns s as a Mermaid attribute name or type, which
+// cannot be quoted: spaces and commas become underscores, as in
+// NUMERIC(10_2), and other characters Mermaid does not accept are dropped.
+func mermaidAttribute(s string) string {
+	s = strings.ReplaceAll(s, ", ", ",")
+	s = strings.NewReplacer(" ", "_", ",", "_").Replace(s)
+	s = mermaidWord.ReplaceAllString(s, "")
+	if s == "" || s[0] >= '0' && s[0] <= '9' || s[0] == '-' || s[0] == '(' {
+		s = "_" + s
+	}
+	return s
+}
+
+// WriteMermaid writes the diagram as a Mermaid erDiagram. Columns without a
+// declared type are typed ANY, and NOT NULL columns carry a "NOT NULL"
+// comment.
+func WriteMermaid(w io.Writer, d *Diagram) error {
+	b := bufio.NewWriter(w)
+	b.WriteString("erDiagram\n")
+	for _, entity := range d.Entities {
+		if len(entity.Attributes) == 0 {
+			fmt.Fprintf(b, "    %s\n", mermaidEntity(entity.Name))
+			continue
+		}
+		fmt.Fprintf(b, "    %s {\n", mermaidEntity(entity.Name))
+		for _, attr := range entity.Attributes {
+			typ := attr.Type
+			if typ == "" {
+				typ = "ANY"
+			}
+			fmt.Fprintf(b, "        %s %s", mermaidAttribute(typ), mermaidAttribute(attr.Name))
+			if keys := attr.keys(); len(keys) > 0 {
+				fmt.Fprintf(b, " %s", strings.Join(keys, ", "))
+			}
+			if attr.NotNull {
+				b.WriteString(` "NOT NULL"`)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+	for _, rel := range d.Relationships {
+		fmt.Fprintf(b, "    %s %s--%s %s : %s\n", mermaidEntity(rel.Parent), parentMarks[rel.ParentCardinality],
+			childMarks[rel.ChildCardinality], mermaidEntity(rel.Child), quote.Mermaid(strings.Join(rel.Columns, ", ")))
+	}
+	return b.Flush()
+}
+
+// WritePlantUML writes the diagram as a PlantUML entity diagram in
+// information engineering notation. Entities are aliased e0, e1, ... in
+// the order of the diagram; primary key columns are listed first, above a
+// separator, and NOT NULL columns are marked with a star.
+func WritePlantUML(w io.Writer, d *Diagram) error {
+	b := bufio.NewWriter(w)
+	b.WriteString("@startuml\nhide circle\nskinparam linetype ortho\n")
+	aliases := map[string]string{}
+	for i, entity := range d.Entities {
+		alias := fmt.Sprintf("e%d", i)
+		aliases[strings.ToLower(entity.Name)] = alias
+		// PlantUML has no escape for quotes in a quoted name.
+		fmt.Fprintf(b, "\nentity \"%s\" as %s {\n", strings.ReplaceAll(entity.Name, `"`, "'"), alias)
+		var keys, others []Attribute
+		for _, attr := range entity.Attributes {
+			if attr.PrimaryKey {
+				keys = append(keys, attr)
+			} else {
+				others = append(others, attr)
+			}
+		}
+		for _, attr := range keys {
+			writePlantUMLAttribute(b, attr)
+		}
+		if len(keys) > 0 && len(others) > 0 {
+			b.WriteString("  --\n")
+		}
+		for _, attr := range others {
+			writePlantUMLAttribute(b, attr)
+		}
+		b.WriteString("}\n")
+	}
+	if len(d.Relationships) > 0 {
+		b.WriteString("\n")
+	}
+	for _, rel := range d.Relationships {
+		fmt.Fprintf(b, "%s %s--%s %s : %s\n", aliases[strings.ToLower(rel.Parent)], parentMarks[rel.ParentCardinality],
+			childMarks[rel.ChildCardinality], aliases[strings.ToLower(rel.Child)], strings.Join(rel.Columns, ", "))
+	}
+	b.WriteString("@enduml\n")
+	return b.Flush()
+}
+
+func writePlantUMLAttribute(b *bufio.Writer, attr Attribute) {
+	b.WriteString("  ")
+	if attr.NotNull {
+		b.WriteString("* ")
+	}
+	b.WriteString(attr.Name)
+	if attr.Type != "" {
+		b.WriteString(" : " + attr.Type)
+	}
+	for _, key := range attr.keys() {
+		b.WriteString(" <<" + key + ">>")
+	}
+	b.WriteString("\n")
+}
+
+// dotArrows are the crow's foot arrow shapes of each cardinality.
+var dotArrows = []string{"teeodot", "teetee", "crowodot"}
+
+// WriteDOT writes the diagram in Graphviz's DOT language, each entity as
+// an HTML-like table with a row per column. An edge goes from the first
+// child column of a relationship to the first parent column, or to the
+// entity when the column is not drawn, with crow's foot ends.
+func WriteDOT(w io.Writer, d *Diagram) error {
+	b := bufio.NewWriter(w)
+	b.WriteString("digraph erd {\n\trankdir=LR;\n\tnode [shape=plaintext];\n")
+	ports := map[string]string{}
+	for _, entity := range d.Entities {
+		fmt.Fprintf(b, "\t%s [label=<<table border=\"0\" cellborder=\"1\" cellspacing=\"0\">", quote.DOT(entity.Name))
+		fmt.Fprintf(b, "<tr><td colspan=\"3\" bgcolor=\"lightgrey\"><b>%s</b></td></tr>", html.EscapeString(entity.Name))
+		for i, attr := range entity.Attributes {
+			port := fmt.Sprintf("c%d", i)
+			ports[strings.ToLower(entity.Name)+"\x00"+strings.ToLower(attr.Name)] = port
+			markers := attr.keys()
+			if attr.NotNull {
+				markers = append(markers, "NN")
+			}
+			fmt.Fprintf(b, "<tr><td port=\"%s\" align=\"left\">%s</td><td align=\"left\">%s</td><td align=\"left\">%s</td></tr>",
+				port, html.EscapeString(attr.Name), html.EscapeString(attr.Type), strings.Join(markers, ", "))
+		}
+		b.WriteString("</table>>];\n")
+	}
+	endpoint := func(table string, columns []string) string {
+		s := quote.DOT(table)
+		if len(columns) > 0 {
+			if port, ok := ports[strings.ToLower(table)+"\x00"+strings.ToLower(columns[0])]; ok {
+				s += ":" + port
+			}
+		}
+		return s
+	}
+	for _, rel := range d.Relationships {
+		fmt.Fprintf(b, "\t%s -> %s [dir=both, arrowtail=%s, arrowhead=%s, label=%s];\n",
+			endpoint(rel.Child, rel.Columns), endpoint(rel.Parent, rel.ParentColumns),
+			dotArrows[rel.ChildCardinality], dotArrows[rel.ParentCardinality], quote.DOT(strings.Join(rel.Columns, ", ")))
+	}
+	b.WriteString("}\n")
+	return b.Flush()
+}
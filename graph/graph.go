@@ -0,0 +1,411 @@
+// Package graph builds the dependency graph of a parsed schema: the tables a
+// table references through its foreign keys, the tables and views a view
+// reads, and the tables triggers and indexes are attached to. It orders the
+// objects so that each is created after the objects it depends on, and finds
+// the foreign key cycles that stop rows from being loaded in that order.
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+type NodeKind int
+
+const (
+	NODE_TABLE NodeKind = iota
+	NODE_VIEW
+	NODE_INDEX
+	NODE_TRIGGER
+)
+
+var nodeKindNames = []string{"table", "view", "index", "trigger"}
+
+func (k NodeKind) String() string {
+	if k < 0 || int(k) >= len(nodeKindNames) {
+		return fmt.Sprintf("NodeKind(%d)", int(k))
+	}
+	return nodeKindNames[k]
+}
+
+type EdgeKind int
+
+const (
+	// EDGE_FOREIGNKEY links a table to the parent table of a foreign key.
+	EDGE_FOREIGNKEY EdgeKind = iota
+	// EDGE_SELECT links a view to a table or view its select reads.
+	EDGE_SELECT
+	// EDGE_INDEX links an index to its table.
+	EDGE_INDEX
+	// EDGE_TRIGGER links a trigger to the table or view it fires on.
+	EDGE_TRIGGER
+)
+
+var edgeKindNames = []string{"foreign key", "select", "index", "trigger"}
+
+func (k EdgeKind) String() string {
+	if k < 0 || int(k) >= len(edgeKindNames) {
+		return fmt.Sprintf("EdgeKind(%d)", int(k))
+	}
+	return edgeKindNames[k]
+}
+
+// Node is a table, view, index or trigger of the schema.
+type Node struct {
+	Kind NodeKind
+	Name string
+}
+
+func (n *Node) String() string {
+	return n.Kind.String() + " " + n.Name
+}
+
+// Edge records that From depends on To, which must be created first. For a
+// foreign key, Columns are its child columns and Deferred reports whether it
+// is DEFERRABLE INITIALLY DEFERRED.
+type Edge struct {
+	From     *Node
+	To       *Node
+	Kind     EdgeKind
+	Columns  []string
+	Deferred bool
+}
+
+// Graph is the dependency graph of a schema. Nodes holds the tables, views,
+// indexes and triggers, each kind in schema order, and Edges the
+// dependencies between them in the same order. References to objects that
+// are not in the schema have no edge.
+type Graph struct {
+	Nodes []*Node
+	Edges []*Edge
+
+	names map[string]*Node
+}
+
+// New returns the dependency graph of schema.
+func New(schema *parser.Schema) *Graph {
+	g := &Graph{names: map[string]*Node{}}
+	for _, table := range schema.Tables {
+		g.add(NODE_TABLE, table.Name)
+	}
+	for _, view := range schema.Views {
+		g.add(NODE_VIEW, view.Name)
+	}
+	for _, index := range schema.Indexes {
+		g.add(NODE_INDEX, index.Name)
+	}
+	for _, trigger := range schema.Triggers {
+		g.add(NODE_TRIGGER, trigger.Name)
+	}
+
+	for _, table := range schema.Tables {
+		from := g.Node(NODE_TABLE, table.Name)
+		for _, fk := range table.ForeignKeys() {
+			if to := g.Node(NODE_TABLE, fk.Clause.Table); to != nil {
+				g.Edges = append(g.Edges, &Edge{
+					From:     from,
+					To:       to,
+					Kind:     EDGE_FOREIGNKEY,
+					Columns:  fk.Columns,
+					Deferred: fk.Clause.Deferrable == parser.DEFTYPE_DEFERRABLE_INITIALLY_DEFERRED,
+				})
+			}
+		}
+	}
+	for _, view := range schema.Views {
+		from := g.Node(NODE_VIEW, view.Name)
+		for _, name := range selectSources(view.Select) {
+			g.link(from, name, EDGE_SELECT)
+		}
+	}
+	for _, index := range schema.Indexes {
+		g.link(g.Node(NODE_INDEX, index.Name), index.Table, EDGE_INDEX)
+	}
+	for _, trigger := range schema.Triggers {
+		g.link(g.Node(NODE_TRIGGER, trigger.Name), trigger.Table, EDGE_TRIGGER)
+	}
+	return g
+}
+
+func nodeKey(kind NodeKind, name string) string {
+	return kind.String() + " " + strings.ToLower(name)
+}
+
+func (g *Graph) add(kind NodeKind, name string) {
+	key := nodeKey(kind, name)
+	if g.names[key] != nil {
+		return
+	}
+	node := &Node{Kind: kind, Name: name}
+	g.names[key] = node
+	g.Nodes = append(g.Nodes, node)
+}
+
+// link adds an edge from a view, index or trigger to the table or view
+// named name, once.
+func (g *Graph) link(from *Node, name string, kind EdgeKind) {
+	to := g.Node(NODE_TABLE, name)
+	if to == nil {
+		to = g.Node(NODE_VIEW, name)
+	}
+	if to == nil || to == from {
+		return
+	}
+	for _, edge := range g.Edges {
+		if edge.From == from && edge.To == to && edge.Kind == kind {
+			return
+		}
+	}
+	g.Edges = append(g.Edges, &Edge{From: from, To: to, Kind: kind})
+}
+
+// Node returns the node of the given kind and name, compared
+// case-insensitively like SQLite does, or nil.
+func (g *Graph) Node(kind NodeKind, name string) *Node {
+	return g.names[nodeKey(kind, name)]
+}
+
+// Dependencies returns the edges from node, in the order of Edges.
+func (g *Graph) Dependencies(node *Node) []*Edge {
+	var edges []*Edge
+	for _, edge := range g.Edges {
+		if edge.From == node {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// Dependents returns the edges to node, in the order of Edges.
+func (g *Graph) Dependents(node *Node) []*Edge {
+	var edges []*Edge
+	for _, edge := range g.Edges {
+		if edge.To == node {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+// Order returns every node so that each comes after the nodes it depends
+// on, keeping the order of Nodes where dependencies allow. Within a cycle,
+// deferred foreign keys are the ones left pointing forward, so that the
+// order is also a valid order to insert rows in whenever the cycle's
+// deferred foreign keys break it. A table referencing itself is not a
+// cycle.
+func (g *Graph) Order() []*Node {
+	component := g.components()
+	visited := map[*Node]bool{}
+	var order []*Node
+	var visit func(node *Node)
+	visit = func(node *Node) {
+		visited[node] = true
+		for _, edge := range g.Dependencies(node) {
+			if edge.Deferred && component[edge.From] == component[edge.To] {
+				continue
+			}
+			if !visited[edge.To] {
+				visit(edge.To)
+			}
+		}
+		order = append(order, node)
+	}
+	for _, node := range g.Nodes {
+		if !visited[node] {
+			visit(node)
+		}
+	}
+	return order
+}
+
+// Cycle is a set of tables whose foreign keys reference each other, with
+// the foreign keys between them. Deferred reports whether the cycle's
+// deferred foreign keys break it, so that its rows can be inserted in one
+// transaction in the order of Order. Otherwise loading them needs one of its
+// foreign keys to be made DEFERRABLE INITIALLY DEFERRED, or foreign key
+// enforcement to be turned off with PRAGMA foreign_keys=off.
+type Cycle struct {
+	Tables   []*Node
+	Edges    []*Edge
+	Deferred bool
+}
+
+func (c Cycle) String() string {
+	names := make([]string, len(c.Tables))
+	for i, table := range c.Tables {
+		names[i] = table.Name
+	}
+	message := "foreign key cycle between " + strings.Join(names, ", ")
+	if c.Deferred {
+		return message + ", broken by deferred foreign keys"
+	}
+	return message + ": make one of its foreign keys DEFERRABLE INITIALLY DEFERRED or load it with PRAGMA foreign_keys=off"
+}
+
+// Cycles returns the foreign key cycles of the graph, in the order of their
+// first table in Nodes.
+func (g *Graph) Cycles() []Cycle {
+	component := g.components()
+	members := map[int][]*Node{}
+	var ids []int
+	for _, node := range g.Nodes {
+		id := component[node]
+		if members[id] == nil {
+			ids = append(ids, id)
+		}
+		members[id] = append(members[id], node)
+	}
+
+	var cycles []Cycle
+	for _, id := range ids {
+		if len(members[id]) < 2 {
+			continue
+		}
+		cycle := Cycle{Tables: members[id]}
+		for _, edge := range g.Edges {
+			if component[edge.From] == id && component[edge.To] == id {
+				cycle.Edges = append(cycle.Edges, edge)
+			}
+		}
+		cycle.Deferred = acyclic(cycle.Tables, cycle.Edges)
+		cycles = append(cycles, cycle)
+	}
+	return cycles
+}
+
+// components numbers the strongly connected components of the graph with
+// Tarjan's algorithm. Only foreign keys form cycles in practice, since
+// SQLite rejects a view that reads itself.
+func (g *Graph) components() map[*Node]int {
+	component := map[*Node]int{}
+	index := map[*Node]int{}
+	low := map[*Node]int{}
+	onStack := map[*Node]bool{}
+	var stack []*Node
+	next := 0
+
+	var connect func(node *Node)
+	connect = func(node *Node) {
+		index[node], low[node] = next, next
+		next++
+		stack = append(stack, node)
+		onStack[node] = true
+		for _, edge := range g.Dependencies(node) {
+			if _, seen := index[edge.To]; !seen {
+				connect(edge.To)
+				if low[edge.To] < low[node] {
+					low[node] = low[edge.To]
+				}
+			} else if onStack[edge.To] && index[edge.To] < low[node] {
+				low[node] = index[edge.To]
+			}
+		}
+		if low[node] == index[node] {
+			id := len(component)
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component[top] = id
+				if top == node {
+					break
+				}
+			}
+		}
+	}
+	for _, node := range g.Nodes {
+		if _, seen := index[node]; !seen {
+			connect(node)
+		}
+	}
+	return component
+}
+
+// acyclic reports whether the edges of nodes that are not deferred leave
+// no cycle between distinct nodes.
+func acyclic(nodes []*Node, edges []*Edge) bool {
+	const (
+		unvisited = iota
+		active
+		done
+	)
+	state := map[*Node]int{}
+	var visit func(node *Node) bool
+	visit = func(node *Node) bool {
+		state[node] = active
+		for _, edge := range edges {
+			if edge.From != node || edge.Deferred || edge.To == node {
+				continue
+			}
+			if state[edge.To] == active || state[edge.To] == unvisited && !visit(edge.To) {
+				return false
+			}
+		}
+		state[node] = done
+		return true
+	}
+	for _, node := range nodes {
+		if state[node] == unvisited && !visit(node) {
+			return false
+		}
+	}
+	return true
+}
+
+// selectSources returns the names of the tables and views a select
+// statement reads, from every FROM and JOIN clause including those of its
+// subqueries and common table expressions. It does not tell the names of
+// common table expressions and table-valued functions apart, which New
+// drops unless a table or view has the same name.
+func selectSources(sql string) []string {
+	var tokens []parser.Token
+	for _, token := range parser.Tokenize(sql) {
+		if token.Kind != parser.TOKEN_WHITESPACE && token.Kind != parser.TOKEN_COMMENT {
+			tokens = append(tokens, token)
+		}
+	}
+
+	var names []string
+	// inFrom tells, for each parenthesis depth, whether the tokens are in
+	// a FROM clause, where a comma starts another table.
+	inFrom := []bool{false}
+	expect := false
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		depth := len(inFrom) - 1
+		switch {
+		case token.Text == "(":
+			inFrom = append(inFrom, false)
+			expect = false
+		case token.Text == ")":
+			if depth > 0 {
+				inFrom = inFrom[:depth]
+			}
+			expect = false
+		case token.Text == ",":
+			expect = inFrom[depth]
+		case token.Is("FROM") || token.Is("JOIN"):
+			inFrom[depth], expect = true, true
+		case token.Is("WHERE") || token.Is("GROUP") || token.Is("HAVING") || token.Is("WINDOW") ||
+			token.Is("ORDER") || token.Is("LIMIT") || token.Is("UNION") || token.Is("EXCEPT") ||
+			token.Is("INTERSECT") || token.Is("SELECT") || token.Is("VALUES"):
+			inFrom[depth], expect = false, false
+		case expect && (token.Kind == parser.TOKEN_IDENTIFIER || token.Kind == parser.TOKEN_KEYWORD || token.Kind == parser.TOKEN_STRING):
+			name := token.Value()
+			// A schema-qualified name.
+			if i+2 < len(tokens) && tokens[i+1].Text == "." {
+				i += 2
+				name = tokens[i].Value()
+			}
+			if i+1 >= len(tokens) || tokens[i+1].Text != "(" {
+				names = append(names, name)
+			}
+			expect = false
+		default:
+			expect = false
+		}
+	}
+	return names
+}
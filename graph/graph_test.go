@@ -0,0 +1,168 @@
+package graph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+const schema = `
+CREATE VIEW recent_orders AS
+  SELECT o.id, c.name FROM orders AS o JOIN customers c ON c.id = o.customer_id
+  WHERE o.id IN (SELECT order_id FROM "order_items");
+CREATE TRIGGER order_audit AFTER INSERT ON orders BEGIN
+  INSERT INTO audit VALUES (new.id);
+END;
+CREATE INDEX orders_customer ON orders (customer_id);
+CREATE TABLE order_items (
+  order_id INTEGER REFERENCES orders (id),
+  product_id INTEGER,
+  FOREIGN KEY (product_id) REFERENCES Products
+);
+CREATE TABLE orders (
+  id INTEGER PRIMARY KEY,
+  customer_id INTEGER REFERENCES customers,
+  parent_id INTEGER REFERENCES orders,
+  archive_id INTEGER REFERENCES archive
+);
+CREATE TABLE products (id INTEGER PRIMARY KEY);
+CREATE TABLE customers (id INTEGER PRIMARY KEY);
+`
+
+func newGraph(t *testing.T, sql string) *Graph {
+	s, errCode := parser.ParseSchema(sql)
+	if !assert.Equal(t, parser.ERROR_NONE, errCode) {
+		t.FailNow()
+	}
+	return New(s)
+}
+
+func names(nodes []*Node) []string {
+	var list []string
+	for _, node := range nodes {
+		list = append(list, node.String())
+	}
+	return list
+}
+
+func TestGraph(t *testing.T) {
+	g := newGraph(t, schema)
+	assert.Equal(t, []string{
+		"table order_items", "table orders", "table products", "table customers",
+		"view recent_orders", "index orders_customer", "trigger order_audit",
+	}, names(g.Nodes))
+
+	var edges []string
+	for _, edge := range g.Edges {
+		edges = append(edges, edge.From.Name+" -> "+edge.To.Name+" "+edge.Kind.String())
+	}
+	assert.Equal(t, []string{
+		"order_items -> orders foreign key",
+		"order_items -> products foreign key",
+		"orders -> customers foreign key",
+		"orders -> orders foreign key",
+		"recent_orders -> orders select",
+		"recent_orders -> customers select",
+		"recent_orders -> order_items select",
+		"orders_customer -> orders index",
+		"order_audit -> orders trigger",
+	}, edges)
+	assert.Equal(t, []string{"product_id"}, g.Edges[1].Columns)
+
+	orders := g.Node(NODE_TABLE, "ORDERS")
+	assert.Len(t, g.Dependencies(orders), 2)
+	assert.Len(t, g.Dependents(orders), 5)
+	assert.Nil(t, g.Node(NODE_VIEW, "orders"))
+
+	assert.Equal(t, []string{
+		"table customers", "table orders", "table products", "table order_items",
+		"view recent_orders", "index orders_customer", "trigger order_audit",
+	}, names(g.Order()))
+	assert.Empty(t, g.Cycles())
+}
+
+func TestSelectSources(t *testing.T) {
+	for sql, want := range map[string][]string{
+		"SELECT * FROM a":                                 {"a"},
+		"SELECT * FROM main.a AS x, [b] y, 'c'":           {"a", "b", "c"},
+		"SELECT * FROM a LEFT JOIN b USING (id), c":       {"a", "b", "c"},
+		"SELECT * FROM (SELECT * FROM a) s, b":            {"a", "b"},
+		"SELECT (SELECT max(id) FROM a), f(x, y) FROM b":  {"a", "b"},
+		"SELECT * FROM a WHERE x IN (1, 2) ORDER BY x, y": {"a"},
+		"SELECT * FROM a UNION SELECT * FROM b":           {"a", "b"},
+		"SELECT * FROM json_each(a.x), b":                 {"b"},
+		"WITH w AS (SELECT * FROM a) SELECT * FROM w":     {"a", "w"},
+		"SELECT 1": nil,
+	} {
+		assert.Equal(t, want, selectSources(sql), sql)
+	}
+}
+
+func TestCycles(t *testing.T) {
+	g := newGraph(t, `
+		CREATE TABLE a (id INTEGER PRIMARY KEY, b_id REFERENCES b);
+		CREATE TABLE b (id INTEGER PRIMARY KEY, a_id REFERENCES a);
+		CREATE TABLE c (id INTEGER PRIMARY KEY, d_id REFERENCES d DEFERRABLE INITIALLY DEFERRED);
+		CREATE TABLE d (id INTEGER PRIMARY KEY, c_id REFERENCES c);
+		CREATE TABLE e (id INTEGER PRIMARY KEY, e_id REFERENCES e);
+	`)
+	cycles := g.Cycles()
+	if assert.Len(t, cycles, 2) {
+		assert.Equal(t, []string{"table a", "table b"}, names(cycles[0].Tables))
+		assert.Len(t, cycles[0].Edges, 2)
+		assert.False(t, cycles[0].Deferred)
+		assert.Equal(t, "foreign key cycle between a, b: make one of its foreign keys DEFERRABLE INITIALLY DEFERRED or load it with PRAGMA foreign_keys=off", cycles[0].String())
+
+		assert.Equal(t, []string{"table c", "table d"}, names(cycles[1].Tables))
+		assert.True(t, cycles[1].Deferred)
+		assert.Equal(t, "foreign key cycle between c, d, broken by deferred foreign keys", cycles[1].String())
+	}
+
+	// The deferred foreign key of c is the one left pointing forward.
+	assert.Equal(t, []string{"table b", "table a", "table c", "table d", "table e"}, names(g.Order()))
+}
+
+func TestWriteDOT(t *testing.T) {
+	g := newGraph(t, `
+		CREATE TABLE [a"b] (id INTEGER PRIMARY KEY);
+		CREATE TABLE c (x REFERENCES [a"b] DEFERRABLE INITIALLY DEFERRED, y, FOREIGN KEY (x, y) REFERENCES [a"b]);
+		CREATE VIEW v AS SELECT * FROM c;
+		CREATE INDEX i ON c (x);
+		CREATE TRIGGER t INSTEAD OF DELETE ON v BEGIN SELECT 1; END;
+	`)
+	var b bytes.Buffer
+	assert.NoError(t, WriteDOT(&b, g))
+	assert.Equal(t, `digraph schema {
+	rankdir=LR;
+	"a\"b" [shape=box];
+	"c" [shape=box];
+	"v" [shape=ellipse];
+	"i" [shape=parallelogram];
+	"t" [shape=hexagon];
+	"c" -> "a\"b" [label="x", color=blue];
+	"c" -> "a\"b" [label="x, y"];
+	"v" -> "c" [style=dashed];
+	"i" -> "c" [style=dashed];
+	"t" -> "v" [style=dashed];
+}
+`, b.String())
+
+	b.Reset()
+	assert.NoError(t, WriteMermaid(&b, g))
+	assert.Equal(t, `flowchart LR
+    n0["a#quot;b"]
+    n1["c"]
+    n2(["v"])
+    n3[/"i"/]
+    n4{{"t"}}
+    n1 -->|"x"| n0
+    n1 -->|"x, y"| n0
+    n2 -.-> n1
+    n3 -.-> n1
+    n4 -.-> n2
+    linkStyle 0 stroke:blue
+`, b.String())
+}
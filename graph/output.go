@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/internal/quote"
+)
+
+var dotShapes = []string{"box", "ellipse", "parallelogram", "hexagon"}
+
+// WriteDOT writes the graph in Graphviz's DOT language. Edges point from an
+// object to the object it depends on; foreign keys are solid and labeled
+// with their child columns, deferred ones in blue, other dependencies
+// dashed.
+func WriteDOT(w io.Writer, g *Graph) error {
+	b := bufio.NewWriter(w)
+	b.WriteString("digraph schema {\n\trankdir=LR;\n")
+	for _, node := range g.Nodes {
+		fmt.Fprintf(b, "\t%s [shape=%s];\n", quote.DOT(node.Name), dotShapes[node.Kind])
+	}
+	for _, edge := range g.Edges {
+		var attrs []string
+		if edge.Kind == EDGE_FOREIGNKEY {
+			attrs = append(attrs, "label="+quote.DOT(strings.Join(edge.Columns, ", ")))
+			if edge.Deferred {
+				attrs = append(attrs, "color=blue")
+			}
+		} else {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(b, "\t%s -> %s [%s];\n", quote.DOT(edge.From.Name), quote.DOT(edge.To.Name), strings.Join(attrs, ", "))
+	}
+	b.WriteString("}\n")
+	return b.Flush()
+}
+
+var mermaidShapes = [][2]string{{"[", "]"}, {"([", "])"}, {"[/", "/]"}, {"{{", "}}"}}
+
+// WriteMermaid writes the graph as a Mermaid flowchart, with the same
+// conventions as WriteDOT. Nodes are named n0, n1, ... in the order of
+// Nodes, since Mermaid ids cannot hold every SQL name.
+func WriteMermaid(w io.Writer, g *Graph) error {
+	b := bufio.NewWriter(w)
+	b.WriteString("flowchart LR\n")
+	ids := make(map[*Node]string, len(g.Nodes))
+	for i, node := range g.Nodes {
+		ids[node] = fmt.Sprintf("n%d", i)
+		shape := mermaidShapes[node.Kind]
+		fmt.Fprintf(b, "    %s%s%s%s\n", ids[node], shape[0], quote.Mermaid(node.Name), shape[1])
+	}
+	var deferred []int
+	for i, edge := range g.Edges {
+		switch {
+		case edge.Kind != EDGE_FOREIGNKEY:
+			fmt.Fprintf(b, "    %s -.-> %s\n", ids[edge.From], ids[edge.To])
+		default:
+			fmt.Fprintf(b, "    %s -->|%s| %s\n", ids[edge.From], quote.Mermaid(strings.Join(edge.Columns, ", ")), ids[edge.To])
+			if edge.Deferred {
+				deferred = append(deferred, i)
+			}
+		}
+	}
+	for _, i := range deferred {
+		fmt.Fprintf(b, "    linkStyle %d stroke:blue\n", i)
+	}
+	return b.Flush()
+}
@@ -0,0 +1,17 @@
+// Package quote writes names as the quoted strings of the diagram languages
+// the graph and erd packages output.
+package quote
+
+import "strings"
+
+// DOT returns s as a Graphviz DOT quoted string.
+func DOT(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// Mermaid returns s as a Mermaid quoted string, with quotes written as the
+// #quot; entity.
+func Mermaid(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
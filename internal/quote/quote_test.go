@@ -0,0 +1,14 @@
+package quote
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `"users"`, DOT("users"))
+	assert.Equal(t, `"a \"b\" \\c"`, DOT(`a "b" \c`))
+	assert.Equal(t, `"users"`, Mermaid("users"))
+	assert.Equal(t, `"a #quot;b#quot; \c"`, Mermaid(`a "b" \c`))
+}
@@ -0,0 +1,334 @@
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// Mismatch is one disagreement between the parsed schema and SQLite's own
+// view of it. Object names what was compared, such as "column email",
+// "foreign key (user_id)" or "index users_name", and Field the property
+// that differs; Parsed and SQLite hold the two values.
+type Mismatch struct {
+	Database string
+	Table    string
+	Object   string
+	Field    string
+	Parsed   string
+	SQLite   string
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%s.%s: %s: %s: parsed %q, SQLite %q", m.Database, m.Table, m.Object, m.Field, m.Parsed, m.SQLite)
+}
+
+// Check compares the tables and indexes of schema, as returned by
+// LoadSchema, with PRAGMA table_xinfo, foreign_key_list, index_list and
+// index_xinfo run on the same connection. Objects with an empty Schema are
+// looked up in "main".
+//
+// SQLite parses the MATCH clause of foreign keys but ignores it and reports
+// NONE, so every MATCH clause is reported as a mismatch of field "match".
+func Check(ctx context.Context, db Querier, schema *parser.Schema) ([]Mismatch, error) {
+	var mismatches []Mismatch
+	for _, table := range schema.Tables {
+		c := checker{ctx: ctx, db: db, database: databaseName(table.Schema), table: table.Name}
+		if err := c.columns(table); err != nil {
+			return mismatches, err
+		}
+		if err := c.foreignKeys(table); err != nil {
+			return mismatches, err
+		}
+		for _, index := range schema.Indexes {
+			if databaseName(index.Schema) == c.database && strings.EqualFold(index.Table, table.Name) {
+				if err := c.index(index); err != nil {
+					return mismatches, err
+				}
+			}
+		}
+		mismatches = append(mismatches, c.mismatches...)
+	}
+	return mismatches, nil
+}
+
+func databaseName(schema string) string {
+	if schema == "" {
+		return "main"
+	}
+	return schema
+}
+
+type checker struct {
+	ctx        context.Context
+	db         Querier
+	database   string
+	table      string
+	mismatches []Mismatch
+}
+
+func (c *checker) compare(object, field, parsed, sqlite string) {
+	if parsed != sqlite {
+		c.mismatches = append(c.mismatches, Mismatch{
+			Database: c.database,
+			Table:    c.table,
+			Object:   object,
+			Field:    field,
+			Parsed:   parsed,
+			SQLite:   sqlite,
+		})
+	}
+}
+
+// pragma runs PRAGMA database.name(argument), scans every row into dest and
+// calls row after each.
+func (c *checker) pragma(name, argument string, dest []interface{}, row func()) error {
+	rows, err := c.db.QueryContext(c.ctx, fmt.Sprintf("PRAGMA %s.%s(%s)", quote(c.database), name, quote(argument)))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("introspect: PRAGMA %s(%s): %w", name, argument, err)
+		}
+		row()
+	}
+	return rows.Err()
+}
+
+func (c *checker) columns(table *parser.Table) error {
+	var (
+		cid, pk, hidden int
+		name, typ       string
+		notnull         bool
+		dflt            sql.NullString
+	)
+	primaryKey := table.PrimaryKey()
+	n := 0
+	err := c.pragma("table_xinfo", table.Name, []interface{}{&cid, &name, &typ, &notnull, &dflt, &pk, &hidden}, func() {
+		n++
+		if n > len(table.Columns) {
+			return
+		}
+		column := &table.Columns[n-1]
+		object := "column " + name
+		c.compare(object, "name", column.Name, name)
+		c.compare(object, "type", normalizeType(declaredType(column)), normalizeType(typ))
+
+		parsedPK := 0
+		for i, key := range primaryKey {
+			if strings.EqualFold(key, column.Name) {
+				parsedPK = i + 1
+			}
+		}
+		c.compare(object, "pk", strconv.Itoa(parsedPK), strconv.Itoa(pk))
+		// SQLite adds NOT NULL to the key columns of WITHOUT ROWID tables.
+		parsedNotnull := column.IsNotnull || table.IsWithoutRowid && parsedPK > 0
+		c.compare(object, "notnull", strconv.FormatBool(parsedNotnull), strconv.FormatBool(notnull))
+
+		sqliteDefault := ""
+		if dflt.Valid {
+			sqliteDefault = normalizeDefault(dflt.String)
+		}
+		c.compare(object, "default", normalizeDefault(column.DefaultExpr), sqliteDefault)
+	})
+	if err != nil {
+		return err
+	}
+	c.compare("table", "columns", strconv.Itoa(len(table.Columns)), strconv.Itoa(n))
+	return nil
+}
+
+func declaredType(column *parser.Column) string {
+	if column.Length == "" {
+		return column.Type
+	}
+	return column.Type + "(" + column.Length + ")"
+}
+
+// normalizeType removes the spaces and case differences SQLite keeps in the
+// declared type.
+func normalizeType(s string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s))
+}
+
+// normalizeDefault returns the value of a quoted default and the text of
+// any other expression, without its outer parentheses, so that the parser's
+// DefaultExpr compares with the expression text SQLite reports.
+func normalizeDefault(s string) string {
+	s = strings.TrimSpace(s)
+	for len(s) >= 2 && s[0] == '(' && s[len(s)-1] == ')' {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		quote := s[:1]
+		return strings.ReplaceAll(s[1:len(s)-1], quote+quote, quote)
+	}
+	return s
+}
+
+// foreignKey is a foreign key as reported by SQLite or as parsed, from a
+// column REFERENCES clause or a table constraint.
+type foreignKey struct {
+	table    string
+	from     []string
+	to       []string
+	onUpdate string
+	onDelete string
+	match    string
+	used     bool
+}
+
+func (fk *foreignKey) object() string {
+	return fmt.Sprintf("foreign key (%s)", strings.Join(fk.from, ", "))
+}
+
+func parsedForeignKey(from []string, clause *parser.ForeignKey) *foreignKey {
+	fk := &foreignKey{table: clause.Table, from: from, onUpdate: actionName(clause.OnUpdate), onDelete: actionName(clause.OnDelete), match: "NONE"}
+	if clause.Match != "" {
+		fk.match = strings.ToUpper(clause.Match)
+	}
+	for i := range from {
+		// Without a column list, SQLite reports NULL: the parent key.
+		to := ""
+		if i < len(clause.ColumnName) {
+			to = clause.ColumnName[i]
+		}
+		fk.to = append(fk.to, to)
+	}
+	return fk
+}
+
+func actionName(action parser.FkAction) string {
+	if action == parser.FKACTION_NONE {
+		return parser.FKACTION_NOACTION.String()
+	}
+	return action.String()
+}
+
+func (c *checker) foreignKeys(table *parser.Table) error {
+	var (
+		sqlite                                  []*foreignKey
+		id, seq                                 int
+		parent, from, onUpdate, onDelete, match string
+		to                                      sql.NullString
+	)
+	err := c.pragma("foreign_key_list", table.Name, []interface{}{&id, &seq, &parent, &from, &to, &onUpdate, &onDelete, &match}, func() {
+		if seq == 0 {
+			sqlite = append(sqlite, &foreignKey{table: parent, onUpdate: onUpdate, onDelete: onDelete, match: match})
+		}
+		fk := sqlite[len(sqlite)-1]
+		fk.from = append(fk.from, from)
+		fk.to = append(fk.to, to.String)
+	})
+	if err != nil {
+		return err
+	}
+
+	var parsed []*foreignKey
+	for i := range table.Columns {
+		if clause := table.Columns[i].ForeignKeyClause; clause != nil {
+			parsed = append(parsed, parsedForeignKey([]string{table.Columns[i].Name}, clause))
+		}
+	}
+	for i := range table.Constraints {
+		constraint := &table.Constraints[i]
+		if constraint.Type == parser.TABLECONSTRAINT_FOREIGNKEY && constraint.ForeignKeyClause != nil {
+			parsed = append(parsed, parsedForeignKey(constraint.ForeignKeyName, constraint.ForeignKeyClause))
+		}
+	}
+
+	for _, fk := range sqlite {
+		var found *foreignKey
+		for _, candidate := range parsed {
+			if !candidate.used && strings.EqualFold(candidate.table, fk.table) &&
+				strings.EqualFold(strings.Join(candidate.from, ","), strings.Join(fk.from, ",")) {
+				found = candidate
+				break
+			}
+		}
+		if found == nil {
+			c.compare(fk.object(), "references", "", fk.table)
+			continue
+		}
+		found.used = true
+		c.compare(fk.object(), "to", strings.Join(found.to, ", "), strings.Join(fk.to, ", "))
+		c.compare(fk.object(), "on update", found.onUpdate, fk.onUpdate)
+		c.compare(fk.object(), "on delete", found.onDelete, fk.onDelete)
+		c.compare(fk.object(), "match", found.match, fk.match)
+	}
+	for _, fk := range parsed {
+		if !fk.used {
+			c.compare(fk.object(), "references", fk.table, "")
+		}
+	}
+	return nil
+}
+
+func (c *checker) index(index *parser.Index) error {
+	object := "index " + index.Name
+	var (
+		seq                     int
+		name, origin            string
+		unique, partial, exists bool
+	)
+	err := c.pragma("index_list", c.table, []interface{}{&seq, &name, &unique, &origin, &partial}, func() {
+		if strings.EqualFold(name, index.Name) {
+			exists = true
+			c.compare(object, "unique", strconv.FormatBool(index.IsUnique), strconv.FormatBool(unique))
+			c.compare(object, "partial", strconv.FormatBool(index.Where != ""), strconv.FormatBool(partial))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if !exists {
+		c.compare(object, "exists", "true", "false")
+		return nil
+	}
+
+	var (
+		seqno, cid int
+		column     sql.NullString
+		desc, key  bool
+		collation  sql.NullString
+	)
+	n := 0
+	err = c.pragma("index_xinfo", index.Name, []interface{}{&seqno, &cid, &column, &desc, &collation, &key}, func() {
+		if !key {
+			return
+		}
+		n++
+		if n > len(index.Columns) {
+			return
+		}
+		parsed := &index.Columns[n-1]
+		columnObject := fmt.Sprintf("%s column %d", object, n)
+		// Expressions have cid -2 and no name.
+		if cid != -2 {
+			c.compare(columnObject, "name", parsed.Name, column.String)
+		}
+		c.compare(columnObject, "desc", strconv.FormatBool(parsed.Order == parser.ORDER_DESC), strconv.FormatBool(desc))
+		// SQLite reports the collation in effect, which defaults to the
+		// column's; only an explicit COLLATE is compared.
+		if parsed.CollateName != "" {
+			c.compare(columnObject, "collation", strings.ToUpper(parsed.CollateName), strings.ToUpper(collation.String))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	c.compare(object, "columns", strconv.Itoa(len(index.Columns)), strconv.Itoa(n))
+	return nil
+}
@@ -0,0 +1,65 @@
+package introspect
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+func TestCheck(t *testing.T) {
+	conn := openConn(t,
+		`CREATE TABLE users (
+			id INTEGER PRIMARY KEY,
+			email VARCHAR (100) NOT NULL DEFAULT 'none',
+			name TEXT
+		)`,
+		`CREATE TABLE memberships (
+			user_id INTEGER REFERENCES users ON DELETE CASCADE,
+			group_id INTEGER,
+			PRIMARY KEY (group_id, user_id),
+			FOREIGN KEY (group_id, user_id) REFERENCES memberships (user_id, group_id) ON UPDATE SET NULL
+		) WITHOUT ROWID`,
+		"CREATE UNIQUE INDEX users_email ON users (email COLLATE NOCASE DESC, lower(name)) WHERE name IS NOT NULL",
+	)
+
+	schema, err := LoadSchema(context.Background(), conn)
+	if !assert.NoError(t, err) {
+		return
+	}
+	mismatches, err := Check(context.Background(), conn, schema)
+	assert.NoError(t, err)
+	assert.Empty(t, mismatches)
+
+	// Changes to the parse result show up as mismatches.
+	users := schema.Table("users")
+	users.Columns[1].IsNotnull = false
+	users.Columns[1].DefaultExpr = "nothing"
+	users.Columns = users.Columns[:2]
+	schema.Table("memberships").Columns[0].ForeignKeyClause.OnDelete = parser.FKACTION_RESTRICT
+	schema.Indexes[0].Columns[0].Order = parser.ORDER_NONE
+	schema.Indexes = append(schema.Indexes, &parser.Index{Name: "missing", Table: "users"})
+
+	mismatches, err = Check(context.Background(), conn, schema)
+	assert.NoError(t, err)
+	var report []string
+	for _, mismatch := range mismatches {
+		report = append(report, mismatch.String())
+	}
+	assert.Equal(t, []string{
+		`main.users: column email: notnull: parsed "false", SQLite "true"`,
+		`main.users: column email: default: parsed "nothing", SQLite "none"`,
+		`main.users: table: columns: parsed "2", SQLite "3"`,
+		`main.users: index users_email column 1: desc: parsed "false", SQLite "true"`,
+		`main.users: index missing: exists: parsed "true", SQLite "false"`,
+		`main.memberships: foreign key (user_id): on delete: parsed "RESTRICT", SQLite "CASCADE"`,
+	}, report)
+}
+
+func TestNormalizeDefault(t *testing.T) {
+	assert.Equal(t, "it's", normalizeDefault("'it''s'"))
+	assert.Equal(t, "1 + 2", normalizeDefault("((1 + 2))"))
+	assert.Equal(t, "CURRENT_TIMESTAMP", normalizeDefault("CURRENT_TIMESTAMP"))
+}
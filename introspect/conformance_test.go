@@ -0,0 +1,101 @@
+package introspect
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/conformance.golden")
+
+// TestConformance runs every statement of testdata/conformance.sql through
+// ParseTable and through SQLite, and compares the report of their
+// differences with testdata/conformance.golden. After a parser change, run
+//
+//	go test ./introspect -run Conformance -update
+//
+// and review the new report with git diff.
+func TestConformance(t *testing.T) {
+	report := conformanceReport(t, "testdata/conformance.sql")
+	if *update {
+		if err := os.WriteFile("testdata/conformance.golden", []byte(report), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	golden, err := os.ReadFile("testdata/conformance.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(golden), report, "run with -update to accept the new report")
+}
+
+func conformanceReport(t *testing.T, path string) string {
+	sql, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var body strings.Builder
+	var total, same, different, failed int
+	for _, statement := range parser.SplitStatements(string(sql)) {
+		total++
+		conn := openConn(t, statement.Text)
+
+		lines, errCode := conformance(t, conn, statement.Text)
+		if errCode != parser.ERROR_NONE {
+			failed++
+			lines = []string{"not parsed: " + errCode.String()}
+		} else if len(lines) == 0 {
+			same++
+			continue
+		} else {
+			different++
+		}
+		// Name the statement by its first line that is not a comment.
+		line, text := statement.Line, strings.Split(statement.Text, "\n")
+		for len(text) > 1 && strings.HasPrefix(strings.TrimSpace(text[0]), "--") {
+			line, text = line+1, text[1:]
+		}
+		fmt.Fprintf(&body, "\n%s:%d: %s\n", filepath.Base(path), line, text[0])
+		for _, line := range lines {
+			fmt.Fprintf(&body, "\t%s\n", line)
+		}
+	}
+	return fmt.Sprintf("%d statements: %d parsed as SQLite does, %d with differences, %d not parsed\n%s",
+		total, same, different, failed, body.String())
+}
+
+// conformance parses sql and returns the differences with the table SQLite
+// created from it. A parser panic is reported as a difference.
+func conformance(t *testing.T, conn Querier, sql string) (lines []string, errCode parser.ErrorCode) {
+	defer func() {
+		if r := recover(); r != nil {
+			lines, errCode = []string{fmt.Sprintf("panic: %v", r)}, parser.ERROR_NONE
+		}
+	}()
+
+	table, errCode := parser.ParseTable(sql, 0)
+	if errCode != parser.ERROR_NONE {
+		return nil, errCode
+	}
+	if table.IsTemporary {
+		table.Schema = "temp"
+	}
+	mismatches, err := Check(context.Background(), conn, &parser.Schema{Tables: []*parser.Table{table}})
+	if err != nil {
+		t.Fatalf("%s: %v", sql, err)
+	}
+	for _, mismatch := range mismatches {
+		lines = append(lines, fmt.Sprintf("%s: %s: parsed %q, SQLite %q", mismatch.Object, mismatch.Field, mismatch.Parsed, mismatch.SQLite))
+	}
+	return lines, parser.ERROR_NONE
+}
@@ -0,0 +1,157 @@
+// Package introspect reads the schema of a live SQLite connection through
+// database/sql, and checks the parsed schema against SQLite's own description
+// of the tables given by its PRAGMA statements.
+//
+// The package does not import a driver: any database/sql driver for SQLite
+// works.
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// Querier is satisfied by *sql.DB, *sql.Conn and *sql.Tx. Temporary tables
+// and attached databases belong to a single connection of a *sql.DB pool:
+// pass the *sql.Conn that created them.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Database is a row of PRAGMA database_list.
+type Database struct {
+	Name string
+	// File is the path of the database file, empty for in-memory and
+	// temporary databases.
+	File string
+}
+
+// Databases returns the main, temp and attached databases of the connection.
+func Databases(ctx context.Context, db Querier) ([]Database, error) {
+	rows, err := db.QueryContext(ctx, "PRAGMA database_list")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var databases []Database
+	for rows.Next() {
+		var seq int
+		var database Database
+		var file sql.NullString
+		if err := rows.Scan(&seq, &database.Name, &file); err != nil {
+			return nil, err
+		}
+		database.File = file.String
+		databases = append(databases, database)
+	}
+	return databases, rows.Err()
+}
+
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// LoadSchema parses the SQL text stored in the schema table of every database
+// of the connection, in the order of PRAGMA database_list and, within each
+// database, of its schema table. The Schema field of every table, index, view
+// and trigger is the name of its database ("main", "temp" or the name it was
+// attached under). Internal indexes and virtual tables are skipped. An object
+// that cannot be parsed does not stop the others: the schema holds every
+// object that parsed, and the error is a ParseErrors listing the ones that did
+// not. A failing query stops loading and returns the objects parsed so far.
+func LoadSchema(ctx context.Context, db Querier) (*parser.Schema, error) {
+	databases, err := Databases(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var schema parser.Schema
+	var failures ParseErrors
+	for _, database := range databases {
+		part, partFailures, err := loadDatabase(ctx, db, database.Name)
+		failures = append(failures, partFailures...)
+		for _, table := range part.Tables {
+			table.Schema = database.Name
+		}
+		for _, index := range part.Indexes {
+			index.Schema = database.Name
+		}
+		for _, view := range part.Views {
+			view.Schema = database.Name
+		}
+		for _, trigger := range part.Triggers {
+			trigger.Schema = database.Name
+		}
+		schema.Tables = append(schema.Tables, part.Tables...)
+		schema.Indexes = append(schema.Indexes, part.Indexes...)
+		schema.Views = append(schema.Views, part.Views...)
+		schema.Triggers = append(schema.Triggers, part.Triggers...)
+		if err != nil {
+			return &schema, err
+		}
+	}
+	if failures != nil {
+		return &schema, failures
+	}
+	return &schema, nil
+}
+
+// ParseError reports an object of a schema table whose SQL text cannot be
+// parsed.
+type ParseError struct {
+	Database string
+	Type     string
+	Name     string
+	Code     parser.ErrorCode
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("introspect: cannot parse %s %s.%s: %s error", e.Type, e.Database, e.Name, e.Code)
+}
+
+// ParseErrors lists every object LoadSchema could not parse.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+func loadDatabase(ctx context.Context, db Querier, database string) (*parser.Schema, ParseErrors, error) {
+	var schema parser.Schema
+	var failures ParseErrors
+	// sqlite_master, rather than sqlite_schema, is understood by every
+	// version; in the temp database it is an alias of sqlite_temp_master.
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT type, name, sql FROM %s.sqlite_master WHERE sql IS NOT NULL ORDER BY rowid", quote(database)))
+	if err != nil {
+		return &schema, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var objectType, name, sql string
+		if err := rows.Scan(&objectType, &name, &sql); err != nil {
+			return &schema, failures, err
+		}
+		if objectType == "table" && isVirtual(sql) {
+			continue
+		}
+		if errCode := schema.Add(sql); errCode != parser.ERROR_NONE {
+			failures = append(failures, &ParseError{Database: database, Type: objectType, Name: name, Code: errCode})
+		}
+	}
+	return &schema, failures, rows.Err()
+}
+
+func isVirtual(sql string) bool {
+	fields := strings.Fields(sql)
+	return len(fields) > 1 && strings.EqualFold(fields[1], "VIRTUAL")
+}
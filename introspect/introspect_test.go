@@ -0,0 +1,89 @@
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+// openConn returns a single connection to a new in-memory database, on which
+// the statements have been run.
+func openConn(t *testing.T, statements ...string) *sql.Conn {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	for _, statement := range statements {
+		if _, err := conn.ExecContext(context.Background(), statement); err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+	return conn
+}
+
+func TestLoadSchema(t *testing.T) {
+	conn := openConn(t,
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE)",
+		"CREATE INDEX users_email ON users (email COLLATE NOCASE)",
+		"CREATE VIEW emails AS SELECT email FROM users",
+		"CREATE TRIGGER users_delete AFTER DELETE ON users BEGIN SELECT 1; END",
+		"CREATE TEMP TABLE scratch (x)",
+		"ATTACH ':memory:' AS aux",
+		"CREATE TABLE aux.events (id INTEGER PRIMARY KEY, user_id REFERENCES users (id))",
+	)
+
+	databases, err := Databases(context.Background(), conn)
+	if assert.NoError(t, err) {
+		var names []string
+		for _, database := range databases {
+			names = append(names, database.Name)
+		}
+		assert.Equal(t, []string{"main", "temp", "aux"}, names)
+	}
+
+	schema, err := LoadSchema(context.Background(), conn)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.Len(t, schema.Tables, 3) {
+		assert.Equal(t, "main", schema.Tables[0].Schema)
+		assert.Equal(t, "users", schema.Tables[0].Name)
+		assert.Equal(t, "temp", schema.Tables[1].Schema)
+		assert.Equal(t, "scratch", schema.Tables[1].Name)
+		assert.Equal(t, "aux", schema.Tables[2].Schema)
+		assert.Equal(t, "events", schema.Tables[2].Name)
+	}
+	if assert.Len(t, schema.Indexes, 1) {
+		assert.Equal(t, "main", schema.Indexes[0].Schema)
+	}
+	assert.Len(t, schema.Views, 1)
+	assert.Len(t, schema.Triggers, 1)
+}
+
+func TestLoadSchemaError(t *testing.T) {
+	conn := openConn(t,
+		"CREATE TABLE a (x)",
+		"CREATE TABLE b (x INTEGER DEFAULT (1 + 1))",
+		"CREATE TABLE c (x)",
+		"CREATE TABLE d (x INTEGER DEFAULT -1)",
+	)
+	schema, err := LoadSchema(context.Background(), conn)
+	assert.ErrorContains(t, err, "introspect: cannot parse table main.b")
+	var failures ParseErrors
+	if assert.ErrorAs(t, err, &failures) && assert.Len(t, failures, 2) {
+		assert.Equal(t, "b", failures[0].Name)
+		assert.Equal(t, "d", failures[1].Name)
+		assert.Equal(t, "main", failures[1].Database)
+	}
+	assert.NotNil(t, schema.Table("a"))
+	assert.NotNil(t, schema.Table("c"))
+}
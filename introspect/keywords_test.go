@@ -0,0 +1,65 @@
+package introspect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// TestKeywordNames uses every SQLite keyword as a name in each position of
+// a CREATE TABLE statement, and checks that ParseTable and Validate accept
+// exactly the statements SQLite accepts.
+func TestKeywordNames(t *testing.T) {
+	// Column constraints the parser does not support yet.
+	unsupported := map[string]bool{
+		"CREATE TABLE t (x DEFERRABLE)":       true,
+		"CREATE TABLE t (x INT DEFERRABLE)":   true,
+		"CREATE TABLE t (x INT DEFAULT NULL)": true,
+	}
+
+	conn := openConn(t)
+	for _, template := range []string{
+		"CREATE TABLE t (%[1]s INT)",
+		"CREATE TABLE %[1]s (x INT)",
+		"CREATE TABLE main.%[1]s (x INT)",
+		"CREATE TABLE t (x %[1]s)",
+		"CREATE TABLE t (x INT %[1]s)",
+		"CREATE TABLE t (x INT DEFAULT %[1]s)",
+		"CREATE TABLE t (x INT CONSTRAINT %[1]s NOT NULL)",
+		"CREATE TABLE t (x INT REFERENCES %[1]s (%[1]s))",
+		"CREATE TABLE t (x INT REFERENCES p MATCH %[1]s)",
+		"CREATE TABLE t (%[1]s INT, PRIMARY KEY (%[1]s))",
+		"CREATE TABLE t (%[1]s INT, CONSTRAINT %[1]s UNIQUE (%[1]s))",
+		"CREATE TABLE t (%[1]s INT, FOREIGN KEY (%[1]s) REFERENCES %[1]s)",
+	} {
+		for _, keyword := range parser.Keywords() {
+			statement := fmt.Sprintf(template, keyword)
+			if unsupported[statement] {
+				continue
+			}
+			err := exec(conn, statement)
+			table, errCode := parser.ParseTable(statement, 0)
+			var diags []parser.Diagnostic
+			if errCode == parser.ERROR_NONE {
+				diags = parser.Validate(table)
+			}
+			if (err == nil) != (errCode == parser.ERROR_NONE && len(diags) == 0) {
+				t.Errorf("%s: SQLite: %v, parsed: %v %v", statement, err, errCode, diags)
+			}
+		}
+	}
+}
+
+// exec runs statement in a transaction that it rolls back.
+func exec(conn *sql.Conn, statement string) error {
+	tx, err := conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec(statement)
+	return err
+}
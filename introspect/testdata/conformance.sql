@@ -0,0 +1,81 @@
+-- Corpus of CREATE TABLE statements compared with SQLite by
+-- TestConformance. Each statement runs in a database of its own.
+
+-- Declared types
+CREATE TABLE types (a INT, b INTEGER, c TEXT, d BLOB, e REAL, f NUMERIC, g);
+CREATE TABLE types_multiword (a UNSIGNED BIG INT, b VARYING CHARACTER(255), c DOUBLE PRECISION, d NATIVE CHARACTER(70));
+CREATE TABLE types_length (a VARCHAR(100), b DECIMAL(10, 5), c CHARACTER ( 20 ), d FLOAT(-3));
+CREATE TABLE types_case (a integer, b VarChar(8), c "text");
+CREATE TABLE types_signed (a NUMERIC(-5, +3), b INT(0x10), c REAL(1.5e3, .5));
+CREATE TABLE types_keyword_names (a DATETIME, b BOOLEAN, c DATE, d TIMESTAMP WITH TIME ZONE);
+
+-- Names and quoting
+CREATE TABLE "quoted table" ("first column" TEXT, [second column] TEXT, `third column` TEXT);
+CREATE TABLE 'single' ('x' TEXT);
+CREATE TABLE keywords ("order" INTEGER, "group" TEXT, "select" BLOB);
+CREATE TABLE "embedded ""quote""" ("a""b" TEXT);
+CREATE TABLE unicode_név (prénom TEXT, 名前 TEXT);
+CREATE TABLE IF NOT EXISTS if_not_exists (a);
+CREATE TEMP TABLE temporary_table (a);
+CREATE TABLE main.qualified (a);
+CREATE TABLE /* comment */ comments ( -- trailing
+  a INTEGER, /* inline */ b TEXT
+);
+
+-- NOT NULL and UNIQUE
+CREATE TABLE not_null (a TEXT NOT NULL, b TEXT NOT NULL ON CONFLICT REPLACE, c TEXT NULL);
+CREATE TABLE uniques (a TEXT UNIQUE, b TEXT, c TEXT, UNIQUE (b, c) ON CONFLICT IGNORE);
+CREATE TABLE named_constraints (a TEXT CONSTRAINT a_nn NOT NULL CONSTRAINT a_uq UNIQUE);
+
+-- Defaults
+CREATE TABLE default_string (a TEXT DEFAULT 'x', b TEXT DEFAULT 'it''s', c TEXT DEFAULT '');
+CREATE TABLE default_number (a INTEGER DEFAULT 0, b REAL DEFAULT 1.5, c INTEGER DEFAULT -1, d INTEGER DEFAULT +7, e INTEGER DEFAULT 0x10);
+CREATE TABLE default_null (a TEXT DEFAULT NULL);
+CREATE TABLE default_keyword (a DEFAULT CURRENT_TIMESTAMP, b DEFAULT CURRENT_DATE, c DEFAULT TRUE);
+CREATE TABLE default_expression (a INTEGER DEFAULT (1 + 2), b TEXT DEFAULT (lower('X')));
+CREATE TABLE default_identifier (a TEXT DEFAULT "double quoted");
+CREATE TABLE default_blob (a BLOB DEFAULT x'00ff');
+
+-- Primary keys
+CREATE TABLE pk_column (id INTEGER PRIMARY KEY, name TEXT);
+CREATE TABLE pk_autoincrement (id INTEGER PRIMARY KEY AUTOINCREMENT);
+CREATE TABLE pk_desc (id INTEGER PRIMARY KEY DESC);
+CREATE TABLE pk_conflict (id INTEGER PRIMARY KEY ON CONFLICT ROLLBACK);
+CREATE TABLE pk_text (code TEXT PRIMARY KEY);
+CREATE TABLE pk_table (a INTEGER, b TEXT, PRIMARY KEY (b, a));
+CREATE TABLE pk_table_order (a INTEGER, b TEXT, PRIMARY KEY (a DESC, b ASC));
+CREATE TABLE pk_without_rowid (a TEXT, b INTEGER, c TEXT, PRIMARY KEY (c, a)) WITHOUT ROWID;
+CREATE TABLE pk_without_rowid_column (a TEXT PRIMARY KEY, b TEXT) WITHOUT ROWID;
+CREATE TABLE pk_collate (a TEXT, PRIMARY KEY (a COLLATE NOCASE));
+
+-- Collations
+CREATE TABLE collations (a TEXT COLLATE NOCASE, b TEXT COLLATE RTRIM, c TEXT COLLATE BINARY);
+
+-- Foreign keys
+CREATE TABLE fk_column (a INTEGER REFERENCES parent (id));
+CREATE TABLE fk_column_implicit (a INTEGER REFERENCES parent);
+CREATE TABLE fk_actions (a INTEGER REFERENCES parent (id) ON DELETE CASCADE ON UPDATE SET NULL);
+CREATE TABLE fk_all_actions (
+  a REFERENCES p (x) ON DELETE SET DEFAULT,
+  b REFERENCES p (x) ON DELETE RESTRICT,
+  c REFERENCES p (x) ON DELETE NO ACTION,
+  d REFERENCES p (x) ON UPDATE CASCADE
+);
+CREATE TABLE fk_match (a INTEGER REFERENCES parent (id) MATCH FULL);
+CREATE TABLE fk_deferrable (a INTEGER REFERENCES parent (id) DEFERRABLE INITIALLY DEFERRED);
+CREATE TABLE fk_table (a INTEGER, b INTEGER, FOREIGN KEY (a, b) REFERENCES parent (x, y) ON DELETE CASCADE);
+CREATE TABLE fk_table_implicit (a INTEGER, b INTEGER, FOREIGN KEY (a, b) REFERENCES parent);
+CREATE TABLE fk_several (
+  a INTEGER REFERENCES p1 (id),
+  b INTEGER,
+  CONSTRAINT fk_b FOREIGN KEY (b) REFERENCES p2 (id) ON UPDATE CASCADE,
+  FOREIGN KEY (a) REFERENCES p3 (id)
+);
+CREATE TABLE fk_self (id INTEGER PRIMARY KEY, parent_id INTEGER REFERENCES fk_self (id));
+
+-- Constraints the parser does not model
+CREATE TABLE check_column (a INTEGER CHECK (a > 0));
+CREATE TABLE check_table (a INTEGER, b INTEGER, CHECK (a < b));
+CREATE TABLE generated (a INTEGER, b INTEGER GENERATED ALWAYS AS (a * 2) STORED, c AS (a + 1));
+CREATE TABLE strict_table (a INTEGER, b TEXT) STRICT;
+CREATE TABLE as_select AS SELECT 1 AS x, 'y' AS y;
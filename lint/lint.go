@@ -0,0 +1,226 @@
+// Package lint checks parsed CREATE TABLE statements against schema
+// conventions that SQLite itself does not enforce.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+type Severity int
+
+const (
+	SEVERITY_INFO Severity = iota
+	SEVERITY_WARNING
+	SEVERITY_ERROR
+)
+
+var severityNames = []string{"info", "warning", "error"}
+
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity returns the severity named by s ("info", "warning" or "error").
+func ParseSeverity(s string) (Severity, error) {
+	for i, name := range severityNames {
+		if strings.EqualFold(s, name) {
+			return Severity(i), nil
+		}
+	}
+	return 0, fmt.Errorf("lint: unknown severity %q", s)
+}
+
+// Finding is a single rule violation. Line is the line of the offending
+// statement when the input came from LintSQL, and 0 otherwise.
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Table    string
+	Column   string
+	Message  string
+	Line     int
+}
+
+// Rule is a named check run against every table. Check calls report once
+// per violation; column may be empty for table-level findings. Schema holds
+// the rest of the input, such as the CREATE INDEX statements of the table.
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	Check       func(table *parser.Table, schema *parser.Schema, report func(column, message string))
+}
+
+// Config selects and tunes the rules a Linter runs. When Enable is empty
+// every rule in Rules is run, minus those listed in Disable. Severity
+// overrides the default severity of individual rules.
+type Config struct {
+	Enable   []string
+	Disable  []string
+	Severity map[string]Severity
+}
+
+type Linter struct {
+	rules []Rule
+}
+
+// New returns a Linter running the rules selected by config. Unknown rule
+// names are reported as an error.
+func New(config Config) (*Linter, error) {
+	known := make(map[string]Rule, len(Rules))
+	for _, rule := range Rules {
+		known[rule.Name] = rule
+	}
+	for _, names := range [][]string{config.Enable, config.Disable} {
+		for _, name := range names {
+			if _, ok := known[name]; !ok {
+				return nil, fmt.Errorf("lint: unknown rule %q", name)
+			}
+		}
+	}
+	for name := range config.Severity {
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("lint: unknown rule %q", name)
+		}
+	}
+
+	enabled := make(map[string]bool)
+	for _, name := range config.Enable {
+		enabled[name] = true
+	}
+	for _, name := range config.Disable {
+		enabled[name] = false
+	}
+
+	var linter Linter
+	for _, rule := range Rules {
+		on, listed := enabled[rule.Name]
+		if (len(config.Enable) > 0 && !on) || (listed && !on) {
+			continue
+		}
+		if severity, ok := config.Severity[rule.Name]; ok {
+			rule.Severity = severity
+		}
+		linter.rules = append(linter.rules, rule)
+	}
+	return &linter, nil
+}
+
+// Lint runs the enabled rules against each table.
+func (l *Linter) Lint(tables ...*parser.Table) []Finding {
+	return l.LintSchema(&parser.Schema{Tables: tables})
+}
+
+// LintSchema runs the enabled rules against each table of schema.
+func (l *Linter) LintSchema(schema *parser.Schema) []Finding {
+	var findings []Finding
+	for _, table := range schema.Tables {
+		findings = append(findings, l.lintTable(table, schema, nil, 0)...)
+	}
+	return findings
+}
+
+// LintSQL parses every CREATE TABLE statement in sql and lints it. A
+// "-- lint:ignore rule[, rule...]" comment inside a statement suppresses
+// the listed rules for that table; without a list it suppresses all rules.
+func (l *Linter) LintSQL(sql string) ([]Finding, error) {
+	// Parse every statement first, so that rules see the indexes created
+	// after a table.
+	statements := parser.SplitStatements(sql)
+	parsed := make([]*parser.Schema, len(statements))
+	var all parser.Schema
+	for i, statement := range statements {
+		schema, errCode := parser.ParseSchema(statement.Text)
+		if errCode != parser.ERROR_NONE {
+			return nil, fmt.Errorf("lint: line %d: cannot parse statement: %s error", statement.Line, errCode)
+		}
+		parsed[i] = schema
+		all.Tables = append(all.Tables, schema.Tables...)
+		all.Indexes = append(all.Indexes, schema.Indexes...)
+	}
+
+	var findings []Finding
+	for i, statement := range statements {
+		for _, table := range parsed[i].Tables {
+			ignored := suppressions(statement.Text)
+			findings = append(findings, l.lintTable(table, &all, ignored, statement.Line)...)
+		}
+	}
+	return findings, nil
+}
+
+func (l *Linter) lintTable(table *parser.Table, schema *parser.Schema, ignored map[string]bool, line int) []Finding {
+	var findings []Finding
+	if ignored["*"] {
+		return nil
+	}
+	for _, rule := range l.rules {
+		if ignored[rule.Name] {
+			continue
+		}
+		rule.Check(table, schema, func(column, message string) {
+			findings = append(findings, Finding{
+				Rule:     rule.Name,
+				Severity: rule.Severity,
+				Table:    table.Name,
+				Column:   column,
+				Message:  message,
+				Line:     line,
+			})
+		})
+	}
+	return findings
+}
+
+const suppressionMarker = "lint:ignore"
+
+func suppressions(sql string) map[string]bool {
+	ignored := make(map[string]bool)
+	for _, token := range parser.Tokenize(sql) {
+		if token.Kind != parser.TOKEN_COMMENT || !strings.HasPrefix(token.Text, "--") {
+			continue
+		}
+		comment := strings.TrimSpace(token.Text[2:])
+		if !strings.HasPrefix(comment, suppressionMarker) {
+			continue
+		}
+		names := strings.TrimSpace(comment[len(suppressionMarker):])
+		if names == "" {
+			ignored["*"] = true
+			continue
+		}
+		for _, name := range strings.Split(names, ",") {
+			ignored[strings.TrimSpace(name)] = true
+		}
+	}
+	return ignored
+}
+
+// MaxSeverity returns the highest severity among findings, or -1 when there
+// are none.
+func MaxSeverity(findings []Finding) Severity {
+	max := Severity(-1)
+	for _, finding := range findings {
+		if finding.Severity > max {
+			max = finding.Severity
+		}
+	}
+	return max
+}
+
+// RuleNames returns the names of every known rule, sorted.
+func RuleNames() []string {
+	names := make([]string, 0, len(Rules))
+	for _, rule := range Rules {
+		names = append(names, rule.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,156 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const schema = `
+CREATE TABLE groups (
+ id INTEGER PRIMARY KEY AUTOINCREMENT,
+ name TEXT
+);
+
+CREATE TABLE contactGroups (
+ contact_id INTEGER,
+ group_id INTEGER,
+ FOREIGN KEY (group_id) REFERENCES groups (id)
+);
+`
+
+func rules(findings []Finding) []string {
+	var names []string
+	for _, finding := range findings {
+		names = append(names, finding.Rule)
+	}
+	return names
+}
+
+func TestLintSQL(t *testing.T) {
+	linter, err := New(Config{})
+	assert.NoError(t, err)
+
+	findings, err := linter.LintSQL(schema)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"no-autoincrement",
+		"no-keyword-identifiers",
+		"require-primary-key",
+		"index-foreign-keys",
+		"foreign-key-on-delete",
+		"snake-case-names",
+	}, rules(findings))
+	assert.Equal(t, 2, findings[0].Line)
+	assert.Equal(t, 7, findings[2].Line)
+}
+
+func TestLintConfig(t *testing.T) {
+	linter, err := New(Config{
+		Enable:   []string{"require-primary-key", "no-autoincrement"},
+		Disable:  []string{"no-autoincrement"},
+		Severity: map[string]Severity{"require-primary-key": SEVERITY_WARNING},
+	})
+	assert.NoError(t, err)
+
+	findings, err := linter.LintSQL(schema)
+	assert.NoError(t, err)
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, "require-primary-key", findings[0].Rule)
+		assert.Equal(t, SEVERITY_WARNING, findings[0].Severity)
+	}
+
+	_, err = New(Config{Disable: []string{"no-such-rule"}})
+	assert.Error(t, err)
+}
+
+func TestLintSuppression(t *testing.T) {
+	linter, err := New(Config{})
+	assert.NoError(t, err)
+
+	findings, err := linter.LintSQL(`
+	CREATE TABLE tags (
+	 id INTEGER PRIMARY KEY AUTOINCREMENT -- lint:ignore no-autoincrement
+	);
+	CREATE TABLE Legacy (a) -- lint:ignore
+	;`)
+	assert.NoError(t, err)
+	assert.Empty(t, findings)
+
+	// Only comments suppress rules, not strings that look like them.
+	findings, err = linter.LintSQL(`CREATE TABLE notes (body TEXT DEFAULT '-- lint:ignore require-primary-key, x');`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"require-primary-key"}, rules(findings))
+}
+
+func TestLintForeignKeyIndexes(t *testing.T) {
+	linter, err := New(Config{Enable: []string{"index-foreign-keys"}})
+	assert.NoError(t, err)
+
+	findings, err := linter.LintSQL(`
+	CREATE TABLE orders (
+	 id INTEGER PRIMARY KEY,
+	 user_id INTEGER REFERENCES users,
+	 shop_id INTEGER REFERENCES shops,
+	 item_id INTEGER REFERENCES items,
+	 cart_id INTEGER REFERENCES carts
+	);
+	CREATE INDEX orders_user ON orders (user_id, id);
+	CREATE INDEX orders_shop ON orders (id, shop_id);
+	CREATE INDEX orders_item ON orders (item_id) WHERE item_id > 0;
+	CREATE INDEX orders_cart ON Orders (abs(cart_id));`)
+	assert.NoError(t, err)
+	var messages []string
+	for _, finding := range findings {
+		messages = append(messages, finding.Message)
+	}
+	assert.Equal(t, []string{
+		"foreign key (shop_id) is not covered by an index",
+		"foreign key (item_id) is not covered by an index",
+		"foreign key (cart_id) is not covered by an index",
+	}, messages)
+}
+
+func TestLintColumnConstraints(t *testing.T) {
+	linter, err := New(Config{Enable: []string{"index-foreign-keys", "foreign-key-on-delete", "snake-case-names", "no-keyword-identifiers"}})
+	assert.NoError(t, err)
+
+	findings, err := linter.LintSQL(`
+	CREATE TABLE posts (
+	 id INTEGER PRIMARY KEY,
+	 author_id INTEGER CONSTRAINT AuthorKey REFERENCES users (id) ON DELETE CASCADE CONSTRAINT "order" REFERENCES people (id)
+	);`)
+	assert.NoError(t, err)
+	var messages []string
+	for _, finding := range findings {
+		messages = append(messages, finding.Message)
+	}
+	assert.ElementsMatch(t, []string{
+		"foreign key (author_id) is not covered by an index",
+		"foreign key on author_id to people does not declare ON DELETE",
+		`name "AuthorKey" is not snake_case`,
+		`name "order" is an SQLite keyword`,
+	}, messages)
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	findings := []Finding{{Rule: "require-primary-key", Severity: SEVERITY_ERROR, Table: "t", Message: "table t has no primary key", Line: 3}}
+	assert.NoError(t, WriteSARIF(&buf, "schema.sql", findings))
+
+	var log struct {
+		Version string
+		Runs    []struct {
+			Results []struct {
+				RuleID string
+				Level  string
+			}
+		}
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Equal(t, "error", log.Runs[0].Results[0].Level)
+	assert.Equal(t, "require-primary-key", log.Runs[0].Results[0].RuleID)
+}
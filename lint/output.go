@@ -0,0 +1,126 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// WriteText writes one line per finding in the form
+// "file:line: severity: message [rule]".
+func WriteText(w io.Writer, file string, findings []Finding) error {
+	for _, finding := range findings {
+		_, err := fmt.Fprintf(w, "%s:%d: %s: %s [%s]\n", file, finding.Line, finding.Severity, finding.Message, finding.Rule)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonFinding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Table    string `json:"table"`
+	Column   string `json:"column,omitempty"`
+	Message  string `json:"message"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// WriteJSON writes the findings as a JSON array.
+func WriteJSON(w io.Writer, file string, findings []Finding) error {
+	list := make([]jsonFinding, 0, len(findings))
+	for _, finding := range findings {
+		list = append(list, jsonFinding{
+			Rule:     finding.Rule,
+			Severity: finding.Severity.String(),
+			Table:    finding.Table,
+			Column:   finding.Column,
+			Message:  finding.Message,
+			File:     file,
+			Line:     finding.Line,
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(list)
+}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log, the format consumed
+// by GitHub code scanning and most CI annotation tools.
+func WriteSARIF(w io.Writer, file string, findings []Finding) error {
+	type message struct {
+		Text string `json:"text"`
+	}
+	type rule struct {
+		ID               string  `json:"id"`
+		ShortDescription message `json:"shortDescription"`
+	}
+	type region struct {
+		StartLine int `json:"startLine"`
+	}
+	type artifactLocation struct {
+		URI string `json:"uri"`
+	}
+	type physicalLocation struct {
+		ArtifactLocation artifactLocation `json:"artifactLocation"`
+		Region           *region          `json:"region,omitempty"`
+	}
+	type location struct {
+		PhysicalLocation physicalLocation `json:"physicalLocation"`
+	}
+	type result struct {
+		RuleID    string     `json:"ruleId"`
+		Level     string     `json:"level"`
+		Message   message    `json:"message"`
+		Locations []location `json:"locations"`
+	}
+
+	var rules []rule
+	for _, r := range Rules {
+		rules = append(rules, rule{ID: r.Name, ShortDescription: message{r.Description}})
+	}
+	results := make([]result, 0, len(findings))
+	for _, finding := range findings {
+		loc := location{PhysicalLocation: physicalLocation{ArtifactLocation: artifactLocation{URI: file}}}
+		if finding.Line > 0 {
+			loc.PhysicalLocation.Region = &region{StartLine: finding.Line}
+		}
+		results = append(results, result{
+			RuleID:    finding.Rule,
+			Level:     sarifLevel(finding.Severity),
+			Message:   message{finding.Message},
+			Locations: []location{loc},
+		})
+	}
+
+	log := map[string]interface{}{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []interface{}{
+			map[string]interface{}{
+				"tool": map[string]interface{}{
+					"driver": map[string]interface{}{
+						"name":  "sqlite-ddl-lint",
+						"rules": rules,
+					},
+				},
+				"results": results,
+			},
+		},
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SEVERITY_ERROR:
+		return "error"
+	case SEVERITY_WARNING:
+		return "warning"
+	}
+	return "note"
+}
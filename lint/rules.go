@@ -0,0 +1,204 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Allam76/Sqlite3CreateTableParser/parser"
+)
+
+// Rules lists every rule known to the linter, in the order they run.
+var Rules = []Rule{
+	{
+		Name:        "require-primary-key",
+		Description: "Every table declares a primary key.",
+		Severity:    SEVERITY_ERROR,
+		Check:       checkPrimaryKey,
+	},
+	{
+		Name:        "index-foreign-keys",
+		Description: "Foreign key columns are the leading columns of a PRIMARY KEY or UNIQUE constraint or an index.",
+		Severity:    SEVERITY_WARNING,
+		Check:       checkForeignKeyIndexes,
+	},
+	{
+		Name:        "text-primary-key-without-rowid",
+		Description: "Tables with a non-INTEGER primary key are declared WITHOUT ROWID.",
+		Severity:    SEVERITY_WARNING,
+		Check:       checkTextPrimaryKey,
+	},
+	{
+		Name:        "no-autoincrement",
+		Description: "AUTOINCREMENT is not used; INTEGER PRIMARY KEY already allocates rowids.",
+		Severity:    SEVERITY_WARNING,
+		Check:       checkAutoincrement,
+	},
+	{
+		Name:        "foreign-key-on-delete",
+		Description: "Every foreign key declares an ON DELETE action.",
+		Severity:    SEVERITY_WARNING,
+		Check:       checkOnDelete,
+	},
+	{
+		Name:        "snake-case-names",
+		Description: "Table, column and constraint names are snake_case.",
+		Severity:    SEVERITY_INFO,
+		Check:       checkSnakeCase,
+	},
+	{
+		Name:        "no-keyword-identifiers",
+		Description: "SQLite keywords are not used as table, column or constraint names.",
+		Severity:    SEVERITY_WARNING,
+		Check:       checkKeywords,
+	},
+}
+
+func checkPrimaryKey(table *parser.Table, schema *parser.Schema, report func(column, message string)) {
+	if table.PrimaryKey() == nil {
+		report("", fmt.Sprintf("table %s has no primary key", table.Name))
+	}
+}
+
+func hasPrefix(index, columns []string) bool {
+	if len(index) < len(columns) {
+		return false
+	}
+	for i, name := range columns {
+		if !strings.EqualFold(index[i], name) {
+			return false
+		}
+	}
+	return true
+}
+
+func checkForeignKeyIndexes(table *parser.Table, schema *parser.Schema, report func(column, message string)) {
+	indexes := [][]string{table.PrimaryKey()}
+	for _, column := range table.Columns {
+		if column.IsUnique {
+			indexes = append(indexes, []string{column.Name})
+		}
+	}
+	for _, constraint := range table.Constraints {
+		if constraint.Type == parser.TABLECONSTRAINT_UNIQUE {
+			var names []string
+			for _, column := range constraint.IndexedColumns {
+				names = append(names, column.Name)
+			}
+			indexes = append(indexes, names)
+		}
+	}
+	// A partial index or one on expressions does not serve the lookups
+	// SQLite makes when a parent row changes.
+	for _, index := range schema.Indexes {
+		if !strings.EqualFold(index.Table, table.Name) || index.Where != "" {
+			continue
+		}
+		var names []string
+		for _, column := range index.Columns {
+			if column.Expr != nil {
+				break
+			}
+			names = append(names, column.Name)
+		}
+		indexes = append(indexes, names)
+	}
+
+	check := func(columns []string) {
+		for _, index := range indexes {
+			if hasPrefix(index, columns) {
+				return
+			}
+		}
+		report(columns[0], fmt.Sprintf("foreign key (%s) is not covered by an index", strings.Join(columns, ", ")))
+	}
+	// A column with several REFERENCES clauses needs a single index.
+	checked := make(map[string]bool)
+	for _, fk := range table.ForeignKeys() {
+		key := strings.ToLower(strings.Join(fk.Columns, ","))
+		if len(fk.Columns) > 0 && !checked[key] {
+			checked[key] = true
+			check(fk.Columns)
+		}
+	}
+}
+
+func checkTextPrimaryKey(table *parser.Table, schema *parser.Schema, report func(column, message string)) {
+	if table.IsWithoutRowid {
+		return
+	}
+	columns := table.PrimaryKey()
+	if len(columns) != 1 {
+		return
+	}
+	for _, column := range table.Columns {
+		if strings.EqualFold(column.Name, columns[0]) && !strings.EqualFold(column.Type, "INTEGER") {
+			report(column.Name, fmt.Sprintf("primary key %s is not an INTEGER; declare the table WITHOUT ROWID", column.Name))
+		}
+	}
+}
+
+func checkAutoincrement(table *parser.Table, schema *parser.Schema, report func(column, message string)) {
+	for _, column := range table.Columns {
+		if column.IsAutoincrement {
+			report(column.Name, fmt.Sprintf("column %s uses AUTOINCREMENT", column.Name))
+		}
+	}
+}
+
+func checkOnDelete(table *parser.Table, schema *parser.Schema, report func(column, message string)) {
+	for i := range table.Columns {
+		column := &table.Columns[i]
+		for _, constraint := range column.ConstraintList() {
+			fk := constraint.ForeignKeyClause
+			if constraint.Type == parser.COLUMNCONSTRAINT_FOREIGNKEY && fk != nil && fk.OnDelete == parser.FKACTION_NONE {
+				report(column.Name, fmt.Sprintf("foreign key on %s to %s does not declare ON DELETE", column.Name, fk.Table))
+			}
+		}
+	}
+	for _, constraint := range table.Constraints {
+		fk := constraint.ForeignKeyClause
+		if constraint.Type == parser.TABLECONSTRAINT_FOREIGNKEY && fk != nil && fk.OnDelete == parser.FKACTION_NONE {
+			report("", fmt.Sprintf("foreign key (%s) does not declare ON DELETE", strings.Join(constraint.ForeignKeyName, ", ")))
+		}
+	}
+}
+
+var snakeCase = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// names returns every identifier declared by table, paired with the column
+// it belongs to (empty for table-level names).
+func names(table *parser.Table) [][2]string {
+	list := [][2]string{{"", table.Name}}
+	for i := range table.Columns {
+		column := &table.Columns[i]
+		list = append(list, [2]string{column.Name, column.Name})
+		for _, constraint := range column.ConstraintList() {
+			if constraint.Name != "" {
+				list = append(list, [2]string{column.Name, constraint.Name})
+			}
+		}
+	}
+	for _, constraint := range table.Constraints {
+		if constraint.Name != "" {
+			list = append(list, [2]string{"", constraint.Name})
+		}
+	}
+	return list
+}
+
+func checkSnakeCase(table *parser.Table, schema *parser.Schema, report func(column, message string)) {
+	for _, name := range names(table) {
+		if !snakeCase.MatchString(name[1]) {
+			report(name[0], fmt.Sprintf("name %q is not snake_case", name[1]))
+		}
+	}
+}
+
+func checkKeywords(table *parser.Table, schema *parser.Schema, report func(column, message string)) {
+	for _, name := range names(table) {
+		if parser.IsKeyword(name[1]) {
+			report(name[0], fmt.Sprintf("name %q is an SQLite keyword", name[1]))
+		}
+	}
+}
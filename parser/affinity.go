@@ -0,0 +1,56 @@
+package parser
+
+import "strings"
+
+type Affinity int
+
+const (
+	AFFINITY_BLOB Affinity = iota
+	AFFINITY_TEXT
+	AFFINITY_NUMERIC
+	AFFINITY_INTEGER
+	AFFINITY_REAL
+)
+
+var affinityNames = enumNames{
+	typeName: "Affinity",
+	strings:  []string{"BLOB", "TEXT", "NUMERIC", "INTEGER", "REAL"},
+	text:     []string{"blob", "text", "numeric", "integer", "real"},
+}
+
+func (a Affinity) String() string {
+	return affinityNames.string(int(a))
+}
+
+func (a Affinity) MarshalText() ([]byte, error) {
+	return affinityNames.marshalText(int(a))
+}
+
+func (a *Affinity) UnmarshalText(text []byte) error {
+	value, err := affinityNames.unmarshalText(text)
+	*a = Affinity(value)
+	return err
+}
+
+// TypeAffinity returns the affinity SQLite gives a column declared with
+// typeName, following the rules of section 3.1 of
+// https://www.sqlite.org/datatype3.html in order.
+func TypeAffinity(typeName string) Affinity {
+	declared := strings.ToUpper(typeName)
+	switch {
+	case strings.Contains(declared, "INT"):
+		return AFFINITY_INTEGER
+	case strings.Contains(declared, "CHAR"), strings.Contains(declared, "CLOB"), strings.Contains(declared, "TEXT"):
+		return AFFINITY_TEXT
+	case declared == "", strings.Contains(declared, "BLOB"):
+		return AFFINITY_BLOB
+	case strings.Contains(declared, "REAL"), strings.Contains(declared, "FLOA"), strings.Contains(declared, "DOUB"):
+		return AFFINITY_REAL
+	}
+	return AFFINITY_NUMERIC
+}
+
+// Affinity returns the type affinity of the column.
+func (c *Column) Affinity() Affinity {
+	return TypeAffinity(c.Type)
+}
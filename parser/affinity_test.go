@@ -0,0 +1,22 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeAffinity(t *testing.T) {
+	// Examples from section 3.1.1 of https://www.sqlite.org/datatype3.html.
+	tests := map[string]Affinity{
+		"INT": AFFINITY_INTEGER, "TINYINT": AFFINITY_INTEGER, "UNSIGNED BIG INT": AFFINITY_INTEGER,
+		"CHARACTER": AFFINITY_TEXT, "VARYING CHARACTER": AFFINITY_TEXT, "NCHAR": AFFINITY_TEXT, "CLOB": AFFINITY_TEXT,
+		"BLOB": AFFINITY_BLOB, "": AFFINITY_BLOB,
+		"REAL": AFFINITY_REAL, "DOUBLE PRECISION": AFFINITY_REAL, "FLOAT": AFFINITY_REAL,
+		"NUMERIC": AFFINITY_NUMERIC, "DECIMAL": AFFINITY_NUMERIC, "BOOLEAN": AFFINITY_NUMERIC, "DATETIME": AFFINITY_NUMERIC,
+		"FLOATING POINT": AFFINITY_INTEGER, "STRING": AFFINITY_NUMERIC,
+	}
+	for typeName, affinity := range tests {
+		assert.Equal(t, affinity, TypeAffinity(typeName), typeName)
+	}
+}
@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ChangeKind int
+
+const (
+	CHANGE_TABLE_ADDED ChangeKind = iota
+	CHANGE_TABLE_REMOVED
+	CHANGE_TABLE_MODIFIED
+	CHANGE_COLUMN_ADDED
+	CHANGE_COLUMN_REMOVED
+	CHANGE_COLUMN_MODIFIED
+	CHANGE_CONSTRAINT_ADDED
+	CHANGE_CONSTRAINT_REMOVED
+)
+
+// Change is one difference between two schemas. Old and New hold the SQL of
+// the affected table, column or constraint before and after the change.
+type Change struct {
+	Kind   ChangeKind
+	Table  string
+	Column string
+	Old    string
+	New    string
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case CHANGE_TABLE_ADDED:
+		return fmt.Sprintf("+ table %s", c.Table)
+	case CHANGE_TABLE_REMOVED:
+		return fmt.Sprintf("- table %s", c.Table)
+	case CHANGE_TABLE_MODIFIED:
+		return fmt.Sprintf("~ table %s: %s -> %s", c.Table, c.Old, c.New)
+	case CHANGE_COLUMN_ADDED:
+		return fmt.Sprintf("+ column %s.%s: %s", c.Table, c.Column, c.New)
+	case CHANGE_COLUMN_REMOVED:
+		return fmt.Sprintf("- column %s.%s: %s", c.Table, c.Column, c.Old)
+	case CHANGE_COLUMN_MODIFIED:
+		return fmt.Sprintf("~ column %s.%s: %s -> %s", c.Table, c.Column, c.Old, c.New)
+	case CHANGE_CONSTRAINT_ADDED:
+		return fmt.Sprintf("+ constraint on %s: %s", c.Table, c.New)
+	case CHANGE_CONSTRAINT_REMOVED:
+		return fmt.Sprintf("- constraint on %s: %s", c.Table, c.Old)
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(c.Kind))
+}
+
+func tableOptions(table *Table) string {
+	var options []string
+	if table.IsTemporary {
+		options = append(options, "TEMP")
+	}
+	if table.IsWithoutRowid {
+		options = append(options, "WITHOUT ROWID")
+	}
+	if table.IsStrict {
+		options = append(options, "STRICT")
+	}
+	return strings.Join(options, " ")
+}
+
+// Diff lists the changes turning the tables of from into those of to.
+// Tables and columns are matched by name, case-insensitively; constraints
+// are matched by their SQL text.
+func Diff(from, to *Schema) []Change {
+	var changes []Change
+
+	for _, old := range from.Tables {
+		if to.Table(old.Name) == nil {
+			changes = append(changes, Change{Kind: CHANGE_TABLE_REMOVED, Table: old.Name, Old: Format(old)})
+		}
+	}
+	for _, table := range to.Tables {
+		old := from.Table(table.Name)
+		if old == nil {
+			changes = append(changes, Change{Kind: CHANGE_TABLE_ADDED, Table: table.Name, New: Format(table)})
+			continue
+		}
+		changes = append(changes, diffTable(old, table)...)
+	}
+
+	return changes
+}
+
+func diffTable(from, to *Table) []Change {
+	var changes []Change
+
+	if tableOptions(from) != tableOptions(to) {
+		changes = append(changes, Change{Kind: CHANGE_TABLE_MODIFIED, Table: to.Name, Old: tableOptions(from), New: tableOptions(to)})
+	}
+
+	for i := range from.Columns {
+		old := &from.Columns[i]
+		if to.Column(old.Name) == nil {
+			changes = append(changes, Change{Kind: CHANGE_COLUMN_REMOVED, Table: to.Name, Column: old.Name, Old: FormatColumn(old)})
+		}
+	}
+	for i := range to.Columns {
+		column := &to.Columns[i]
+		old := from.Column(column.Name)
+		if old == nil {
+			changes = append(changes, Change{Kind: CHANGE_COLUMN_ADDED, Table: to.Name, Column: column.Name, New: FormatColumn(column)})
+			continue
+		}
+		// Compare with the new name so a change in case alone is not reported.
+		renamed := *old
+		renamed.Name = column.Name
+		if FormatColumn(&renamed) != FormatColumn(column) {
+			changes = append(changes, Change{Kind: CHANGE_COLUMN_MODIFIED, Table: to.Name, Column: column.Name, Old: FormatColumn(old), New: FormatColumn(column)})
+		}
+	}
+
+	oldConstraints := make(map[string]bool)
+	for i := range from.Constraints {
+		oldConstraints[FormatTableConstraint(&from.Constraints[i])] = true
+	}
+	newConstraints := make(map[string]bool)
+	for i := range to.Constraints {
+		newConstraints[FormatTableConstraint(&to.Constraints[i])] = true
+	}
+	for i := range from.Constraints {
+		if sql := FormatTableConstraint(&from.Constraints[i]); !newConstraints[sql] {
+			changes = append(changes, Change{Kind: CHANGE_CONSTRAINT_REMOVED, Table: to.Name, Old: sql})
+		}
+	}
+	for i := range to.Constraints {
+		if sql := FormatTableConstraint(&to.Constraints[i]); !oldConstraints[sql] {
+			changes = append(changes, Change{Kind: CHANGE_CONSTRAINT_ADDED, Table: to.Name, New: sql})
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,31 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	from, _ := ParseSchema(`
+	CREATE TABLE a (id INTEGER PRIMARY KEY, name TEXT, gone INT);
+	CREATE TABLE b (x);
+	`)
+	to, _ := ParseSchema(`
+	CREATE TABLE A (ID INTEGER PRIMARY KEY, name TEXT NOT NULL, added INT, UNIQUE (name));
+	CREATE TABLE c (y);
+	`)
+
+	var changes []string
+	for _, change := range Diff(from, to) {
+		changes = append(changes, change.String())
+	}
+	assert.Equal(t, []string{
+		"- table b",
+		"- column A.gone: gone INT",
+		"~ column A.name: name TEXT -> name TEXT NOT NULL",
+		"+ column A.added: added INT",
+		"+ constraint on A: UNIQUE (name)",
+		"+ table c",
+	}, changes)
+}
@@ -0,0 +1,295 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Every enum has three textual forms:
+//
+//   - String returns the SQL keywords naming the value ("SET NULL"), for
+//     logs and messages.
+//   - MarshalText returns the stable lower-case name used by the JSON and
+//     YAML encodings ("set_null"), documented in schema/table.schema.json.
+//     New values may be added, existing names never change.
+//   - SQL returns the exact fragment written in a CREATE TABLE statement
+//     ("ON CONFLICT REPLACE"), or "" for the NONE values.
+//
+// UnmarshalText accepts either of the first two forms, ignoring case.
+
+type enumNames struct {
+	typeName string
+	strings  []string
+	text     []string
+}
+
+func (e *enumNames) string(value int) string {
+	if value < 0 || value >= len(e.strings) {
+		return fmt.Sprintf("%s(%d)", e.typeName, value)
+	}
+	return e.strings[value]
+}
+
+func (e *enumNames) marshalText(value int) ([]byte, error) {
+	if value < 0 || value >= len(e.text) {
+		return nil, fmt.Errorf("parser: invalid %s %d", e.typeName, value)
+	}
+	return []byte(e.text[value]), nil
+}
+
+func (e *enumNames) unmarshalText(text []byte) (int, error) {
+	s := string(text)
+	for i := range e.text {
+		if strings.EqualFold(s, e.text[i]) || strings.EqualFold(s, e.strings[i]) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("parser: invalid %s %q", e.typeName, s)
+}
+
+var errorCodeNames = enumNames{
+	typeName: "ErrorCode",
+	strings:  []string{"NONE", "SYNTAX", "UNSUPPORTEDSQL"},
+	text:     []string{"none", "syntax", "unsupported_sql"},
+}
+
+func (e ErrorCode) String() string {
+	return errorCodeNames.string(int(e))
+}
+
+func (e ErrorCode) MarshalText() ([]byte, error) {
+	return errorCodeNames.marshalText(int(e))
+}
+
+func (e *ErrorCode) UnmarshalText(text []byte) error {
+	value, err := errorCodeNames.unmarshalText(text)
+	*e = ErrorCode(value)
+	return err
+}
+
+var conflictClauseNames = enumNames{
+	typeName: "ConflictClause",
+	strings:  []string{"NONE", "ROLLBACK", "ABORT", "FAIL", "IGNORE", "REPLACE"},
+	text:     []string{"none", "rollback", "abort", "fail", "ignore", "replace"},
+}
+
+func (c ConflictClause) String() string {
+	return conflictClauseNames.string(int(c))
+}
+
+func (c ConflictClause) MarshalText() ([]byte, error) {
+	return conflictClauseNames.marshalText(int(c))
+}
+
+func (c *ConflictClause) UnmarshalText(text []byte) error {
+	value, err := conflictClauseNames.unmarshalText(text)
+	*c = ConflictClause(value)
+	return err
+}
+
+func (c ConflictClause) SQL() string {
+	if c == CONFLICT_NONE {
+		return ""
+	}
+	return "ON CONFLICT " + c.String()
+}
+
+var orderClauseNames = enumNames{
+	typeName: "OrderClause",
+	strings:  []string{"NONE", "ASC", "DESC"},
+	text:     []string{"none", "asc", "desc"},
+}
+
+func (o OrderClause) String() string {
+	return orderClauseNames.string(int(o))
+}
+
+func (o OrderClause) MarshalText() ([]byte, error) {
+	return orderClauseNames.marshalText(int(o))
+}
+
+func (o *OrderClause) UnmarshalText(text []byte) error {
+	value, err := orderClauseNames.unmarshalText(text)
+	*o = OrderClause(value)
+	return err
+}
+
+func (o OrderClause) SQL() string {
+	if o == ORDER_NONE {
+		return ""
+	}
+	return o.String()
+}
+
+var fkActionNames = enumNames{
+	typeName: "FkAction",
+	strings:  []string{"NONE", "SET NULL", "SET DEFAULT", "CASCADE", "RESTRICT", "NO ACTION"},
+	text:     []string{"none", "set_null", "set_default", "cascade", "restrict", "no_action"},
+}
+
+func (a FkAction) String() string {
+	return fkActionNames.string(int(a))
+}
+
+func (a FkAction) MarshalText() ([]byte, error) {
+	return fkActionNames.marshalText(int(a))
+}
+
+func (a *FkAction) UnmarshalText(text []byte) error {
+	value, err := fkActionNames.unmarshalText(text)
+	*a = FkAction(value)
+	return err
+}
+
+// SQL returns the action as written after ON DELETE or ON UPDATE.
+func (a FkAction) SQL() string {
+	if a == FKACTION_NONE {
+		return ""
+	}
+	return a.String()
+}
+
+var fkDefTypeNames = enumNames{
+	typeName: "FkDefType",
+	strings: []string{
+		"NONE",
+		"DEFERRABLE",
+		"DEFERRABLE INITIALLY DEFERRED",
+		"DEFERRABLE INITIALLY IMMEDIATE",
+		"NOT DEFERRABLE",
+		"NOT DEFERRABLE INITIALLY DEFERRED",
+		"NOT DEFERRABLE INITIALLY IMMEDIATE",
+	},
+	text: []string{
+		"none",
+		"deferrable",
+		"deferrable_initially_deferred",
+		"deferrable_initially_immediate",
+		"not_deferrable",
+		"not_deferrable_initially_deferred",
+		"not_deferrable_initially_immediate",
+	},
+}
+
+func (d FkDefType) String() string {
+	return fkDefTypeNames.string(int(d))
+}
+
+func (d FkDefType) MarshalText() ([]byte, error) {
+	return fkDefTypeNames.marshalText(int(d))
+}
+
+func (d *FkDefType) UnmarshalText(text []byte) error {
+	value, err := fkDefTypeNames.unmarshalText(text)
+	*d = FkDefType(value)
+	return err
+}
+
+func (d FkDefType) SQL() string {
+	if d == DEFTYPE_NONE {
+		return ""
+	}
+	return d.String()
+}
+
+var constraintTypeNames = enumNames{
+	typeName: "ConstraintType",
+	strings:  []string{"PRIMARY KEY", "UNIQUE", "CHECK", "FOREIGN KEY"},
+	text:     []string{"primary_key", "unique", "check", "foreign_key"},
+}
+
+func (t ConstraintType) String() string {
+	return constraintTypeNames.string(int(t))
+}
+
+func (t ConstraintType) MarshalText() ([]byte, error) {
+	return constraintTypeNames.marshalText(int(t))
+}
+
+func (t *ConstraintType) UnmarshalText(text []byte) error {
+	value, err := constraintTypeNames.unmarshalText(text)
+	*t = ConstraintType(value)
+	return err
+}
+
+// SQL returns the keywords introducing the constraint.
+func (t ConstraintType) SQL() string {
+	return t.String()
+}
+
+var columnConstraintTypeNames = enumNames{
+	typeName: "ColumnConstraintType",
+	strings:  []string{"PRIMARY KEY", "NOT NULL", "NULL", "UNIQUE", "CHECK", "DEFAULT", "COLLATE", "REFERENCES"},
+	text:     []string{"primary_key", "not_null", "null", "unique", "check", "default", "collate", "foreign_key"},
+}
+
+func (t ColumnConstraintType) String() string {
+	return columnConstraintTypeNames.string(int(t))
+}
+
+func (t ColumnConstraintType) MarshalText() ([]byte, error) {
+	return columnConstraintTypeNames.marshalText(int(t))
+}
+
+func (t *ColumnConstraintType) UnmarshalText(text []byte) error {
+	value, err := columnConstraintTypeNames.unmarshalText(text)
+	*t = ColumnConstraintType(value)
+	return err
+}
+
+// SQL returns the keywords introducing the constraint.
+func (t ColumnConstraintType) SQL() string {
+	return t.String()
+}
+
+var tokenKindNames = enumNames{
+	typeName: "TokenKind",
+	strings: []string{
+		"EOF", "ERROR", "WHITESPACE", "COMMENT", "KEYWORD", "IDENTIFIER",
+		"STRING", "NUMBER", "BLOB", "VARIABLE", "OPERATOR", "PUNCTUATION",
+	},
+	text: []string{
+		"eof", "error", "whitespace", "comment", "keyword", "identifier",
+		"string", "number", "blob", "variable", "operator", "punctuation",
+	},
+}
+
+func (k TokenKind) String() string {
+	return tokenKindNames.string(int(k))
+}
+
+func (k TokenKind) MarshalText() ([]byte, error) {
+	return tokenKindNames.marshalText(int(k))
+}
+
+func (k *TokenKind) UnmarshalText(text []byte) error {
+	value, err := tokenKindNames.unmarshalText(text)
+	*k = TokenKind(value)
+	return err
+}
+
+var exprKindNames = enumNames{
+	typeName: "ExprKind",
+	strings: []string{
+		"LITERAL", "COLUMN", "VARIABLE", "UNARY", "BINARY", "POSTFIX", "COLLATE", "BETWEEN",
+		"IN", "FUNCTION", "CAST", "CASE", "VECTOR", "SUBQUERY", "RAISE",
+	},
+	text: []string{
+		"literal", "column", "variable", "unary", "binary", "postfix", "collate", "between",
+		"in", "function", "cast", "case", "vector", "subquery", "raise",
+	},
+}
+
+func (k ExprKind) String() string {
+	return exprKindNames.string(int(k))
+}
+
+func (k ExprKind) MarshalText() ([]byte, error) {
+	return exprKindNames.marshalText(int(k))
+}
+
+func (k *ExprKind) UnmarshalText(text []byte) error {
+	value, err := exprKindNames.unmarshalText(text)
+	*k = ExprKind(value)
+	return err
+}
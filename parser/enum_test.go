@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnumString(t *testing.T) {
+	assert.Equal(t, "CASCADE", FKACTION_CASCADE.String())
+	assert.Equal(t, "SET NULL", FKACTION_SETNULL.String())
+	assert.Equal(t, "REPLACE", CONFLICT_REPLACE.String())
+	assert.Equal(t, "DESC", ORDER_DESC.String())
+	assert.Equal(t, "FOREIGN KEY", TABLECONSTRAINT_FOREIGNKEY.String())
+	assert.Equal(t, "NOT NULL", COLUMNCONSTRAINT_NOTNULL.String())
+	assert.Equal(t, "SYNTAX", ERROR_SYNTAX.String())
+	assert.Equal(t, "KEYWORD", TOKEN_KEYWORD.String())
+	assert.Equal(t, "FkAction(42)", FkAction(42).String())
+}
+
+func TestEnumSQL(t *testing.T) {
+	assert.Equal(t, "ON CONFLICT REPLACE", CONFLICT_REPLACE.SQL())
+	assert.Equal(t, "", CONFLICT_NONE.SQL())
+	assert.Equal(t, "DEFERRABLE INITIALLY DEFERRED", DEFTYPE_DEFERRABLE_INITIALLY_DEFERRED.SQL())
+	assert.Equal(t, "NO ACTION", FKACTION_NOACTION.SQL())
+	assert.Equal(t, "ASC", ORDER_ASC.SQL())
+	assert.Equal(t, "PRIMARY KEY", TABLECONSTRAINT_PRIMARYKEY.SQL())
+	assert.Equal(t, "REFERENCES", COLUMNCONSTRAINT_FOREIGNKEY.SQL())
+}
+
+func TestEnumText(t *testing.T) {
+	text, err := DEFTYPE_NOTDEFERRABLE_INITIALLY_IMMEDIATE.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "not_deferrable_initially_immediate", string(text))
+
+	var action FkAction
+	assert.NoError(t, action.UnmarshalText([]byte("set default")))
+	assert.Equal(t, FKACTION_SETDEFAULT, action)
+	assert.NoError(t, action.UnmarshalText([]byte("NO_ACTION")))
+	assert.Equal(t, FKACTION_NOACTION, action)
+	assert.Error(t, action.UnmarshalText([]byte("explode")))
+
+	var code ErrorCode
+	assert.NoError(t, code.UnmarshalText([]byte("unsupported_sql")))
+	assert.Equal(t, ERROR_UNSUPPORTEDSQL, code)
+}
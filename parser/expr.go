@@ -0,0 +1,688 @@
+package parser
+
+import (
+	"strings"
+)
+
+// ExprKind tells which kind of node an Expr is.
+type ExprKind int
+
+const (
+	EXPR_LITERAL ExprKind = iota
+	EXPR_COLUMN
+	EXPR_VARIABLE
+	EXPR_UNARY
+	EXPR_BINARY
+	EXPR_POSTFIX
+	EXPR_COLLATE
+	EXPR_BETWEEN
+	EXPR_IN
+	EXPR_FUNCTION
+	EXPR_CAST
+	EXPR_CASE
+	EXPR_VECTOR
+	EXPR_SUBQUERY
+	EXPR_RAISE
+)
+
+// Expr is a node of the tree of an SQL expression. The fields set depend on
+// Kind:
+//
+//   - EXPR_LITERAL: Value, the literal as written: a number, a string, a
+//     blob, NULL, TRUE, FALSE, CURRENT_DATE, CURRENT_TIME or
+//     CURRENT_TIMESTAMP.
+//   - EXPR_COLUMN: Name, with Table and Schema when it is qualified.
+//   - EXPR_VARIABLE: Value, the parameter as written, such as ?1 or :name.
+//   - EXPR_UNARY: Op, one of - + ~ NOT, applied to Args[0].
+//   - EXPR_BINARY: Args[0] Op Args[1], where Op is an operator such as ||,
+//     =, AND, IS NOT or NOT LIKE; Args[2] is the ESCAPE of a LIKE.
+//   - EXPR_POSTFIX: Args[0] followed by Op, one of ISNULL, NOTNULL and
+//     NOT NULL.
+//   - EXPR_COLLATE: Args[0] COLLATE Name.
+//   - EXPR_BETWEEN: Args[0] Op Args[1] AND Args[2], where Op is BETWEEN or
+//     NOT BETWEEN.
+//   - EXPR_IN: Args[0] Op, IN or NOT IN, followed by the list Args[1:], the
+//     subquery Select, or the table Name with its Schema.
+//   - EXPR_FUNCTION: a call of Name with Args, and Distinct, or with Star
+//     for name(*).
+//   - EXPR_CAST: CAST(Args[0] AS Type).
+//   - EXPR_CASE: CASE Operand, which may be nil, then pairs of WHEN Args[i]
+//     THEN Args[i+1], and an optional Else.
+//   - EXPR_VECTOR: a parenthesized list of two or more Args.
+//   - EXPR_SUBQUERY: the text of a select statement in Select, in
+//     parentheses, with Op EXISTS for EXISTS (select).
+//   - EXPR_RAISE: RAISE with Op, one of IGNORE ROLLBACK ABORT FAIL, and the
+//     message Args[0] of the last three.
+//
+// Keywords in Op are upper case and separated by single spaces; the other
+// operators are kept as written. Parentheses that only group are not kept.
+// Start and End are the byte offsets of the node in the parsed text.
+type Expr struct {
+	Kind     ExprKind  `json:"kind" yaml:"kind"`
+	Op       string    `json:"op" yaml:"op"`
+	Value    string    `json:"value" yaml:"value"`
+	Schema   string    `json:"schema" yaml:"schema"`
+	Table    string    `json:"table" yaml:"table"`
+	Name     string    `json:"name" yaml:"name"`
+	Args     []*Expr   `json:"args" yaml:"args"`
+	Distinct bool      `json:"distinct" yaml:"distinct"`
+	Star     bool      `json:"star" yaml:"star"`
+	Type     *TypeName `json:"type" yaml:"type"`
+	Operand  *Expr     `json:"operand" yaml:"operand"`
+	Else     *Expr     `json:"else" yaml:"else"`
+	Select   string    `json:"select" yaml:"select"`
+	Start    int       `json:"start" yaml:"start"`
+	End      int       `json:"end" yaml:"end"`
+}
+
+// Walk calls fn for e and then for each of its subexpressions, depth
+// first, as long as fn returns true.
+func (e *Expr) Walk(fn func(*Expr) bool) bool {
+	if e == nil {
+		return true
+	}
+	if !fn(e) {
+		return false
+	}
+	if !e.Operand.Walk(fn) {
+		return false
+	}
+	for _, arg := range e.Args {
+		if !arg.Walk(fn) {
+			return false
+		}
+	}
+	return e.Else.Walk(fn)
+}
+
+// The binding powers of the operators, from SQLite's parse.y.
+const (
+	precOr = iota + 1
+	precAnd
+	precNot
+	precEquality
+	precComparison
+	precEscape
+	precBitwise
+	precAdditive
+	precMultiplicative
+	precConcat
+	precCollate
+	precUnary
+	precPrimary
+)
+
+var binaryPrecedence = map[string]int{
+	"=": precEquality, "==": precEquality, "!=": precEquality, "<>": precEquality,
+	"<": precComparison, "<=": precComparison, ">": precComparison, ">=": precComparison,
+	"&": precBitwise, "|": precBitwise, "<<": precBitwise, ">>": precBitwise,
+	"+": precAdditive, "-": precAdditive,
+	"*": precMultiplicative, "/": precMultiplicative, "%": precMultiplicative,
+	"||": precConcat, "->": precConcat, "->>": precConcat,
+}
+
+// exprParser parses expressions from the tokens of a text, whitespace and
+// comments left out. base is added to the token offsets.
+type exprParser struct {
+	sql    string
+	tokens []Token
+	pos    int
+	base   int
+}
+
+func newExprParser(sql string, base int) *exprParser {
+	p := &exprParser{sql: sql, base: base}
+	tokenizer := NewTokenizer(sql)
+	for {
+		token := tokenizer.Next()
+		if token.Kind == TOKEN_WHITESPACE || token.Kind == TOKEN_COMMENT {
+			continue
+		}
+		p.tokens = append(p.tokens, token)
+		if token.Kind == TOKEN_EOF {
+			return p
+		}
+	}
+}
+
+func (p *exprParser) peek() Token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) peekAt(n int) Token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *exprParser) next() Token {
+	token := p.tokens[p.pos]
+	if token.Kind != TOKEN_EOF {
+		p.pos++
+	}
+	return token
+}
+
+// accept consumes the next token if it is the keyword or punctuation text.
+func (p *exprParser) accept(text string) bool {
+	token := p.peek()
+	if token.Kind == TOKEN_KEYWORD && strings.EqualFold(token.Text, text) ||
+		(token.Kind == TOKEN_PUNCTUATION || token.Kind == TOKEN_OPERATOR) && token.Text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// end returns the offset just past the last token consumed.
+func (p *exprParser) end() int {
+	if p.pos == 0 {
+		return p.base
+	}
+	return p.base + p.tokens[p.pos-1].End
+}
+
+func (p *exprParser) start() int {
+	return p.base + p.peek().Start
+}
+
+// isName reports whether token is a name in a place where SQLite accepts
+// the keywords of usage as names.
+func isName(token Token, usage uint8) bool {
+	switch token.Kind {
+	case TOKEN_IDENTIFIER:
+		return true
+	case TOKEN_KEYWORD:
+		k := lookupKeyword(token.Text)
+		return k != nil && k.usage&usage != 0
+	}
+	return false
+}
+
+// ParseExpr parses an SQL expression, such as the text of a CHECK
+// constraint or of the WHERE clause of an index.
+func ParseExpr(sql string) (*Expr, ErrorCode) {
+	p := newExprParser(sql, 0)
+	expr, errCode := p.parseExpr(0)
+	if errCode != ERROR_NONE {
+		return nil, errCode
+	}
+	if p.peek().Kind != TOKEN_EOF {
+		return nil, ERROR_SYNTAX
+	}
+	return expr, ERROR_NONE
+}
+
+// parseExpr parses an expression whose operators bind at least as tightly
+// as min.
+func (p *exprParser) parseExpr(min int) (*Expr, ErrorCode) {
+	left, errCode := p.parsePrimary()
+	if errCode != ERROR_NONE {
+		return nil, errCode
+	}
+	for {
+		start := left.Start
+		token := p.peek()
+		if token.Kind == TOKEN_OPERATOR {
+			prec, ok := binaryPrecedence[token.Text]
+			if !ok || prec < min {
+				return left, ERROR_NONE
+			}
+			p.next()
+			right, errCode := p.parseExpr(prec + 1)
+			if errCode != ERROR_NONE {
+				return nil, errCode
+			}
+			left = &Expr{Kind: EXPR_BINARY, Op: token.Text, Args: []*Expr{left, right}, Start: start, End: p.end()}
+			continue
+		}
+		if token.Kind != TOKEN_KEYWORD {
+			return left, ERROR_NONE
+		}
+
+		word := strings.ToUpper(token.Text)
+		not := false
+		if word == "NOT" {
+			following := strings.ToUpper(p.peekAt(1).Text)
+			switch following {
+			case "NULL":
+				word = "NOT NULL"
+			case "IN", "LIKE", "GLOB", "MATCH", "REGEXP", "BETWEEN":
+				not, word = true, following
+			default:
+				return left, ERROR_NONE
+			}
+		}
+
+		var prec int
+		switch word {
+		case "OR":
+			prec = precOr
+		case "AND":
+			prec = precAnd
+		case "COLLATE":
+			prec = precCollate
+		case "IS", "ISNULL", "NOTNULL", "NOT NULL", "IN", "LIKE", "GLOB", "MATCH", "REGEXP", "BETWEEN":
+			prec = precEquality
+		default:
+			return left, ERROR_NONE
+		}
+		if prec < min {
+			return left, ERROR_NONE
+		}
+		p.next()
+		if not {
+			p.next()
+		}
+		op := word
+		if not {
+			op = "NOT " + word
+		}
+
+		switch word {
+		case "COLLATE":
+			name := p.next()
+			if !isName(name, keywordID) {
+				return nil, ERROR_SYNTAX
+			}
+			left = &Expr{Kind: EXPR_COLLATE, Name: name.Value(), Args: []*Expr{left}}
+		case "ISNULL", "NOTNULL", "NOT NULL":
+			if word == "NOT NULL" {
+				p.next()
+			}
+			left = &Expr{Kind: EXPR_POSTFIX, Op: word, Args: []*Expr{left}}
+		case "BETWEEN":
+			low, errCode := p.parseExpr(precComparison)
+			if errCode != ERROR_NONE {
+				return nil, errCode
+			}
+			if !p.accept("AND") {
+				return nil, ERROR_SYNTAX
+			}
+			high, errCode := p.parseExpr(precComparison)
+			if errCode != ERROR_NONE {
+				return nil, errCode
+			}
+			left = &Expr{Kind: EXPR_BETWEEN, Op: op, Args: []*Expr{left, low, high}}
+		case "IN":
+			if left, errCode = p.parseIn(left, op); errCode != ERROR_NONE {
+				return nil, errCode
+			}
+		case "LIKE", "GLOB", "MATCH", "REGEXP":
+			right, errCode := p.parseExpr(precComparison)
+			if errCode != ERROR_NONE {
+				return nil, errCode
+			}
+			left = &Expr{Kind: EXPR_BINARY, Op: op, Args: []*Expr{left, right}}
+			if p.accept("ESCAPE") {
+				escape, errCode := p.parseExpr(precEscape)
+				if errCode != ERROR_NONE {
+					return nil, errCode
+				}
+				left.Args = append(left.Args, escape)
+			}
+		case "IS":
+			if p.accept("NOT") {
+				op = "IS NOT"
+			}
+			if p.accept("DISTINCT") {
+				if !p.accept("FROM") {
+					return nil, ERROR_SYNTAX
+				}
+				op += " DISTINCT FROM"
+			}
+			right, errCode := p.parseExpr(precComparison)
+			if errCode != ERROR_NONE {
+				return nil, errCode
+			}
+			left = &Expr{Kind: EXPR_BINARY, Op: op, Args: []*Expr{left, right}}
+		default:
+			right, errCode := p.parseExpr(prec + 1)
+			if errCode != ERROR_NONE {
+				return nil, errCode
+			}
+			left = &Expr{Kind: EXPR_BINARY, Op: op, Args: []*Expr{left, right}}
+		}
+		left.Start, left.End = start, p.end()
+	}
+}
+
+// parseIn parses what follows IN: a parenthesized list or select, or a
+// table name.
+func (p *exprParser) parseIn(left *Expr, op string) (*Expr, ErrorCode) {
+	in := &Expr{Kind: EXPR_IN, Op: op, Args: []*Expr{left}}
+	if !p.accept("(") {
+		if !isName(p.peek(), keywordName) {
+			return nil, ERROR_SYNTAX
+		}
+		in.Name = p.next().Value()
+		if p.accept(".") {
+			if !isName(p.peek(), keywordName) {
+				return nil, ERROR_SYNTAX
+			}
+			in.Schema, in.Name = in.Name, p.next().Value()
+		}
+		if p.peek().Text == "(" {
+			// A table-valued function.
+			return nil, ERROR_UNSUPPORTEDSQL
+		}
+		return in, ERROR_NONE
+	}
+	if p.isSelect() {
+		var errCode ErrorCode
+		if in.Select, errCode = p.parseSelect(); errCode != ERROR_NONE {
+			return nil, errCode
+		}
+		return in, ERROR_NONE
+	}
+	if p.accept(")") {
+		return in, ERROR_NONE
+	}
+	list, errCode := p.parseList()
+	if errCode != ERROR_NONE {
+		return nil, errCode
+	}
+	in.Args = append(in.Args, list...)
+	return in, ERROR_NONE
+}
+
+// parseList parses expressions separated by commas up to a closing
+// parenthesis, which it consumes.
+func (p *exprParser) parseList() ([]*Expr, ErrorCode) {
+	var list []*Expr
+	for {
+		expr, errCode := p.parseExpr(0)
+		if errCode != ERROR_NONE {
+			return nil, errCode
+		}
+		list = append(list, expr)
+		if p.accept(")") {
+			return list, ERROR_NONE
+		}
+		if !p.accept(",") {
+			return nil, ERROR_SYNTAX
+		}
+	}
+}
+
+func (p *exprParser) isSelect() bool {
+	token := p.peek()
+	return token.Is("SELECT") || token.Is("VALUES") || token.Is("WITH")
+}
+
+// parseSelect skips a select statement up to the parenthesis closing it,
+// which it consumes, and returns its text.
+func (p *exprParser) parseSelect() (string, ErrorCode) {
+	start, depth := p.peek().Start, 0
+	for {
+		token := p.next()
+		switch {
+		case token.Kind == TOKEN_EOF || token.Kind == TOKEN_ERROR:
+			return "", ERROR_SYNTAX
+		case token.Text == "(":
+			depth++
+		case token.Text == ")" && depth > 0:
+			depth--
+		case token.Text == ")":
+			return strings.TrimSpace(p.sql[start:token.Start]), ERROR_NONE
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (*Expr, ErrorCode) {
+	start := p.start()
+	expr, errCode := p.parseOperand()
+	if errCode != ERROR_NONE {
+		return nil, errCode
+	}
+	expr.Start, expr.End = start, p.end()
+	return expr, ERROR_NONE
+}
+
+func (p *exprParser) parseOperand() (*Expr, ErrorCode) {
+	token := p.next()
+	switch token.Kind {
+	case TOKEN_NUMBER, TOKEN_STRING, TOKEN_BLOB:
+		return &Expr{Kind: EXPR_LITERAL, Value: token.Text}, ERROR_NONE
+	case TOKEN_VARIABLE:
+		return &Expr{Kind: EXPR_VARIABLE, Value: token.Text}, ERROR_NONE
+	case TOKEN_OPERATOR:
+		if token.Text != "-" && token.Text != "+" && token.Text != "~" {
+			return nil, ERROR_SYNTAX
+		}
+		operand, errCode := p.parseExpr(precUnary)
+		if errCode != ERROR_NONE {
+			return nil, errCode
+		}
+		return &Expr{Kind: EXPR_UNARY, Op: token.Text, Args: []*Expr{operand}}, ERROR_NONE
+	case TOKEN_PUNCTUATION:
+		if token.Text != "(" {
+			return nil, ERROR_SYNTAX
+		}
+		if p.isSelect() {
+			query, errCode := p.parseSelect()
+			if errCode != ERROR_NONE {
+				return nil, errCode
+			}
+			return &Expr{Kind: EXPR_SUBQUERY, Select: query}, ERROR_NONE
+		}
+		list, errCode := p.parseList()
+		if errCode != ERROR_NONE {
+			return nil, errCode
+		}
+		if len(list) == 1 {
+			return list[0], ERROR_NONE
+		}
+		return &Expr{Kind: EXPR_VECTOR, Args: list}, ERROR_NONE
+	case TOKEN_KEYWORD, TOKEN_IDENTIFIER:
+		return p.parseWord(token)
+	}
+	return nil, ERROR_SYNTAX
+}
+
+// parseWord parses an operand starting with a word: a keyword starting an
+// expression, a function call or a column.
+func (p *exprParser) parseWord(token Token) (*Expr, ErrorCode) {
+	if token.Kind == TOKEN_KEYWORD {
+		switch word := strings.ToUpper(token.Text); word {
+		case "NULL", "CURRENT_DATE", "CURRENT_TIME", "CURRENT_TIMESTAMP":
+			return &Expr{Kind: EXPR_LITERAL, Value: word}, ERROR_NONE
+		case "NOT":
+			operand, errCode := p.parseExpr(precNot)
+			if errCode != ERROR_NONE {
+				return nil, errCode
+			}
+			return &Expr{Kind: EXPR_UNARY, Op: word, Args: []*Expr{operand}}, ERROR_NONE
+		case "EXISTS":
+			if !p.accept("(") || !p.isSelect() {
+				return nil, ERROR_SYNTAX
+			}
+			query, errCode := p.parseSelect()
+			if errCode != ERROR_NONE {
+				return nil, errCode
+			}
+			return &Expr{Kind: EXPR_SUBQUERY, Op: word, Select: query}, ERROR_NONE
+		case "CAST":
+			return p.parseCast()
+		case "CASE":
+			return p.parseCase()
+		case "RAISE":
+			return p.parseRaise()
+		}
+	}
+
+	if p.peek().Text == "(" && isName(token, keywordName) {
+		p.next()
+		return p.parseFunction(token.Value())
+	}
+	if p.peek().Text == "." {
+		if !isName(token, keywordName) {
+			return nil, ERROR_SYNTAX
+		}
+		column := &Expr{Kind: EXPR_COLUMN}
+		names := []string{token.Value()}
+		for len(names) < 3 && p.accept(".") {
+			name := p.next()
+			if !isName(name, keywordName) {
+				return nil, ERROR_SYNTAX
+			}
+			names = append(names, name.Value())
+		}
+		if len(names) == 3 {
+			column.Schema, names = names[0], names[1:]
+		}
+		column.Table, column.Name = names[0], names[1]
+		return column, ERROR_NONE
+	}
+	if !isName(token, keywordColumn) {
+		return nil, ERROR_SYNTAX
+	}
+	if token.Kind == TOKEN_IDENTIFIER && (token.Is("TRUE") || token.Is("FALSE")) {
+		return &Expr{Kind: EXPR_LITERAL, Value: strings.ToUpper(token.Text)}, ERROR_NONE
+	}
+	return &Expr{Kind: EXPR_COLUMN, Name: token.Value()}, ERROR_NONE
+}
+
+func (p *exprParser) parseFunction(name string) (*Expr, ErrorCode) {
+	function := &Expr{Kind: EXPR_FUNCTION, Name: name}
+	switch {
+	case p.accept("*"):
+		function.Star = true
+		if !p.accept(")") {
+			return nil, ERROR_SYNTAX
+		}
+	case p.accept(")"):
+	default:
+		function.Distinct = p.accept("DISTINCT")
+		if !function.Distinct {
+			p.accept("ALL")
+		}
+		args, errCode := p.parseList()
+		if errCode != ERROR_NONE {
+			return nil, errCode
+		}
+		function.Args = args
+	}
+	if next := p.peek(); next.Is("FILTER") || next.Is("OVER") {
+		return nil, ERROR_UNSUPPORTEDSQL
+	}
+	return function, ERROR_NONE
+}
+
+func (p *exprParser) parseCast() (*Expr, ErrorCode) {
+	if !p.accept("(") {
+		return nil, ERROR_SYNTAX
+	}
+	operand, errCode := p.parseExpr(0)
+	if errCode != ERROR_NONE {
+		return nil, errCode
+	}
+	if !p.accept("AS") {
+		return nil, ERROR_SYNTAX
+	}
+	typeName, errCode := p.parseTypeName()
+	if errCode != ERROR_NONE {
+		return nil, errCode
+	}
+	if !p.accept(")") {
+		return nil, ERROR_SYNTAX
+	}
+	return &Expr{Kind: EXPR_CAST, Args: []*Expr{operand}, Type: typeName}, ERROR_NONE
+}
+
+// parseTypeName parses the type of a CAST, following the grammar of
+// parseColumnType.
+func (p *exprParser) parseTypeName() (*TypeName, ErrorCode) {
+	var typeName TypeName
+	start := p.peek().Start
+	for isName(p.peek(), keywordID) {
+		typeName.Words = append(typeName.Words, p.next().Value())
+	}
+	if len(typeName.Words) == 0 {
+		return nil, ERROR_SYNTAX
+	}
+	if p.accept("(") {
+		for {
+			var number SignedNumber
+			if sign := p.peek(); sign.Text == "+" || sign.Text == "-" {
+				number.Sign = p.next().Text
+			}
+			value := p.next()
+			if value.Kind != TOKEN_NUMBER {
+				return nil, ERROR_SYNTAX
+			}
+			number.Value = value.Text
+			typeName.Args = append(typeName.Args, number)
+			if p.accept(")") {
+				break
+			}
+			if len(typeName.Args) == 2 || !p.accept(",") {
+				return nil, ERROR_SYNTAX
+			}
+		}
+	}
+	typeName.Text = p.sql[start : p.end()-p.base]
+	return &typeName, ERROR_NONE
+}
+
+func (p *exprParser) parseCase() (*Expr, ErrorCode) {
+	expr := &Expr{Kind: EXPR_CASE}
+	var errCode ErrorCode
+	if !p.peek().Is("WHEN") {
+		if expr.Operand, errCode = p.parseExpr(0); errCode != ERROR_NONE {
+			return nil, errCode
+		}
+	}
+	for p.accept("WHEN") {
+		when, errCode := p.parseExpr(0)
+		if errCode != ERROR_NONE {
+			return nil, errCode
+		}
+		if !p.accept("THEN") {
+			return nil, ERROR_SYNTAX
+		}
+		then, errCode := p.parseExpr(0)
+		if errCode != ERROR_NONE {
+			return nil, errCode
+		}
+		expr.Args = append(expr.Args, when, then)
+	}
+	if len(expr.Args) == 0 {
+		return nil, ERROR_SYNTAX
+	}
+	if p.accept("ELSE") {
+		if expr.Else, errCode = p.parseExpr(0); errCode != ERROR_NONE {
+			return nil, errCode
+		}
+	}
+	if !p.accept("END") {
+		return nil, ERROR_SYNTAX
+	}
+	return expr, ERROR_NONE
+}
+
+func (p *exprParser) parseRaise() (*Expr, ErrorCode) {
+	if !p.accept("(") {
+		return nil, ERROR_SYNTAX
+	}
+	expr := &Expr{Kind: EXPR_RAISE, Op: strings.ToUpper(p.next().Text)}
+	switch expr.Op {
+	case "IGNORE":
+	case "ROLLBACK", "ABORT", "FAIL":
+		if !p.accept(",") {
+			return nil, ERROR_SYNTAX
+		}
+		message, errCode := p.parseExpr(0)
+		if errCode != ERROR_NONE {
+			return nil, errCode
+		}
+		expr.Args = []*Expr{message}
+	default:
+		return nil, ERROR_SYNTAX
+	}
+	if !p.accept(")") {
+		return nil, ERROR_SYNTAX
+	}
+	return expr, ERROR_NONE
+}
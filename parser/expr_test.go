@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExpr(t *testing.T) {
+	expr, errCode := ParseExpr("a + b * -c || 'x' COLLATE nocase")
+	if assert.Equal(t, ERROR_NONE, errCode) {
+		assert.Equal(t, EXPR_BINARY, expr.Kind)
+		assert.Equal(t, "+", expr.Op)
+		assert.Equal(t, "a", expr.Args[0].Name)
+		mul := expr.Args[1]
+		assert.Equal(t, "*", mul.Op)
+		assert.Equal(t, "b", mul.Args[0].Name)
+		concat := mul.Args[1]
+		assert.Equal(t, "||", concat.Op)
+		assert.Equal(t, EXPR_UNARY, concat.Args[0].Kind)
+		assert.Equal(t, EXPR_COLLATE, concat.Args[1].Kind)
+		assert.Equal(t, "nocase", concat.Args[1].Name)
+		assert.Equal(t, "'x'", concat.Args[1].Args[0].Value)
+		assert.Equal(t, 0, expr.Start)
+		assert.Equal(t, 32, expr.End)
+		assert.Equal(t, 4, mul.Start)
+	}
+
+	expr, errCode = ParseExpr(`NOT x.y NOT BETWEEN 1 AND 2 AND "z" IS NOT DISTINCT FROM ? OR w NOT NULL`)
+	if assert.Equal(t, ERROR_NONE, errCode) {
+		assert.Equal(t, "OR", expr.Op)
+		and := expr.Args[0]
+		assert.Equal(t, "AND", and.Op)
+		not := and.Args[0]
+		assert.Equal(t, EXPR_UNARY, not.Kind)
+		between := not.Args[0]
+		assert.Equal(t, EXPR_BETWEEN, between.Kind)
+		assert.Equal(t, "NOT BETWEEN", between.Op)
+		assert.Equal(t, "x", between.Args[0].Table)
+		assert.Equal(t, "y", between.Args[0].Name)
+		assert.Equal(t, "IS NOT DISTINCT FROM", and.Args[1].Op)
+		assert.Equal(t, EXPR_VARIABLE, and.Args[1].Args[1].Kind)
+		assert.Equal(t, EXPR_POSTFIX, expr.Args[1].Kind)
+		assert.Equal(t, "NOT NULL", expr.Args[1].Op)
+	}
+
+	expr, errCode = ParseExpr("count(DISTINCT key) + max(*) + CAST(x AS DECIMAL(10, -2)) + CASE WHEN a THEN 1 ELSE 2 END")
+	if assert.Equal(t, ERROR_NONE, errCode) {
+		var kinds []ExprKind
+		expr.Walk(func(e *Expr) bool {
+			kinds = append(kinds, e.Kind)
+			return true
+		})
+		assert.Equal(t, []ExprKind{
+			EXPR_BINARY, EXPR_BINARY, EXPR_BINARY, EXPR_FUNCTION, EXPR_COLUMN, EXPR_FUNCTION,
+			EXPR_CAST, EXPR_COLUMN, EXPR_CASE, EXPR_COLUMN, EXPR_LITERAL, EXPR_LITERAL,
+		}, kinds)
+	}
+
+	expr, errCode = ParseExpr("a IN (SELECT b FROM (SELECT 1 AS b)) AND c NOT IN (1, 2) AND d IN main.t AND (e, f) = (1, 2)")
+	if assert.Equal(t, ERROR_NONE, errCode) {
+		in := expr.Args[0].Args[0].Args[0]
+		assert.Equal(t, "SELECT b FROM (SELECT 1 AS b)", in.Select)
+		assert.Equal(t, "NOT IN", expr.Args[0].Args[0].Args[1].Op)
+		assert.Len(t, expr.Args[0].Args[0].Args[1].Args, 3)
+		assert.Equal(t, "main", expr.Args[0].Args[1].Schema)
+		assert.Equal(t, EXPR_VECTOR, expr.Args[1].Args[0].Kind)
+	}
+
+	for _, sql := range []string{
+		"", "a +", "(a", "a b", "f(a,)", "CAST(a)", "CASE END", "x IN", "1 NOT", "'unterminated",
+		"CAST(a AS INT(max))", "RAISE(a)", "a ISNULL b", "EXISTS a",
+	} {
+		_, errCode := ParseExpr(sql)
+		assert.Equal(t, ERROR_SYNTAX, errCode, sql)
+	}
+	_, errCode = ParseExpr("sum(a) OVER ()")
+	assert.Equal(t, ERROR_UNSUPPORTEDSQL, errCode)
+}
+
+func TestFormatExpr(t *testing.T) {
+	for sql, formatted := range map[string]string{
+		"a+b*c":                        "a + b * c",
+		"(a+b)*c":                      "(a + b) * c",
+		"a-(b-c)":                      "a - (b - c)",
+		"- -a":                         "- -a",
+		"NOT (a AND b) OR c":           "NOT (a AND b) OR c",
+		"(a OR b) AND c":               "(a OR b) AND c",
+		"(a COLLATE x) || b":           "a COLLATE x || b",
+		"(a || b) COLLATE x":           "(a || b) COLLATE x",
+		"a like b escape '!'":          "a LIKE b ESCAPE '!'",
+		"x between 1+1 and 3":          "x BETWEEN 1 + 1 AND 3",
+		"\"key\" in (1,2)":             `"key" IN (1, 2)`,
+		"replace(a, 'x', '')":          "replace(a, 'x', '')",
+		"count(*)":                     "count(*)",
+		"cast(a as \"my type\"(1,+2))": `CAST(a AS "my type"(1, +2))`,
+		"case a when 1 then 'one' end": "CASE a WHEN 1 THEN 'one' END",
+		"not exists (select 1)":        "NOT EXISTS (select 1)",
+		"raise(abort, 'no')":           "RAISE(ABORT, 'no')",
+	} {
+		expr, errCode := ParseExpr(sql)
+		if assert.Equal(t, ERROR_NONE, errCode, sql) {
+			assert.Equal(t, formatted, FormatExpr(expr), sql)
+			reparsed, errCode := ParseExpr(formatted)
+			if assert.Equal(t, ERROR_NONE, errCode, formatted) {
+				assert.Equal(t, formatted, FormatExpr(reparsed), sql)
+			}
+		}
+	}
+}
@@ -0,0 +1,576 @@
+package parser
+
+import (
+	"strings"
+)
+
+// QuoteIdentifier returns name as it must be written in SQL: unchanged when
+// it is a plain identifier, otherwise wrapped in double quotes.
+func QuoteIdentifier(name string) string {
+	plain := name != "" && !isDigit(name[0]) && name[0] != '$'
+	for i := 0; plain && i < len(name); i++ {
+		plain = isIDChar(name[i])
+	}
+	if plain && !IsKeyword(name) {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteWord quotes a word like QuoteIdentifier, but leaves alone the
+// keywords SQLite accepts as names where usage says, such as KEY in a type
+// name.
+func quoteWord(word string, usage uint8) string {
+	if k := lookupKeyword(word); k != nil && k.usage&usage != 0 {
+		return word
+	}
+	return QuoteIdentifier(word)
+}
+
+func quoteIdentifiers(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = QuoteIdentifier(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func formatLiteral(value string) string {
+	switch strings.ToUpper(value) {
+	case "CURRENT_TIME", "CURRENT_DATE", "CURRENT_TIMESTAMP", "TRUE", "FALSE":
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func writeConflictClause(b *strings.Builder, clause ConflictClause) {
+	if clause != CONFLICT_NONE {
+		b.WriteString(" ")
+		b.WriteString(clause.SQL())
+	}
+}
+
+func writeOrder(b *strings.Builder, order OrderClause) {
+	if order != ORDER_NONE {
+		b.WriteString(" ")
+		b.WriteString(order.SQL())
+	}
+}
+
+// FormatForeignKey returns the foreign-key-clause of fk, starting with
+// REFERENCES.
+func FormatForeignKey(fk *ForeignKey) string {
+	var b strings.Builder
+	b.WriteString("REFERENCES ")
+	b.WriteString(QuoteIdentifier(fk.Table))
+	if len(fk.ColumnName) > 0 {
+		b.WriteString(" (")
+		b.WriteString(quoteIdentifiers(fk.ColumnName))
+		b.WriteString(")")
+	}
+	if fk.OnDelete != FKACTION_NONE {
+		b.WriteString(" ON DELETE ")
+		b.WriteString(fk.OnDelete.SQL())
+	}
+	if fk.OnUpdate != FKACTION_NONE {
+		b.WriteString(" ON UPDATE ")
+		b.WriteString(fk.OnUpdate.SQL())
+	}
+	if fk.Match != "" {
+		b.WriteString(" MATCH ")
+		b.WriteString(QuoteIdentifier(fk.Match))
+	}
+	if fk.Deferrable != DEFTYPE_NONE {
+		b.WriteString(" ")
+		b.WriteString(fk.Deferrable.SQL())
+	}
+	return b.String()
+}
+
+// FormatColumnConstraint returns the column-constraint of constraint.
+func FormatColumnConstraint(constraint *ColumnConstraint) string {
+	var b strings.Builder
+	if constraint.Name != "" {
+		b.WriteString("CONSTRAINT ")
+		b.WriteString(QuoteIdentifier(constraint.Name))
+		b.WriteString(" ")
+	}
+	switch constraint.Type {
+	case COLUMNCONSTRAINT_CHECK:
+		b.WriteString("CHECK (")
+		b.WriteString(constraint.Expr)
+		b.WriteString(")")
+	case COLUMNCONSTRAINT_DEFAULT:
+		b.WriteString("DEFAULT ")
+		b.WriteString(formatLiteral(constraint.Expr))
+	case COLUMNCONSTRAINT_COLLATE:
+		b.WriteString("COLLATE ")
+		b.WriteString(QuoteIdentifier(constraint.CollateName))
+	case COLUMNCONSTRAINT_FOREIGNKEY:
+		if constraint.ForeignKeyClause != nil {
+			b.WriteString(FormatForeignKey(constraint.ForeignKeyClause))
+		}
+	default:
+		b.WriteString(constraint.Type.SQL())
+		writeOrder(&b, constraint.Order)
+		writeConflictClause(&b, constraint.ConflictClause)
+		if constraint.IsAutoincrement {
+			b.WriteString(" AUTOINCREMENT")
+		}
+	}
+	return b.String()
+}
+
+// FormatColumn returns the column-def of column. Its constraints are
+// written from Constraints when it is set, in their order and with their
+// names, and otherwise from the fields summing them up.
+func FormatColumn(column *Column) string {
+	var b strings.Builder
+	b.WriteString(QuoteIdentifier(column.Name))
+	if column.TypeName != nil {
+		for _, word := range column.TypeName.Words {
+			b.WriteString(" ")
+			b.WriteString(quoteWord(word, keywordID))
+		}
+	} else if column.Type != "" {
+		b.WriteString(" ")
+		b.WriteString(column.Type)
+	}
+	if column.Type != "" && column.Length != "" {
+		b.WriteString("(")
+		b.WriteString(column.Length)
+		b.WriteString(")")
+	}
+	if column.Constraints != nil {
+		for i := range column.Constraints {
+			b.WriteString(" ")
+			b.WriteString(FormatColumnConstraint(&column.Constraints[i]))
+		}
+		return b.String()
+	}
+	if column.ConstraintName != "" {
+		b.WriteString(" CONSTRAINT ")
+		b.WriteString(QuoteIdentifier(column.ConstraintName))
+	}
+	if column.IsPrimaryKey {
+		b.WriteString(" PRIMARY KEY")
+		writeOrder(&b, column.PkOrder)
+		writeConflictClause(&b, column.PkConflictClause)
+		if column.IsAutoincrement {
+			b.WriteString(" AUTOINCREMENT")
+		}
+	}
+	if column.IsNotnull {
+		b.WriteString(" NOT NULL")
+		writeConflictClause(&b, column.NotNullConflictClause)
+	}
+	if column.IsUnique {
+		b.WriteString(" UNIQUE")
+		writeConflictClause(&b, column.UniqueConflictClause)
+	}
+	if column.CheckExpr != "" {
+		b.WriteString(" CHECK (")
+		b.WriteString(column.CheckExpr)
+		b.WriteString(")")
+	}
+	if column.DefaultExpr != "" {
+		b.WriteString(" DEFAULT ")
+		b.WriteString(formatLiteral(column.DefaultExpr))
+	}
+	if column.CollateName != "" {
+		b.WriteString(" COLLATE ")
+		b.WriteString(QuoteIdentifier(column.CollateName))
+	}
+	if column.ForeignKeyClause != nil {
+		b.WriteString(" ")
+		b.WriteString(FormatForeignKey(column.ForeignKeyClause))
+	}
+	return b.String()
+}
+
+// FormatTableConstraint returns the table-constraint of constraint.
+func FormatTableConstraint(constraint *TableConstraint) string {
+	var b strings.Builder
+	if constraint.Name != "" {
+		b.WriteString("CONSTRAINT ")
+		b.WriteString(QuoteIdentifier(constraint.Name))
+		b.WriteString(" ")
+	}
+	switch constraint.Type {
+	case TABLECONSTRAINT_PRIMARYKEY, TABLECONSTRAINT_UNIQUE:
+		b.WriteString(constraint.Type.SQL())
+		b.WriteString(" (")
+		for i, column := range constraint.IndexedColumns {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if column.Expr != nil {
+				min := 0
+				if column.CollateName != "" {
+					min = precCollate
+				}
+				writeExpr(&b, column.Expr, min)
+			} else {
+				b.WriteString(QuoteIdentifier(column.Name))
+			}
+			if column.CollateName != "" {
+				b.WriteString(" COLLATE ")
+				b.WriteString(QuoteIdentifier(column.CollateName))
+			}
+			writeOrder(&b, column.Order)
+		}
+		b.WriteString(")")
+		writeConflictClause(&b, constraint.ConflictClause)
+	case TABLECONSTRAINT_CHECK:
+		b.WriteString(constraint.Type.SQL())
+		b.WriteString(" (")
+		b.WriteString(constraint.CheckExpr)
+		b.WriteString(")")
+		writeConflictClause(&b, constraint.ConflictClause)
+	case TABLECONSTRAINT_FOREIGNKEY:
+		b.WriteString(constraint.Type.SQL())
+		b.WriteString(" (")
+		b.WriteString(quoteIdentifiers(constraint.ForeignKeyName))
+		b.WriteString(")")
+		if constraint.ForeignKeyClause != nil {
+			b.WriteString(" ")
+			b.WriteString(FormatForeignKey(constraint.ForeignKeyClause))
+		}
+	}
+	return b.String()
+}
+
+// Format returns a canonical CREATE TABLE statement for table, one column
+// or table constraint per line, terminated by a semicolon.
+func Format(table *Table) string {
+	return formatTable(table, &statementComments{})
+}
+
+// FormatWithComments is Format keeping the comments of sql, the CREATE
+// TABLE statement table was parsed from. A comment on its own line is
+// written above the column or table constraint that follows it, and one
+// ending a line after the end of a column or table constraint after it;
+// comments before the column list and after the closing parenthesis are
+// written above the statement.
+func FormatWithComments(table *Table, sql string) string {
+	return formatTable(table, findComments(sql))
+}
+
+// statementComments holds the comments of a CREATE TABLE statement: head
+// before the statement, and before and after the i-th line of its body.
+type statementComments struct {
+	head   []string
+	before map[int][]string
+	after  map[int][]string
+}
+
+func findComments(sql string) *statementComments {
+	comments := &statementComments{before: map[int][]string{}, after: map[int][]string{}}
+	depth, item := 0, 0
+	started, newline, closed := false, true, false
+	tokenizer := NewTokenizer(sql)
+	for token := tokenizer.Next(); token.Kind != TOKEN_EOF; token = tokenizer.Next() {
+		switch {
+		case token.Kind == TOKEN_WHITESPACE:
+			newline = newline || strings.Contains(token.Text, "\n")
+			continue
+		case token.Kind == TOKEN_COMMENT:
+			switch {
+			case depth == 0:
+				comments.head = append(comments.head, token.Text)
+			case started:
+				comments.after[item] = append(comments.after[item], token.Text)
+			case !newline && item > 0:
+				// After the comma ending the previous line.
+				comments.after[item-1] = append(comments.after[item-1], token.Text)
+			case !newline:
+				comments.head = append(comments.head, token.Text)
+			default:
+				comments.before[item] = append(comments.before[item], token.Text)
+			}
+			continue
+		case closed:
+		case token.Text == "(":
+			if depth > 0 {
+				started = true
+			}
+			depth++
+		case token.Text == ")" && depth > 0:
+			depth--
+			closed = depth == 0
+		case token.Text == "," && depth == 1:
+			item++
+			started = false
+		case depth > 0:
+			started = true
+		}
+		newline = false
+	}
+	return comments
+}
+
+// inline reports whether comments can follow the text of a line: all but
+// the last one must be block comments within a line.
+func inline(comments []string) bool {
+	for i, comment := range comments {
+		if i < len(comments)-1 && strings.HasPrefix(comment, "--") || strings.Contains(comment, "\n") {
+			return false
+		}
+	}
+	return true
+}
+
+func formatTable(table *Table, comments *statementComments) string {
+	var b strings.Builder
+	for _, comment := range comments.head {
+		b.WriteString(comment)
+		b.WriteString("\n")
+	}
+	b.WriteString("CREATE ")
+	if table.IsTemporary {
+		b.WriteString("TEMP ")
+	}
+	b.WriteString("TABLE ")
+	if table.IsIfNotExists {
+		b.WriteString("IF NOT EXISTS ")
+	}
+	if table.Schema != "" {
+		b.WriteString(QuoteIdentifier(table.Schema))
+		b.WriteString(".")
+	}
+	b.WriteString(QuoteIdentifier(table.Name))
+	b.WriteString(" (\n")
+
+	var lines []string
+	for i := range table.Columns {
+		lines = append(lines, FormatColumn(&table.Columns[i]))
+	}
+	for i := range table.Constraints {
+		lines = append(lines, FormatTableConstraint(&table.Constraints[i]))
+	}
+	for i, line := range lines {
+		before, after := comments.before[i], comments.after[i]
+		if !inline(after) {
+			before, after = append(before, after...), nil
+		}
+		for _, comment := range before {
+			b.WriteString("  ")
+			b.WriteString(comment)
+			b.WriteString("\n")
+		}
+		b.WriteString("  ")
+		b.WriteString(line)
+		if i < len(lines)-1 {
+			b.WriteString(",")
+		}
+		for _, comment := range after {
+			b.WriteString(" ")
+			b.WriteString(comment)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(")")
+	if table.IsWithoutRowid {
+		b.WriteString(" WITHOUT ROWID")
+	}
+	if table.IsStrict {
+		if table.IsWithoutRowid {
+			b.WriteString(",")
+		}
+		b.WriteString(" STRICT")
+	}
+	b.WriteString(";")
+	return b.String()
+}
+
+// FormatExpr returns the text of expr, with parentheses where the
+// precedence of its operators requires them.
+func FormatExpr(expr *Expr) string {
+	var b strings.Builder
+	writeExpr(&b, expr, 0)
+	return b.String()
+}
+
+func exprPrecedence(expr *Expr) int {
+	switch expr.Kind {
+	case EXPR_UNARY:
+		if expr.Op == "NOT" {
+			return precNot
+		}
+		return precUnary
+	case EXPR_BINARY:
+		if prec, ok := binaryPrecedence[expr.Op]; ok {
+			return prec
+		}
+		switch expr.Op {
+		case "OR":
+			return precOr
+		case "AND":
+			return precAnd
+		}
+		return precEquality
+	case EXPR_POSTFIX, EXPR_BETWEEN, EXPR_IN:
+		return precEquality
+	case EXPR_COLLATE:
+		return precCollate
+	}
+	return precPrimary
+}
+
+// writeExpr writes expr, in parentheses if its operator binds less tightly
+// than min.
+func writeExpr(b *strings.Builder, expr *Expr, min int) {
+	prec := exprPrecedence(expr)
+	if prec < min {
+		b.WriteString("(")
+		writeExpr(b, expr, 0)
+		b.WriteString(")")
+		return
+	}
+
+	switch expr.Kind {
+	case EXPR_LITERAL, EXPR_VARIABLE:
+		b.WriteString(expr.Value)
+	case EXPR_COLUMN:
+		for _, name := range []string{expr.Schema, expr.Table} {
+			if name != "" {
+				b.WriteString(QuoteIdentifier(name))
+				b.WriteString(".")
+			}
+		}
+		b.WriteString(QuoteIdentifier(expr.Name))
+	case EXPR_UNARY:
+		b.WriteString(expr.Op)
+		if operand := expr.Args[0]; expr.Op == "NOT" || operand.Kind == EXPR_UNARY && operand.Op == "-" {
+			b.WriteString(" ")
+		}
+		writeExpr(b, expr.Args[0], prec)
+	case EXPR_BINARY:
+		writeExpr(b, expr.Args[0], prec)
+		b.WriteString(" ")
+		b.WriteString(expr.Op)
+		b.WriteString(" ")
+		writeExpr(b, expr.Args[1], prec+1)
+		if len(expr.Args) > 2 {
+			b.WriteString(" ESCAPE ")
+			writeExpr(b, expr.Args[2], precEscape)
+		}
+	case EXPR_POSTFIX:
+		writeExpr(b, expr.Args[0], prec)
+		b.WriteString(" ")
+		b.WriteString(expr.Op)
+	case EXPR_COLLATE:
+		writeExpr(b, expr.Args[0], prec)
+		b.WriteString(" COLLATE ")
+		b.WriteString(quoteWord(expr.Name, keywordID))
+	case EXPR_BETWEEN:
+		writeExpr(b, expr.Args[0], prec)
+		b.WriteString(" ")
+		b.WriteString(expr.Op)
+		b.WriteString(" ")
+		writeExpr(b, expr.Args[1], precComparison)
+		b.WriteString(" AND ")
+		writeExpr(b, expr.Args[2], precComparison)
+	case EXPR_IN:
+		writeExpr(b, expr.Args[0], prec)
+		b.WriteString(" ")
+		b.WriteString(expr.Op)
+		switch {
+		case expr.Select != "":
+			b.WriteString(" (")
+			b.WriteString(expr.Select)
+			b.WriteString(")")
+		case expr.Name != "":
+			b.WriteString(" ")
+			if expr.Schema != "" {
+				b.WriteString(QuoteIdentifier(expr.Schema))
+				b.WriteString(".")
+			}
+			b.WriteString(QuoteIdentifier(expr.Name))
+		default:
+			b.WriteString(" (")
+			writeExprList(b, expr.Args[1:])
+			b.WriteString(")")
+		}
+	case EXPR_FUNCTION:
+		b.WriteString(quoteWord(expr.Name, keywordName))
+		b.WriteString("(")
+		if expr.Star {
+			b.WriteString("*")
+		}
+		if expr.Distinct {
+			b.WriteString("DISTINCT ")
+		}
+		writeExprList(b, expr.Args)
+		b.WriteString(")")
+	case EXPR_CAST:
+		b.WriteString("CAST(")
+		writeExpr(b, expr.Args[0], 0)
+		b.WriteString(" AS ")
+		for i, word := range expr.Type.Words {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(quoteWord(word, keywordID))
+		}
+		for i, arg := range expr.Type.Args {
+			if i == 0 {
+				b.WriteString("(")
+			} else {
+				b.WriteString(", ")
+			}
+			b.WriteString(arg.String())
+		}
+		if len(expr.Type.Args) > 0 {
+			b.WriteString(")")
+		}
+		b.WriteString(")")
+	case EXPR_CASE:
+		b.WriteString("CASE")
+		if expr.Operand != nil {
+			b.WriteString(" ")
+			writeExpr(b, expr.Operand, 0)
+		}
+		for i := 0; i+1 < len(expr.Args); i += 2 {
+			b.WriteString(" WHEN ")
+			writeExpr(b, expr.Args[i], 0)
+			b.WriteString(" THEN ")
+			writeExpr(b, expr.Args[i+1], 0)
+		}
+		if expr.Else != nil {
+			b.WriteString(" ELSE ")
+			writeExpr(b, expr.Else, 0)
+		}
+		b.WriteString(" END")
+	case EXPR_VECTOR:
+		b.WriteString("(")
+		writeExprList(b, expr.Args)
+		b.WriteString(")")
+	case EXPR_SUBQUERY:
+		if expr.Op != "" {
+			b.WriteString(expr.Op)
+			b.WriteString(" ")
+		}
+		b.WriteString("(")
+		b.WriteString(expr.Select)
+		b.WriteString(")")
+	case EXPR_RAISE:
+		b.WriteString("RAISE(")
+		b.WriteString(expr.Op)
+		if len(expr.Args) > 0 {
+			b.WriteString(", ")
+			writeExpr(b, expr.Args[0], 0)
+		}
+		b.WriteString(")")
+	}
+}
+
+func writeExprList(b *strings.Builder, list []*Expr) {
+	for i, expr := range list {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		writeExpr(b, expr, 0)
+	}
+}
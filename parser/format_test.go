@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	const ddl = `
+	create temp table if not exists "order" (
+	 id integer primary key desc on conflict replace autoincrement,
+	 name varchar(32) not null unique collate nocase,
+	 "group" integer references groups(id) on delete cascade deferrable initially deferred,
+	 constraint pk unique (name collate nocase asc, id) on conflict ignore,
+	 foreign key (id, name) references people (id, name) on update set null match simple
+	) without rowid;`
+
+	table, errCode := ParseTable(ddl, 0)
+	assert.Equal(t, ERROR_NONE, errCode)
+
+	formatted := Format(table)
+	assert.Equal(t, `CREATE TEMP TABLE IF NOT EXISTS "order" (
+  id integer PRIMARY KEY DESC ON CONFLICT REPLACE AUTOINCREMENT,
+  name varchar(32) NOT NULL UNIQUE COLLATE nocase,
+  "group" integer REFERENCES "groups" (id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+  CONSTRAINT pk UNIQUE (name COLLATE nocase ASC, id) ON CONFLICT IGNORE,
+  FOREIGN KEY (id, name) REFERENCES people (id, name) ON UPDATE SET NULL MATCH simple
+) WITHOUT ROWID;`, formatted)
+
+	reparsed, errCode := ParseTable(formatted, 0)
+	assert.Equal(t, ERROR_NONE, errCode)
+	assert.Equal(t, clearSpans(table), clearSpans(reparsed))
+}
+
+// clearSpans zeroes the offsets of the column constraints of table, which
+// differ between a statement and its formatted text.
+func clearSpans(table *Table) *Table {
+	for i := range table.Columns {
+		for j := range table.Columns[i].Constraints {
+			table.Columns[i].Constraints[j].Start = 0
+			table.Columns[i].Constraints[j].End = 0
+		}
+	}
+	return table
+}
+
+func TestFormatWithComments(t *testing.T) {
+	const ddl = `-- users of the site
+CREATE TABLE Users ( -- lint:ignore snake-case-names
+  -- the rowid
+  id INTEGER PRIMARY KEY, /* kept */ -- after the comma
+  name TEXT /* inside */ NOT NULL,
+  /* one */ /* two */
+  CHECK (name <> '') -- last
+) /* options */;`
+
+	table, errCode := ParseTable(ddl, 0)
+	if !assert.Equal(t, ERROR_NONE, errCode) {
+		return
+	}
+	formatted := FormatWithComments(table, ddl)
+	assert.Equal(t, `-- users of the site
+-- lint:ignore snake-case-names
+/* options */
+CREATE TABLE Users (
+  -- the rowid
+  id INTEGER PRIMARY KEY, /* kept */ -- after the comma
+  name TEXT NOT NULL, /* inside */
+  /* one */
+  /* two */
+  CHECK (name <> '') -- last
+);`, formatted)
+	assert.Equal(t, formatted, FormatWithComments(table, formatted))
+
+	// A line comment cannot be followed on its line.
+	table, _ = ParseTable("CREATE TABLE t (a INT -- one\n/* two */, b INT)", 0)
+	assert.Equal(t, "CREATE TABLE t (\n  -- one\n  /* two */\n  a INT,\n  b INT\n);",
+		FormatWithComments(table, "CREATE TABLE t (a INT -- one\n/* two */, b INT)"))
+}
+
+func TestFormatColumnConstraints(t *testing.T) {
+	table, errCode := ParseTable(`CREATE TABLE t (
+	 a INTEGER CONSTRAINT nn NOT NULL ON CONFLICT FAIL CONSTRAINT pk PRIMARY KEY DESC,
+	 b TEXT NULL COLLATE nocase UNIQUE COLLATE binary
+	)`, 0)
+	if !assert.Equal(t, ERROR_NONE, errCode) {
+		return
+	}
+	assert.Equal(t, "a INTEGER CONSTRAINT nn NOT NULL ON CONFLICT FAIL CONSTRAINT pk PRIMARY KEY DESC",
+		FormatColumn(&table.Columns[0]))
+	assert.Equal(t, "b TEXT NULL COLLATE nocase UNIQUE COLLATE binary", FormatColumn(&table.Columns[1]))
+
+	// Without Constraints, the summary fields are written.
+	column := table.Columns[0]
+	column.Constraints = nil
+	assert.Equal(t, "a INTEGER CONSTRAINT pk PRIMARY KEY DESC NOT NULL ON CONFLICT FAIL", FormatColumn(&column))
+}
+
+func TestFormatTypeName(t *testing.T) {
+	table, errCode := ParseTable(`CREATE TABLE t (a "my type" [key] (10, -2), b "select")`, 0)
+	if !assert.Equal(t, ERROR_NONE, errCode) {
+		return
+	}
+	assert.Equal(t, `a "my type" key(10, -2)`, FormatColumn(&table.Columns[0]))
+	assert.Equal(t, `b "select"`, FormatColumn(&table.Columns[1]))
+
+	reparsed, errCode := ParseTable(Format(table), 0)
+	if assert.Equal(t, ERROR_NONE, errCode) {
+		assert.Equal(t, "my type key", reparsed.Columns[0].Type)
+		assert.Equal(t, "select", reparsed.Columns[1].Type)
+	}
+}
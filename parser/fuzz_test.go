@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"os"
+	"testing"
+)
+
+// addSeeds adds the statements of testdata/sqlite_tests.sql, and inputs
+// that used to panic, to the seed corpus of f. The seeds also run as
+// ordinary tests, without -fuzz.
+func addSeeds(f *testing.F) {
+	data, err := os.ReadFile("testdata/sqlite_tests.sql")
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, statement := range SplitStatements(string(data)) {
+		f.Add(statement.Text)
+	}
+	for _, sql := range []string{
+		"",
+		"-",
+		"/",
+		`"`,
+		"[",
+		"CREATE TABLE A",
+		"CREATE TABLE t(a /",
+		`CREATE TABLE t(a "`,
+		"CREATE TABLE t(a /*",
+		"CREATE TABLE t(a INTEGER(",
+		"CREATE TABLE t(a é",
+		"CREATE TABLE é(ü TEXT)",
+		`CREATE TABLE t(a "ü" TEXT)`,
+		"CREATE INDEX i ON t(",
+		"CREATE TRIGGER t AFTER INSERT ON t BEGIN '",
+	} {
+		f.Add(sql)
+	}
+}
+
+func FuzzParseTable(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, sql string) {
+		table, errCode := ParseTable(sql, 0)
+		if errCode != ERROR_NONE || table == nil {
+			return
+		}
+		Validate(table)
+		Format(table)
+	})
+}
+
+func FuzzParseSchema(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, sql string) {
+		ParseSchema(sql)
+		ParseIndex(sql)
+		ParseView(sql)
+		ParseTrigger(sql)
+	})
+}
+
+// FuzzLexer checks that the lexer always moves forward and stays within
+// its buffer.
+func FuzzLexer(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, sql string) {
+		state := newState(sql)
+		for {
+			offset := state.offset
+			token := lexerNext(state)
+			if state.offset > len(state.buffer) {
+				t.Fatalf("offset %d past the end %d", state.offset, len(state.buffer))
+			}
+			if token == tokEOF || token == tokERROR {
+				return
+			}
+			if state.offset <= offset {
+				t.Fatalf("token %d at offset %d did not advance", token, offset)
+			}
+		}
+	})
+}
+
+// FuzzTokenizer checks that the tokens cover the input exactly, in order,
+// with consistent positions.
+func FuzzTokenizer(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, sql string) {
+		line, col, offset := 1, 1, 0
+		tokenizer := NewTokenizer(sql)
+		for token := tokenizer.Next(); token.Kind != TOKEN_EOF; token = tokenizer.Next() {
+			if token.Start != offset || token.End <= token.Start || token.Text != sql[token.Start:token.End] {
+				t.Fatalf("token %+v does not follow offset %d", token, offset)
+			}
+			if token.Line != line || token.Col != col {
+				t.Fatalf("token %+v is not at line %d column %d", token, line, col)
+			}
+			for _, r := range token.Text {
+				if col++; r == '\n' {
+					line, col = line+1, 1
+				}
+			}
+			offset = token.End
+		}
+		if offset != len(sql) {
+			t.Fatalf("tokens end at %d of %d", offset, len(sql))
+		}
+	})
+}
+
+// FuzzParseExpr checks that a parsed expression formats to text that parses
+// to the same text again.
+func FuzzParseExpr(f *testing.F) {
+	for _, sql := range []string{
+		"a + b * -c || 'x' COLLATE nocase",
+		"NOT a BETWEEN 1 AND 2 OR b IS NOT DISTINCT FROM ?1",
+		"x NOT IN (SELECT y FROM t) AND z IN (1, 2) AND (a, b) = (1, 2)",
+		"count(DISTINCT a) + CAST(b AS DECIMAL(10, -2)) + CASE WHEN a THEN 1 ELSE 2 END",
+		"a LIKE b ESCAPE '!' AND c ISNULL AND - -d",
+	} {
+		f.Add(sql)
+	}
+	f.Fuzz(func(t *testing.T, sql string) {
+		expr, errCode := ParseExpr(sql)
+		if errCode != ERROR_NONE {
+			return
+		}
+		formatted := FormatExpr(expr)
+		reparsed, errCode := ParseExpr(formatted)
+		if errCode != ERROR_NONE {
+			t.Fatalf("%q formatted as %q: %v", sql, formatted, errCode)
+		}
+		if again := FormatExpr(reparsed); again != formatted {
+			t.Fatalf("%q formatted as %q, then %q", sql, formatted, again)
+		}
+	})
+}
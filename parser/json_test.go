@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+const jsonDDL = `
+CREATE TABLE contact_groups (
+ contact_id integer PRIMARY KEY DESC ON CONFLICT REPLACE,
+ group_id integer NOT NULL REFERENCES groups (group_id) ON DELETE SET NULL DEFERRABLE INITIALLY DEFERRED,
+ UNIQUE (group_id COLLATE nocase ASC) ON CONFLICT IGNORE,
+ FOREIGN KEY (contact_id) REFERENCES contacts (contact_id) ON DELETE CASCADE ON UPDATE NO ACTION
+) WITHOUT ROWID;
+`
+
+func TestJSONRoundTrip(t *testing.T) {
+	table, errCode := ParseTable(jsonDDL, 0)
+	assert.Equal(t, ERROR_NONE, errCode)
+
+	data, err := json.Marshal(table)
+	assert.NoError(t, err)
+	for _, value := range []string{`"pk_order":"desc"`, `"pk_conflict_clause":"replace"`, `"on_delete":"set_null"`,
+		`"deferrable":"deferrable_initially_deferred"`, `"type":"unique"`, `"on_update":"no_action"`} {
+		assert.Contains(t, string(data), value)
+	}
+
+	var decoded Table
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, table, &decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"pk_order":"sideways"}`), &Column{}))
+	_, err = json.Marshal(Column{PkOrder: 42})
+	assert.Error(t, err)
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	table, errCode := ParseTable(jsonDDL, 0)
+	assert.Equal(t, ERROR_NONE, errCode)
+
+	data, err := yaml.Marshal(table)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "on_delete: cascade")
+
+	// YAML has no null list, so compare the re-encoded documents.
+	var decoded Table
+	assert.NoError(t, yaml.Unmarshal(data, &decoded))
+	encoded, err := yaml.Marshal(&decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(encoded))
+}
+
+func jsonFields(typ reflect.Type) []string {
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		fields = append(fields, strings.Split(typ.Field(i).Tag.Get("json"), ",")[0])
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func TestJSONSchemaCoversModel(t *testing.T) {
+	data, err := os.ReadFile("../schema/table.schema.json")
+	assert.NoError(t, err)
+
+	type object struct {
+		Properties map[string]json.RawMessage
+		Required   []string
+		Enum       []string
+	}
+	var schema struct {
+		object
+		Defs map[string]object `json:"$defs"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &schema))
+
+	objects := map[string]reflect.Type{
+		"":                  reflect.TypeOf(Table{}),
+		"column":            reflect.TypeOf(Column{}),
+		"table_constraint":  reflect.TypeOf(TableConstraint{}),
+		"foreign_key":       reflect.TypeOf(ForeignKey{}),
+		"indexed_column":    reflect.TypeOf(IdxColumn{}),
+		"type_name":         reflect.TypeOf(TypeName{}),
+		"signed_number":     reflect.TypeOf(SignedNumber{}),
+		"column_constraint": reflect.TypeOf(ColumnConstraint{}),
+		"expr":              reflect.TypeOf(Expr{}),
+	}
+	for name, typ := range objects {
+		def := schema.object
+		if name != "" {
+			def = schema.Defs[name]
+		}
+		var properties []string
+		for property := range def.Properties {
+			properties = append(properties, property)
+		}
+		sort.Strings(properties)
+		sort.Strings(def.Required)
+		assert.Equal(t, jsonFields(typ), properties, typ.Name())
+		assert.Equal(t, jsonFields(typ), def.Required, typ.Name())
+	}
+
+	enums := map[string][]string{
+		"conflict_clause":        conflictClauseNames.text,
+		"order_clause":           orderClauseNames.text,
+		"fk_action":              fkActionNames.text,
+		"fk_def_type":            fkDefTypeNames.text,
+		"constraint_type":        constraintTypeNames.text,
+		"column_constraint_type": columnConstraintTypeNames.text,
+		"expr_kind":              exprKindNames.text,
+	}
+	for name, values := range enums {
+		assert.Equal(t, values, schema.Defs[name].Enum, name)
+	}
+}
@@ -0,0 +1,91 @@
+package parser
+
+//go:generate go run mkkeywords.go
+
+// keyword is an entry of keywordTable. token is the lexer token of the
+// keywords used by the CREATE TABLE grammar and tokKEYWORD for the others;
+// usage tells where a keyword may be used as a name without quotes.
+type keyword struct {
+	name  string
+	token tokenT
+	usage uint8
+}
+
+// The places where SQLite's fallback rule accepts a keyword as a name.
+const (
+	// keywordName is the name of a table, column, constraint or index.
+	keywordName uint8 = 1 << iota
+	// keywordID is a word of a type name or a collation name.
+	keywordID
+	// keywordValue is a DEFAULT value.
+	keywordValue
+	// keywordColumn is a column of a key column list, which SQLite parses
+	// as an expression.
+	keywordColumn
+
+	// wordUsage is the usage of a word that is not a keyword.
+	wordUsage = keywordName | keywordID | keywordValue | keywordColumn
+)
+
+func upperASCII(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// keywordHash must match keywordHash in mkkeywords.go, applied to the
+// upper-case s.
+func keywordHash(s string) int {
+	h := uint32(len(s))
+	for i := 0; i < len(s); i++ {
+		h = h*31 + uint32(upperASCII(s[i]))
+	}
+	return int(h % keywordHashSize)
+}
+
+// lookupKeyword returns the keyword s, ignoring case, or nil. It does not
+// allocate.
+func lookupKeyword(s string) *keyword {
+	if len(s) < keywordMinLength || len(s) > keywordMaxLength {
+		return nil
+	}
+	for i := keywordHashTable[keywordHash(s)]; i != 0; i = keywordNextTable[i-1] {
+		k := &keywordTable[i-1]
+		if len(k.name) != len(s) {
+			continue
+		}
+		j := 0
+		for j < len(s) && upperASCII(s[j]) == k.name[j] {
+			j++
+		}
+		if j == len(s) {
+			return k
+		}
+	}
+	return nil
+}
+
+// IsKeyword reports whether s is one of SQLite's keywords, ignoring case.
+func IsKeyword(s string) bool {
+	return lookupKeyword(s) != nil
+}
+
+// IsReservedKeyword reports whether s is one of SQLite's keywords that
+// cannot be used as a name without quotes. The others, such as KEY, ACTION
+// or MATCH, are accepted as names through SQLite's fallback rule, though not
+// everywhere: IF is not a table name, where it would start IF NOT EXISTS,
+// and the join keywords such as LEFT are not type or collation names.
+func IsReservedKeyword(s string) bool {
+	k := lookupKeyword(s)
+	return k != nil && k.usage == 0
+}
+
+// Keywords returns SQLite's keywords in alphabetical order.
+func Keywords() []string {
+	names := make([]string, len(keywordTable))
+	for i := range keywordTable {
+		names[i] = keywordTable[i].name
+	}
+	return names
+}
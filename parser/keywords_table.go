@@ -0,0 +1,199 @@
+// Code generated by mkkeywords.go; DO NOT EDIT.
+
+package parser
+
+const (
+	keywordHashSize  = 305
+	keywordMinLength = 2
+	keywordMaxLength = 17
+)
+
+var keywordTable = [147]keyword{
+	{"ABORT", tokABORT, keywordName | keywordID | keywordValue | keywordColumn},
+	{"ACTION", tokACTION, keywordName | keywordID | keywordValue | keywordColumn},
+	{"ADD", tokKEYWORD, 0},
+	{"AFTER", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"ALL", tokKEYWORD, 0},
+	{"ALTER", tokKEYWORD, 0},
+	{"ALWAYS", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"ANALYZE", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"AND", tokKEYWORD, 0},
+	{"AS", tokAS, 0},
+	{"ASC", tokASC, keywordName | keywordID | keywordValue | keywordColumn},
+	{"ATTACH", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"AUTOINCREMENT", tokAUTOINCREMENT, 0},
+	{"BEFORE", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"BEGIN", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"BETWEEN", tokKEYWORD, 0},
+	{"BY", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"CASCADE", tokCASCADE, keywordName | keywordID | keywordValue | keywordColumn},
+	{"CASE", tokKEYWORD, 0},
+	{"CAST", tokKEYWORD, keywordName | keywordID | keywordValue},
+	{"CHECK", tokCHECK, 0},
+	{"COLLATE", tokCOLLATE, 0},
+	{"COLUMN", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"COMMIT", tokKEYWORD, 0},
+	{"CONFLICT", tokCONFLICT, keywordName | keywordID | keywordValue | keywordColumn},
+	{"CONSTRAINT", tokCONSTRAINT, 0},
+	{"CREATE", tokCREATE, 0},
+	{"CROSS", tokKEYWORD, keywordName | keywordColumn},
+	{"CURRENT", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"CURRENT_DATE", tokKEYWORD, keywordName | keywordID | keywordValue},
+	{"CURRENT_TIME", tokKEYWORD, keywordName | keywordID | keywordValue},
+	{"CURRENT_TIMESTAMP", tokKEYWORD, keywordName | keywordID | keywordValue},
+	{"DATABASE", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"DEFAULT", tokDEFAULT, 0},
+	{"DEFERRABLE", tokDEFERRABLE, 0},
+	{"DEFERRED", tokDEFERRED, keywordName | keywordID | keywordValue | keywordColumn},
+	{"DELETE", tokDELETE, 0},
+	{"DESC", tokDESC, keywordName | keywordID | keywordValue | keywordColumn},
+	{"DETACH", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"DISTINCT", tokKEYWORD, 0},
+	{"DO", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"DROP", tokKEYWORD, 0},
+	{"EACH", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"ELSE", tokKEYWORD, 0},
+	{"END", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"ESCAPE", tokKEYWORD, 0},
+	{"EXCEPT", tokKEYWORD, 0},
+	{"EXCLUDE", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"EXCLUSIVE", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"EXISTS", tokEXISTS, 0},
+	{"EXPLAIN", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"FAIL", tokFAIL, keywordName | keywordID | keywordValue | keywordColumn},
+	{"FILTER", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"FIRST", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"FOLLOWING", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"FOR", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"FOREIGN", tokFOREIGN, 0},
+	{"FROM", tokKEYWORD, 0},
+	{"FULL", tokKEYWORD, keywordName | keywordColumn},
+	{"GENERATED", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"GLOB", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"GROUP", tokKEYWORD, 0},
+	{"GROUPS", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"HAVING", tokKEYWORD, 0},
+	{"IF", tokIF, keywordName | keywordID | keywordValue | keywordColumn},
+	{"IGNORE", tokIGNORE, keywordName | keywordID | keywordValue | keywordColumn},
+	{"IMMEDIATE", tokIMMEDIATE, keywordName | keywordID | keywordValue | keywordColumn},
+	{"IN", tokKEYWORD, 0},
+	{"INDEX", tokKEYWORD, 0},
+	{"INDEXED", tokKEYWORD, keywordName | keywordValue | keywordColumn},
+	{"INITIALLY", tokINITIALLY, keywordName | keywordID | keywordValue | keywordColumn},
+	{"INNER", tokKEYWORD, keywordName | keywordColumn},
+	{"INSERT", tokKEYWORD, 0},
+	{"INSTEAD", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"INTERSECT", tokKEYWORD, 0},
+	{"INTO", tokKEYWORD, 0},
+	{"IS", tokKEYWORD, 0},
+	{"ISNULL", tokKEYWORD, 0},
+	{"JOIN", tokKEYWORD, 0},
+	{"KEY", tokKEY, keywordName | keywordID | keywordValue | keywordColumn},
+	{"LAST", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"LEFT", tokKEYWORD, keywordName | keywordColumn},
+	{"LIKE", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"LIMIT", tokKEYWORD, 0},
+	{"MATCH", tokMATCH, keywordName | keywordID | keywordValue | keywordColumn},
+	{"MATERIALIZED", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"NATURAL", tokKEYWORD, keywordName | keywordColumn},
+	{"NO", tokNO, keywordName | keywordID | keywordValue | keywordColumn},
+	{"NOT", tokNOT, 0},
+	{"NOTHING", tokKEYWORD, 0},
+	{"NOTNULL", tokKEYWORD, 0},
+	{"NULL", tokNULL, 0},
+	{"NULLS", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"OF", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"OFFSET", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"ON", tokON, 0},
+	{"OR", tokKEYWORD, 0},
+	{"ORDER", tokKEYWORD, 0},
+	{"OTHERS", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"OUTER", tokKEYWORD, keywordName | keywordColumn},
+	{"OVER", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"PARTITION", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"PLAN", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"PRAGMA", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"PRECEDING", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"PRIMARY", tokPRIMARY, 0},
+	{"QUERY", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"RAISE", tokKEYWORD, keywordName | keywordID | keywordValue},
+	{"RANGE", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"RECURSIVE", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"REFERENCES", tokREFERENCES, 0},
+	{"REGEXP", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"REINDEX", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"RELEASE", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"RENAME", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"REPLACE", tokREPLACE, keywordName | keywordID | keywordValue | keywordColumn},
+	{"RESTRICT", tokRESTRICT, keywordName | keywordID | keywordValue | keywordColumn},
+	{"RETURNING", tokKEYWORD, 0},
+	{"RIGHT", tokKEYWORD, keywordName | keywordColumn},
+	{"ROLLBACK", tokROLLBACK, keywordName | keywordID | keywordValue | keywordColumn},
+	{"ROW", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"ROWS", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"SAVEPOINT", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"SELECT", tokKEYWORD, 0},
+	{"SET", tokSET, 0},
+	{"TABLE", tokTABLE, 0},
+	{"TEMP", tokTEMP, keywordName | keywordID | keywordValue | keywordColumn},
+	{"TEMPORARY", tokTEMP, keywordName | keywordID | keywordValue | keywordColumn},
+	{"THEN", tokKEYWORD, 0},
+	{"TIES", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"TO", tokKEYWORD, 0},
+	{"TRANSACTION", tokKEYWORD, 0},
+	{"TRIGGER", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"UNBOUNDED", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"UNION", tokKEYWORD, 0},
+	{"UNIQUE", tokUNIQUE, 0},
+	{"UPDATE", tokUPDATE, 0},
+	{"USING", tokKEYWORD, 0},
+	{"VACUUM", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"VALUES", tokKEYWORD, 0},
+	{"VIEW", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"VIRTUAL", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"WHEN", tokKEYWORD, 0},
+	{"WHERE", tokKEYWORD, 0},
+	{"WINDOW", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"WITH", tokKEYWORD, keywordName | keywordID | keywordValue | keywordColumn},
+	{"WITHOUT", tokWITHOUT, keywordName | keywordID | keywordValue | keywordColumn},
+}
+
+// keywordHashTable holds 1 + the index of the first keyword of each hash,
+// or 0.
+var keywordHashTable = [305]uint8{
+	0, 0, 0, 0, 0, 70, 0, 0, 135, 40, 0, 69, 0, 0, 0, 0,
+	0, 0, 124, 73, 0, 0, 0, 0, 0, 33, 0, 0, 114, 132, 131, 0,
+	51, 103, 0, 0, 8, 62, 0, 140, 0, 0, 91, 0, 141, 0, 78, 47,
+	0, 0, 122, 0, 0, 0, 28, 63, 137, 0, 0, 0, 34, 136, 43, 0,
+	0, 0, 0, 15, 84, 92, 0, 0, 0, 32, 0, 0, 0, 0, 60, 106,
+	21, 0, 0, 0, 0, 138, 0, 0, 0, 0, 146, 0, 17, 0, 128, 0,
+	0, 133, 79, 117, 0, 87, 105, 42, 0, 109, 0, 36, 93, 0, 101, 0,
+	54, 0, 53, 0, 0, 0, 0, 0, 48, 1, 0, 25, 0, 0, 61, 0,
+	0, 0, 0, 0, 0, 0, 0, 7, 0, 113, 0, 0, 0, 90, 49, 19,
+	41, 0, 44, 0, 11, 88, 0, 27, 55, 52, 0, 0, 0, 145, 20, 144,
+	72, 0, 0, 0, 0, 0, 0, 0, 0, 22, 0, 94, 0, 0, 30, 0,
+	71, 66, 45, 96, 0, 0, 81, 97, 0, 0, 0, 129, 76, 0, 0, 0,
+	0, 0, 0, 143, 80, 12, 0, 0, 139, 86, 0, 0, 120, 0, 0, 58,
+	0, 111, 29, 123, 0, 0, 102, 115, 121, 0, 0, 0, 50, 0, 0, 0,
+	112, 0, 107, 110, 0, 0, 0, 16, 0, 0, 134, 57, 0, 0, 130, 100,
+	39, 0, 0, 0, 0, 5, 0, 108, 46, 0, 14, 59, 24, 0, 125, 0,
+	0, 119, 0, 0, 0, 0, 0, 0, 0, 104, 31, 0, 82, 56, 142, 0,
+	0, 0, 118, 0, 0, 0, 0, 0, 126, 0, 0, 0, 0, 0, 0, 83,
+	147, 0, 65, 0, 0, 0, 3, 0, 0, 0, 68, 127, 95, 0, 0, 77,
+	0,
+}
+
+// keywordNextTable holds 1 + the index of the next keyword with the same
+// hash, or 0.
+var keywordNextTable = [147]uint8{
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 26, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 0,
+	0, 0, 0, 0, 0, 0, 6, 4, 0, 0, 0, 0, 0, 2, 0, 35,
+	0, 0, 74, 0, 0, 75, 0, 0, 0, 0, 0, 0, 0, 0, 64, 0,
+	13, 0, 0, 0, 0, 0, 0, 0, 0, 0, 38, 0, 98, 23, 0, 0,
+	18, 0, 37, 0, 0, 0, 0, 99, 0, 85, 0, 0, 0, 0, 9, 0,
+	0, 0, 0, 89, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 67, 0,
+	0, 0, 116,
+}
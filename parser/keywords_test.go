@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsKeyword(t *testing.T) {
+	assert.Len(t, keywordTable, 147)
+	for _, k := range keywordTable {
+		assert.True(t, IsKeyword(k.name), k.name)
+		assert.True(t, IsKeyword(strings.ToLower(k.name)), k.name)
+		assert.Equal(t, k.usage == 0, IsReservedKeyword(k.name), k.name)
+	}
+	for _, word := range []string{"", "x", "rowid", "users", "CREATED", "TEMPO", "current_dat", "é"} {
+		assert.False(t, IsKeyword(word), word)
+	}
+
+	assert.True(t, IsReservedKeyword("select"))
+	assert.True(t, IsReservedKeyword("Table"))
+	assert.False(t, IsReservedKeyword("key"))
+	assert.False(t, IsReservedKeyword("action"))
+	assert.False(t, IsReservedKeyword("rowid"))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		IsKeyword("current_timestamp")
+		IsReservedKeyword("Nothing")
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestKeywordNames(t *testing.T) {
+	for _, name := range Keywords() {
+		_, errCode := ParseTable("CREATE TABLE t ("+name+" INT)", 0)
+		assert.Equal(t, IsReservedKeyword(name), errCode != ERROR_NONE, name)
+	}
+
+	table, errCode := ParseTable(`CREATE TABLE main.settings (
+		key TEXT CONSTRAINT replace NOT NULL COLLATE nocase,
+		action TEXT DEFAULT indexed,
+		match INT REFERENCES temp (key) MATCH simple,
+		rowid INT,
+		left,
+		CONSTRAINT action PRIMARY KEY (key, left),
+		FOREIGN KEY (action) REFERENCES view (trigger)
+	)`, 0)
+	if assert.Equal(t, ERROR_NONE, errCode) {
+		assert.Equal(t, "main", table.Schema)
+		assert.Equal(t, "settings", table.Name)
+		var names []string
+		for _, column := range table.Columns {
+			names = append(names, column.Name)
+		}
+		assert.Equal(t, []string{"key", "action", "match", "rowid", "left"}, names)
+		assert.Equal(t, "replace", table.Columns[0].ConstraintName)
+		assert.Equal(t, "indexed", table.Columns[1].DefaultExpr)
+		assert.Equal(t, "temp", table.Columns[2].ForeignKeyClause.Table)
+		assert.Equal(t, "action", table.Constraints[0].Name)
+		assert.Equal(t, "left", table.Constraints[0].IndexedColumns[1].Name)
+		assert.Equal(t, "view", table.Constraints[1].ForeignKeyClause.Table)
+	}
+
+	for _, sql := range []string{
+		// Reserved keywords need quotes.
+		"CREATE TABLE t (unique TEXT)",
+		"CREATE TABLE select (x)",
+		// IF would start IF NOT EXISTS.
+		"CREATE TABLE if (x)",
+		"CREATE TABLE main.if (x)",
+		// Join keywords are not type names, and CAST starts an expression.
+		"CREATE TABLE t (x left)",
+		"CREATE TABLE t (cast, PRIMARY KEY (cast))",
+	} {
+		_, errCode := ParseTable(sql, 0)
+		assert.Equal(t, ERROR_SYNTAX, errCode, sql)
+	}
+
+	// GENERATED is a type name unless GENERATED ALWAYS starts a generated
+	// column.
+	table, errCode = ParseTable("CREATE TABLE t (x INT GENERATED)", 0)
+	if assert.Equal(t, ERROR_NONE, errCode) {
+		assert.Equal(t, "INT GENERATED", table.Columns[0].Type)
+	}
+
+	index, errCode := ParseIndex("CREATE INDEX key ON action (match, left DESC)")
+	if assert.Equal(t, ERROR_NONE, errCode) {
+		assert.Equal(t, "key", index.Name)
+		assert.Equal(t, "action", index.Table)
+		assert.Equal(t, "left", index.Columns[1].Name)
+	}
+}
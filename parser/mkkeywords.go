@@ -0,0 +1,220 @@
+//go:build ignore
+
+// mkkeywords writes keywords_table.go, the keyword table of the lexer: the
+// 147 keywords of SQLite 3.41, the lexer token of each and where SQLite's
+// %fallback ID rule lets it be used as a name, with a hash table for lookups
+// that do not allocate. Run it with go generate.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+// keywords is SQLite's keyword list, https://sqlite.org/lang_keywords.html.
+const keywords = `
+	ABORT ACTION ADD AFTER ALL ALTER ALWAYS ANALYZE AND AS ASC ATTACH
+	AUTOINCREMENT BEFORE BEGIN BETWEEN BY CASCADE CASE CAST CHECK COLLATE
+	COLUMN COMMIT CONFLICT CONSTRAINT CREATE CROSS CURRENT CURRENT_DATE
+	CURRENT_TIME CURRENT_TIMESTAMP DATABASE DEFAULT DEFERRABLE DEFERRED
+	DELETE DESC DETACH DISTINCT DO DROP EACH ELSE END ESCAPE EXCEPT EXCLUDE
+	EXCLUSIVE EXISTS EXPLAIN FAIL FILTER FIRST FOLLOWING FOR FOREIGN FROM
+	FULL GENERATED GLOB GROUP GROUPS HAVING IF IGNORE IMMEDIATE IN INDEX
+	INDEXED INITIALLY INNER INSERT INSTEAD INTERSECT INTO IS ISNULL JOIN KEY
+	LAST LEFT LIKE LIMIT MATCH MATERIALIZED NATURAL NO NOT NOTHING NOTNULL
+	NULL NULLS OF OFFSET ON OR ORDER OTHERS OUTER OVER PARTITION PLAN PRAGMA
+	PRECEDING PRIMARY QUERY RAISE RANGE RECURSIVE REFERENCES REGEXP REINDEX
+	RELEASE RENAME REPLACE RESTRICT RETURNING RIGHT ROLLBACK ROW ROWS
+	SAVEPOINT SELECT SET TABLE TEMP TEMPORARY THEN TIES TO TRANSACTION
+	TRIGGER UNBOUNDED UNION UNIQUE UPDATE USING VACUUM VALUES VIEW VIRTUAL
+	WHEN WHERE WINDOW WITH WITHOUT`
+
+// fallback lists the keywords of the %fallback ID rule of parse.y, which
+// SQLite accepts wherever it expects an identifier, and FILTER, OVER and
+// WINDOW, which its tokenizer only treats as keywords before a window
+// clause.
+const fallback = `
+	ABORT ACTION AFTER ALWAYS ANALYZE ASC ATTACH BEFORE BEGIN BY CASCADE
+	CAST COLUMN CONFLICT CURRENT CURRENT_DATE CURRENT_TIME
+	CURRENT_TIMESTAMP DATABASE DEFERRED DESC DETACH DO EACH END EXCLUDE
+	EXCLUSIVE EXPLAIN FAIL FILTER FIRST FOLLOWING FOR GENERATED GLOB
+	GROUPS IF IGNORE IMMEDIATE INITIALLY INSTEAD KEY LAST
+	LIKE MATCH MATERIALIZED NO NULLS OF OFFSET OTHERS OVER
+	PARTITION PLAN PRAGMA PRECEDING QUERY RAISE RANGE RECURSIVE REGEXP
+	REINDEX RELEASE RENAME REPLACE RESTRICT ROLLBACK ROW ROWS
+	SAVEPOINT TEMP TEMPORARY TIES TRIGGER UNBOUNDED VACUUM VIEW VIRTUAL
+	WINDOW WITH WITHOUT`
+
+// The grammar also accepts INDEXED where it reads an id, such as a DEFAULT
+// value, but not where it reads ids, such as a type name, and the join
+// keywords where it reads a name (nm) or a column in an expression.
+const (
+	indexed      = `INDEXED`
+	joinKeywords = `CROSS FULL INNER LEFT NATURAL OUTER RIGHT`
+)
+
+// notColumns lists the fallback keywords that start an expression, and so
+// are not column names in the key column lists SQLite parses as expressions.
+const notColumns = `CAST CURRENT_DATE CURRENT_TIME CURRENT_TIMESTAMP RAISE`
+
+// tokens maps the keywords of the CREATE TABLE grammar to their lexer
+// tokens. Every other keyword is lexed as tokKEYWORD.
+var tokens = map[string]string{
+	"ABORT":         "tokABORT",
+	"ACTION":        "tokACTION",
+	"AS":            "tokAS",
+	"ASC":           "tokASC",
+	"AUTOINCREMENT": "tokAUTOINCREMENT",
+	"CASCADE":       "tokCASCADE",
+	"CHECK":         "tokCHECK",
+	"COLLATE":       "tokCOLLATE",
+	"CONFLICT":      "tokCONFLICT",
+	"CONSTRAINT":    "tokCONSTRAINT",
+	"CREATE":        "tokCREATE",
+	"DEFAULT":       "tokDEFAULT",
+	"DEFERRABLE":    "tokDEFERRABLE",
+	"DEFERRED":      "tokDEFERRED",
+	"DELETE":        "tokDELETE",
+	"DESC":          "tokDESC",
+	"EXISTS":        "tokEXISTS",
+	"FAIL":          "tokFAIL",
+	"FOREIGN":       "tokFOREIGN",
+	"IF":            "tokIF",
+	"IGNORE":        "tokIGNORE",
+	"IMMEDIATE":     "tokIMMEDIATE",
+	"INITIALLY":     "tokINITIALLY",
+	"KEY":           "tokKEY",
+	"MATCH":         "tokMATCH",
+	"NO":            "tokNO",
+	"NOT":           "tokNOT",
+	"NULL":          "tokNULL",
+	"ON":            "tokON",
+	"PRIMARY":       "tokPRIMARY",
+	"REFERENCES":    "tokREFERENCES",
+	"REPLACE":       "tokREPLACE",
+	"RESTRICT":      "tokRESTRICT",
+	"ROLLBACK":      "tokROLLBACK",
+	"SET":           "tokSET",
+	"TABLE":         "tokTABLE",
+	"TEMP":          "tokTEMP",
+	"TEMPORARY":     "tokTEMP",
+	"UNIQUE":        "tokUNIQUE",
+	"UPDATE":        "tokUPDATE",
+	"WITHOUT":       "tokWITHOUT",
+}
+
+// keywordHash must match keywordHash in keywords.go.
+func keywordHash(s string, size int) int {
+	h := uint32(len(s))
+	for i := 0; i < len(s); i++ {
+		h = h*31 + uint32(s[i])
+	}
+	return int(h % uint32(size))
+}
+
+func main() {
+	names := strings.Fields(keywords)
+	sort.Strings(names)
+	usage := map[string][]string{}
+	for _, name := range strings.Fields(fallback) {
+		usage[name] = []string{"keywordName", "keywordID", "keywordValue", "keywordColumn"}
+	}
+	for _, name := range strings.Fields(indexed) {
+		usage[name] = []string{"keywordName", "keywordValue", "keywordColumn"}
+	}
+	for _, name := range strings.Fields(joinKeywords) {
+		usage[name] = []string{"keywordName", "keywordColumn"}
+	}
+	for _, name := range strings.Fields(notColumns) {
+		usage[name] = remove(usage[name], "keywordColumn")
+	}
+
+	// Use the smallest table whose longest chain has at most two keywords.
+	var size int
+	var hash, next []int
+	for size = len(names); size < 4*len(names); size++ {
+		hash, next = make([]int, size), make([]int, len(names))
+		longest := 0
+		for i, name := range names {
+			h := keywordHash(name, size)
+			next[i], hash[h] = hash[h], i+1
+			chain := 0
+			for j := hash[h]; j != 0; j = next[j-1] {
+				chain++
+			}
+			if chain > longest {
+				longest = chain
+			}
+		}
+		if longest <= 2 {
+			break
+		}
+	}
+
+	minLength, maxLength := len(names[0]), 0
+	for _, name := range names {
+		if len(name) < minLength {
+			minLength = len(name)
+		}
+		if len(name) > maxLength {
+			maxLength = len(name)
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by mkkeywords.go; DO NOT EDIT.\n\npackage parser\n\n")
+	fmt.Fprintf(&b, "const (\n\tkeywordHashSize = %d\n\tkeywordMinLength = %d\n\tkeywordMaxLength = %d\n)\n\n", size, minLength, maxLength)
+	fmt.Fprintf(&b, "var keywordTable = [%d]keyword{\n", len(names))
+	for _, name := range names {
+		token := tokens[name]
+		if token == "" {
+			token = "tokKEYWORD"
+		}
+		flags := strings.Join(usage[name], " | ")
+		if flags == "" {
+			flags = "0"
+		}
+		fmt.Fprintf(&b, "\t{%q, %s, %s},\n", name, token, flags)
+	}
+	fmt.Fprintf(&b, "}\n\n")
+	fmt.Fprintf(&b, "// keywordHashTable holds 1 + the index of the first keyword of each hash,\n// or 0.\n")
+	writeTable(&b, "keywordHashTable", hash)
+	fmt.Fprintf(&b, "// keywordNextTable holds 1 + the index of the next keyword with the same\n// hash, or 0.\n")
+	writeTable(&b, "keywordNextTable", next)
+
+	source, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("keywords_table.go", source, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func remove(list []string, value string) []string {
+	var result []string
+	for _, s := range list {
+		if s != value {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func writeTable(b *bytes.Buffer, name string, values []int) {
+	fmt.Fprintf(b, "var %s = [%d]uint8{", name, len(values))
+	for i, value := range values {
+		if i%16 == 0 {
+			b.WriteString("\n\t")
+		} else {
+			b.WriteString(" ")
+		}
+		fmt.Fprintf(b, "%d,", value)
+	}
+	fmt.Fprintf(b, "\n}\n\n")
+}
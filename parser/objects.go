@@ -0,0 +1,366 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Index is a parsed CREATE INDEX statement.
+type Index struct {
+	Name          string      `json:"name" yaml:"name"`
+	Schema        string      `json:"schema" yaml:"schema"`
+	Table         string      `json:"table" yaml:"table"`
+	IsUnique      bool        `json:"is_unique" yaml:"is_unique"`
+	IsIfNotExists bool        `json:"is_if_not_exists" yaml:"is_if_not_exists"`
+	Columns       []IdxColumn `json:"columns" yaml:"columns"`
+	Where         string      `json:"where" yaml:"where"`
+}
+
+// View is a parsed CREATE VIEW statement. Select is the text of the select
+// statement, which is not parsed.
+type View struct {
+	Name          string   `json:"name" yaml:"name"`
+	Schema        string   `json:"schema" yaml:"schema"`
+	IsTemporary   bool     `json:"is_temporary" yaml:"is_temporary"`
+	IsIfNotExists bool     `json:"is_if_not_exists" yaml:"is_if_not_exists"`
+	Columns       []string `json:"columns" yaml:"columns"`
+	Select        string   `json:"select" yaml:"select"`
+}
+
+// Trigger is a parsed CREATE TRIGGER statement. Timing is "BEFORE", "AFTER",
+// "INSTEAD OF" or empty, Event is "DELETE", "INSERT" or "UPDATE", and Columns
+// lists the columns of an UPDATE OF trigger. When and Body are kept as text,
+// Body from BEGIN to END inclusive.
+type Trigger struct {
+	Name          string   `json:"name" yaml:"name"`
+	Schema        string   `json:"schema" yaml:"schema"`
+	IsTemporary   bool     `json:"is_temporary" yaml:"is_temporary"`
+	IsIfNotExists bool     `json:"is_if_not_exists" yaml:"is_if_not_exists"`
+	Table         string   `json:"table" yaml:"table"`
+	Timing        string   `json:"timing" yaml:"timing"`
+	Event         string   `json:"event" yaml:"event"`
+	Columns       []string `json:"columns" yaml:"columns"`
+	ForEachRow    bool     `json:"for_each_row" yaml:"for_each_row"`
+	When          string   `json:"when" yaml:"when"`
+	Body          string   `json:"body" yaml:"body"`
+}
+
+func newState(sql string) *State {
+	return &State{buffer: sql}
+}
+
+// isWord reports whether token is the unquoted word, whether the lexer
+// returns it as an identifier, as a keyword token or as tokKEYWORD.
+func isWord(state *State, token tokenT, word string) bool {
+	return token != tokEOF && state.start < len(state.buffer) && isIDChar(state.buffer[state.start]) &&
+		strings.EqualFold(state.identifier, word)
+}
+
+func nextIsWord(state *State, word string) bool {
+	saved := state.offset
+	if isWord(state, lexerNext(state), word) {
+		return true
+	}
+	state.offset = saved
+	return false
+}
+
+func parseIfNotExists(state *State) (bool, ErrorCode) {
+	if lexerPeek(state) != tokIF {
+		return false, ERROR_NONE
+	}
+	lexerNext(state)
+	if lexerNext(state) != tokNOT || lexerNext(state) != tokEXISTS {
+		return false, ERROR_SYNTAX
+	}
+	return true, ERROR_NONE
+}
+
+// parseQualifiedName parses "name" or "schema.name".
+func parseQualifiedName(state *State) (schema, name string, errCode ErrorCode) {
+	if lexerName(state) != tokIDENTIFIER || state.identifier == "" {
+		return "", "", ERROR_SYNTAX
+	}
+	name = state.identifier
+	if lexerPeek(state) == tokDOT {
+		lexerNext(state)
+		if lexerName(state) != tokIDENTIFIER || state.identifier == "" {
+			return "", "", ERROR_SYNTAX
+		}
+		schema, name = name, state.identifier
+	}
+	return schema, name, ERROR_NONE
+}
+
+func parseNameList(state *State) ([]string, ErrorCode) {
+	var names []string
+	for {
+		if lexerName(state) != tokIDENTIFIER {
+			return nil, ERROR_SYNTAX
+		}
+		names = append(names, state.identifier)
+		if lexerPeek(state) != tokCOMMA {
+			return names, ERROR_NONE
+		}
+		lexerNext(state)
+	}
+}
+
+// scanText skips over the text starting at the current offset up to the
+// first comma or closing parenthesis outside parentheses and quotes, or, if
+// stop is not empty, up to that word. It returns the skipped text trimmed.
+func scanText(state *State, stop string) string {
+	start, depth := state.offset, 0
+	for state.offset < len(state.buffer) {
+		kind, length, _ := scanToken(state.buffer[state.offset:])
+		text := state.buffer[state.offset : state.offset+length]
+		switch {
+		case kind == TOKEN_PUNCTUATION && text == "(":
+			depth++
+		case kind == TOKEN_PUNCTUATION && text == ")" && depth > 0:
+			depth--
+		case kind == TOKEN_PUNCTUATION && (text == "," || text == ")") && depth == 0 && stop == "",
+			kind == TOKEN_IDENTIFIER && depth == 0 && stop != "" && strings.EqualFold(text, stop):
+			return strings.TrimSpace(state.buffer[start:state.offset])
+		}
+		state.offset += length
+	}
+	return strings.TrimSpace(state.buffer[start:state.offset])
+}
+
+// restOf returns the text from the current offset to the end of the
+// statement, without its terminating semicolon.
+func restOf(state *State) string {
+	rest := strings.TrimRightFunc(state.buffer[state.offset:], unicode.IsSpace)
+	return strings.TrimSpace(strings.TrimSuffix(rest, ";"))
+}
+
+// parseIndexedColumn parses an indexed-column, an expression followed by
+// an optional order, up to the next comma or closing parenthesis. The
+// outermost COLLATE of the expression is the collation of the column. Like
+// SQLite, it takes a column name in parentheses or a string for a column
+// name.
+func parseIndexedColumn(state *State) (IdxColumn, ErrorCode) {
+	var column IdxColumn
+	start := state.offset
+	if scanText(state, "") == "" {
+		return column, ERROR_SYNTAX
+	}
+	text := state.buffer[start:state.offset]
+
+	p := newExprParser(text, start)
+	expr, errCode := p.parseExpr(0)
+	if errCode != ERROR_NONE {
+		return column, errCode
+	}
+	if p.accept("ASC") {
+		column.Order = ORDER_ASC
+	} else if p.accept("DESC") {
+		column.Order = ORDER_DESC
+	}
+	if p.peek().Kind != TOKEN_EOF {
+		return column, ERROR_SYNTAX
+	}
+
+	for ; expr.Kind == EXPR_COLLATE; expr = expr.Args[0] {
+		if column.CollateName == "" {
+			column.CollateName = expr.Name
+		}
+	}
+	switch {
+	case expr.Kind == EXPR_COLUMN && expr.Table == "":
+		column.Name = expr.Name
+	case expr.Kind == EXPR_LITERAL && expr.Value[0] == '\'':
+		column.Name = Token{Kind: TOKEN_STRING, Text: expr.Value}.Value()
+	default:
+		column.Name = text[expr.Start-start : expr.End-start]
+		column.Expr = expr
+	}
+	return column, ERROR_NONE
+}
+
+// ParseIndex parses a CREATE INDEX statement.
+func ParseIndex(sql string) (*Index, ErrorCode) {
+	state := newState(sql)
+	var index Index
+
+	if lexerNext(state) != tokCREATE {
+		return nil, ERROR_UNSUPPORTEDSQL
+	}
+	token := lexerNext(state)
+	if token == tokUNIQUE {
+		index.IsUnique = true
+		token = lexerNext(state)
+	}
+	if !isWord(state, token, "INDEX") {
+		return nil, ERROR_UNSUPPORTEDSQL
+	}
+
+	var errCode ErrorCode
+	if index.IsIfNotExists, errCode = parseIfNotExists(state); errCode != ERROR_NONE {
+		return nil, errCode
+	}
+	if index.Schema, index.Name, errCode = parseQualifiedName(state); errCode != ERROR_NONE {
+		return nil, errCode
+	}
+	if lexerNext(state) != tokON || lexerName(state) != tokIDENTIFIER {
+		return nil, ERROR_SYNTAX
+	}
+	index.Table = state.identifier
+
+	if lexerNext(state) != tokOPENparenthesis {
+		return nil, ERROR_SYNTAX
+	}
+	for {
+		column, errCode := parseIndexedColumn(state)
+		if errCode != ERROR_NONE {
+			return nil, errCode
+		}
+		index.Columns = append(index.Columns, column)
+
+		token = lexerNext(state)
+		if token == tokCLOSEDparenthesis {
+			break
+		}
+		if token != tokCOMMA {
+			return nil, ERROR_SYNTAX
+		}
+	}
+
+	if nextIsWord(state, "WHERE") {
+		if index.Where = restOf(state); index.Where == "" {
+			return nil, ERROR_SYNTAX
+		}
+		return &index, ERROR_NONE
+	}
+	token = lexerNext(state)
+	if token == tokSEMICOLON {
+		token = lexerNext(state)
+	}
+	if token != tokEOF {
+		return nil, ERROR_SYNTAX
+	}
+	return &index, ERROR_NONE
+}
+
+// ParseView parses a CREATE VIEW statement.
+func ParseView(sql string) (*View, ErrorCode) {
+	state := newState(sql)
+	var view View
+
+	if lexerNext(state) != tokCREATE {
+		return nil, ERROR_UNSUPPORTEDSQL
+	}
+	token := lexerNext(state)
+	if token == tokTEMP {
+		view.IsTemporary = true
+		token = lexerNext(state)
+	}
+	if !isWord(state, token, "VIEW") {
+		return nil, ERROR_UNSUPPORTEDSQL
+	}
+
+	var errCode ErrorCode
+	if view.IsIfNotExists, errCode = parseIfNotExists(state); errCode != ERROR_NONE {
+		return nil, errCode
+	}
+	if view.Schema, view.Name, errCode = parseQualifiedName(state); errCode != ERROR_NONE {
+		return nil, errCode
+	}
+	if lexerPeek(state) == tokOPENparenthesis {
+		lexerNext(state)
+		if view.Columns, errCode = parseNameList(state); errCode != ERROR_NONE {
+			return nil, errCode
+		}
+		if lexerNext(state) != tokCLOSEDparenthesis {
+			return nil, ERROR_SYNTAX
+		}
+	}
+	if lexerNext(state) != tokAS {
+		return nil, ERROR_SYNTAX
+	}
+	if view.Select = restOf(state); view.Select == "" {
+		return nil, ERROR_SYNTAX
+	}
+	return &view, ERROR_NONE
+}
+
+// ParseTrigger parses a CREATE TRIGGER statement.
+func ParseTrigger(sql string) (*Trigger, ErrorCode) {
+	state := newState(sql)
+	var trigger Trigger
+
+	if lexerNext(state) != tokCREATE {
+		return nil, ERROR_UNSUPPORTEDSQL
+	}
+	token := lexerNext(state)
+	if token == tokTEMP {
+		trigger.IsTemporary = true
+		token = lexerNext(state)
+	}
+	if !isWord(state, token, "TRIGGER") {
+		return nil, ERROR_UNSUPPORTEDSQL
+	}
+
+	var errCode ErrorCode
+	if trigger.IsIfNotExists, errCode = parseIfNotExists(state); errCode != ERROR_NONE {
+		return nil, errCode
+	}
+	if trigger.Schema, trigger.Name, errCode = parseQualifiedName(state); errCode != ERROR_NONE {
+		return nil, errCode
+	}
+
+	switch {
+	case nextIsWord(state, "BEFORE"):
+		trigger.Timing = "BEFORE"
+	case nextIsWord(state, "AFTER"):
+		trigger.Timing = "AFTER"
+	case nextIsWord(state, "INSTEAD"):
+		if !nextIsWord(state, "OF") {
+			return nil, ERROR_SYNTAX
+		}
+		trigger.Timing = "INSTEAD OF"
+	}
+
+	token = lexerNext(state)
+	switch {
+	case token == tokDELETE:
+		trigger.Event = "DELETE"
+	case token == tokUPDATE:
+		trigger.Event = "UPDATE"
+		if nextIsWord(state, "OF") {
+			if trigger.Columns, errCode = parseNameList(state); errCode != ERROR_NONE {
+				return nil, errCode
+			}
+		}
+	case isWord(state, token, "INSERT"):
+		trigger.Event = "INSERT"
+	default:
+		return nil, ERROR_SYNTAX
+	}
+
+	if lexerNext(state) != tokON || lexerName(state) != tokIDENTIFIER {
+		return nil, ERROR_SYNTAX
+	}
+	trigger.Table = state.identifier
+
+	if nextIsWord(state, "FOR") {
+		if !nextIsWord(state, "EACH") || !nextIsWord(state, "ROW") {
+			return nil, ERROR_SYNTAX
+		}
+		trigger.ForEachRow = true
+	}
+	if nextIsWord(state, "WHEN") {
+		if trigger.When = scanText(state, "BEGIN"); trigger.When == "" {
+			return nil, ERROR_SYNTAX
+		}
+	}
+	if scanText(state, "BEGIN") != "" {
+		return nil, ERROR_SYNTAX
+	}
+	trigger.Body = restOf(state)
+	if len(trigger.Body) < len("BEGIN END") || !strings.EqualFold(trigger.Body[len(trigger.Body)-3:], "END") {
+		return nil, ERROR_SYNTAX
+	}
+	return &trigger, ERROR_NONE
+}
@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIndex(t *testing.T) {
+	index, errCode := ParseIndex(`CREATE UNIQUE INDEX IF NOT EXISTS main.users_email
+		ON users (email COLLATE NOCASE DESC, lower(name) ASC, "group") WHERE deleted_at IS NULL;`)
+	if assert.Equal(t, ERROR_NONE, errCode) {
+		assert.Equal(t, "users_email", index.Name)
+		assert.Equal(t, "main", index.Schema)
+		assert.Equal(t, "users", index.Table)
+		assert.True(t, index.IsUnique)
+		assert.True(t, index.IsIfNotExists)
+		expr := index.Columns[1].Expr
+		if assert.NotNil(t, expr) {
+			assert.Equal(t, EXPR_FUNCTION, expr.Kind)
+			assert.Equal(t, "lower", expr.Name)
+			assert.Equal(t, "name", expr.Args[0].Name)
+			index.Columns[1].Expr = nil
+		}
+		assert.Equal(t, []IdxColumn{
+			{Name: "email", CollateName: "NOCASE", Order: ORDER_DESC},
+			{Name: "lower(name)", Order: ORDER_ASC},
+			{Name: "group"},
+		}, index.Columns)
+		assert.Equal(t, "deleted_at IS NULL", index.Where)
+	}
+
+	index, errCode = ParseIndex("CREATE INDEX a_x ON a(x)")
+	if assert.Equal(t, ERROR_NONE, errCode) {
+		assert.False(t, index.IsUnique)
+		assert.Equal(t, []IdxColumn{{Name: "x"}}, index.Columns)
+	}
+
+	for _, sql := range []string{
+		"CREATE INDEX a_x ON a ()",
+		"CREATE INDEX a_x ON a (x",
+		"CREATE INDEX a_x (x)",
+		"CREATE INDEX a_x ON a (x) garbage",
+	} {
+		_, errCode := ParseIndex(sql)
+		assert.Equal(t, ERROR_SYNTAX, errCode, sql)
+	}
+	_, errCode = ParseIndex(`CREATE "INDEX" i ON a (x)`)
+	assert.Equal(t, ERROR_UNSUPPORTEDSQL, errCode)
+}
+
+func TestParseView(t *testing.T) {
+	view, errCode := ParseView("CREATE TEMP VIEW IF NOT EXISTS adults (id, name) AS SELECT id, name FROM users WHERE age >= 18;")
+	if assert.Equal(t, ERROR_NONE, errCode) {
+		assert.Equal(t, "adults", view.Name)
+		assert.True(t, view.IsTemporary)
+		assert.True(t, view.IsIfNotExists)
+		assert.Equal(t, []string{"id", "name"}, view.Columns)
+		assert.Equal(t, "SELECT id, name FROM users WHERE age >= 18", view.Select)
+	}
+
+	_, errCode = ParseView("CREATE VIEW v SELECT 1")
+	assert.Equal(t, ERROR_SYNTAX, errCode)
+	_, errCode = ParseView("CREATE TABLE v (x)")
+	assert.Equal(t, ERROR_UNSUPPORTEDSQL, errCode)
+}
+
+func TestParseTrigger(t *testing.T) {
+	trigger, errCode := ParseTrigger(`CREATE TRIGGER IF NOT EXISTS users_touch
+		AFTER UPDATE OF name, email ON users FOR EACH ROW WHEN old.name <> new.name
+		BEGIN
+			UPDATE users SET updated_at = 'begin; end' WHERE id = new.id;
+		END`)
+	if assert.Equal(t, ERROR_NONE, errCode) {
+		assert.Equal(t, "users_touch", trigger.Name)
+		assert.Equal(t, "AFTER", trigger.Timing)
+		assert.Equal(t, "UPDATE", trigger.Event)
+		assert.Equal(t, []string{"name", "email"}, trigger.Columns)
+		assert.Equal(t, "users", trigger.Table)
+		assert.True(t, trigger.ForEachRow)
+		assert.Equal(t, "old.name <> new.name", trigger.When)
+		assert.Contains(t, trigger.Body, "BEGIN\n")
+		assert.Contains(t, trigger.Body, "'begin; end'")
+	}
+
+	trigger, errCode = ParseTrigger("CREATE TEMP TRIGGER t INSTEAD OF INSERT ON v BEGIN SELECT 1; END;")
+	if assert.Equal(t, ERROR_NONE, errCode) {
+		assert.True(t, trigger.IsTemporary)
+		assert.Equal(t, "INSTEAD OF", trigger.Timing)
+		assert.Equal(t, "INSERT", trigger.Event)
+		assert.Equal(t, "BEGIN SELECT 1; END", trigger.Body)
+	}
+
+	trigger, errCode = ParseTrigger("CREATE TRIGGER t DELETE ON a BEGIN DELETE FROM b; END")
+	if assert.Equal(t, ERROR_NONE, errCode) {
+		assert.Equal(t, "", trigger.Timing)
+		assert.Equal(t, "DELETE", trigger.Event)
+	}
+
+	for _, sql := range []string{
+		"CREATE TRIGGER t INSTEAD INSERT ON v BEGIN SELECT 1; END",
+		"CREATE TRIGGER t AFTER SELECT ON v BEGIN SELECT 1; END",
+		"CREATE TRIGGER t AFTER INSERT ON v SELECT 1",
+		"CREATE TRIGGER t AFTER INSERT ON v BEGIN SELECT 1;",
+	} {
+		_, errCode := ParseTrigger(sql)
+		assert.Equal(t, ERROR_SYNTAX, errCode, sql)
+	}
+}
@@ -0,0 +1,157 @@
+package parser
+
+import "strings"
+
+// Statement is one SQL statement of a script, without its terminating
+// semicolon. Offset is the byte offset of the statement in the script, so
+// that Text is script[Offset:Offset+len(Text)], and Line its 1-based starting
+// line.
+type Statement struct {
+	Text   string
+	Offset int
+	Line   int
+}
+
+// Schema holds every CREATE TABLE, CREATE INDEX, CREATE VIEW and CREATE
+// TRIGGER statement found in a script.
+type Schema struct {
+	Tables   []*Table
+	Indexes  []*Index
+	Views    []*View
+	Triggers []*Trigger
+}
+
+// Table returns the table with the given name, compared case-insensitively
+// like SQLite does, or nil.
+func (s *Schema) Table(name string) *Table {
+	for _, table := range s.Tables {
+		if strings.EqualFold(table.Name, name) {
+			return table
+		}
+	}
+	return nil
+}
+
+// SplitStatements splits a script on the semicolons that terminate its
+// statements, ignoring those inside quotes, comments and trigger bodies.
+// Empty statements are dropped.
+func SplitStatements(sql string) []Statement {
+	var statements []Statement
+	var leading []string
+	var first, last Token
+	empty := true
+
+	flush := func() {
+		if !empty {
+			statements = append(statements, Statement{Text: sql[first.Start:last.End], Offset: first.Start, Line: first.Line})
+		}
+		empty, leading = true, leading[:0]
+	}
+
+	tokenizer := NewTokenizer(sql)
+	for token := tokenizer.Next(); token.Kind != TOKEN_EOF; token = tokenizer.Next() {
+		if token.Kind == TOKEN_WHITESPACE {
+			continue
+		}
+		// The semicolons of a trigger body are part of the statement, up
+		// to the one after its END.
+		if token.Text == ";" && (!isCreateTrigger(leading) || last.Is("END")) {
+			flush()
+			continue
+		}
+		if empty {
+			first, empty = token, false
+		}
+		last = token
+		if (token.Kind == TOKEN_KEYWORD || token.Kind == TOKEN_IDENTIFIER) && len(leading) < 3 {
+			leading = append(leading, strings.ToUpper(token.Text))
+		}
+	}
+	flush()
+
+	return statements
+}
+
+func isCreateTrigger(words []string) bool {
+	if len(words) < 2 || words[0] != "CREATE" {
+		return false
+	}
+	if words[1] == "TEMP" || words[1] == "TEMPORARY" {
+		return len(words) > 2 && words[2] == "TRIGGER"
+	}
+	return words[1] == "TRIGGER"
+}
+
+// createKind returns "TABLE", "INDEX", "VIEW" or "TRIGGER" for the CREATE
+// statements of that kind, and "" for any other statement.
+func createKind(sql string) string {
+	state := newState(sql)
+	if lexerNext(state) != tokCREATE {
+		return ""
+	}
+	token := lexerNext(state)
+	if token == tokTEMP || token == tokUNIQUE {
+		token = lexerNext(state)
+	}
+	if token == tokTABLE {
+		return "TABLE"
+	}
+	for _, kind := range []string{"INDEX", "VIEW", "TRIGGER"} {
+		if isWord(state, token, kind) {
+			return kind
+		}
+	}
+	return ""
+}
+
+// ParseSchema parses every CREATE TABLE, INDEX, VIEW and TRIGGER statement
+// of a script. Statements of any other kind are skipped. Parsing stops at the
+// first table that fails; indexes, views and triggers that cannot be parsed
+// are skipped.
+func ParseSchema(sql string) (*Schema, ErrorCode) {
+	var schema Schema
+	for _, statement := range SplitStatements(sql) {
+		kind := createKind(statement.Text)
+		if kind == "" {
+			continue
+		}
+		if errCode := schema.Add(statement.Text); errCode != ERROR_NONE && kind == "TABLE" {
+			return &schema, errCode
+		}
+	}
+	return &schema, ERROR_NONE
+}
+
+// Add parses a CREATE TABLE, INDEX, VIEW or TRIGGER statement and appends the
+// result to the schema. Any other statement returns ERROR_UNSUPPORTEDSQL.
+func (s *Schema) Add(sql string) ErrorCode {
+	switch createKind(sql) {
+	case "TABLE":
+		table, errCode := ParseTable(sql, 0)
+		if errCode != ERROR_NONE {
+			return errCode
+		}
+		s.Tables = append(s.Tables, table)
+	case "INDEX":
+		index, errCode := ParseIndex(sql)
+		if errCode != ERROR_NONE {
+			return errCode
+		}
+		s.Indexes = append(s.Indexes, index)
+	case "VIEW":
+		view, errCode := ParseView(sql)
+		if errCode != ERROR_NONE {
+			return errCode
+		}
+		s.Views = append(s.Views, view)
+	case "TRIGGER":
+		trigger, errCode := ParseTrigger(sql)
+		if errCode != ERROR_NONE {
+			return errCode
+		}
+		s.Triggers = append(s.Triggers, trigger)
+	default:
+		return ERROR_UNSUPPORTEDSQL
+	}
+	return ERROR_NONE
+}
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStatements(t *testing.T) {
+	const sql = `CREATE TABLE a (x TEXT DEFAULT 'é;');
+-- a comment; with a semicolon
+CREATE TRIGGER tr AFTER INSERT ON a BEGIN
+  DELETE FROM b; UPDATE c SET y = 1;
+END;
+
+CREATE TABLE b (y)`
+
+	statements := SplitStatements(sql)
+	if assert.Len(t, statements, 3) {
+		assert.Equal(t, "CREATE TABLE a (x TEXT DEFAULT 'é;')", statements[0].Text)
+		assert.Equal(t, 1, statements[0].Line)
+		assert.Equal(t, 2, statements[1].Line)
+		assert.Contains(t, statements[1].Text, "END")
+		assert.Equal(t, "CREATE TABLE b (y)", statements[2].Text)
+		assert.Equal(t, 7, statements[2].Line)
+		for _, statement := range statements {
+			assert.Equal(t, statement.Text, sql[statement.Offset:statement.Offset+len(statement.Text)])
+		}
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	schema, errCode := ParseSchema(`
+	CREATE TABLE a (x INTEGER PRIMARY KEY);
+	CREATE INDEX a_x ON a (x);
+	CREATE TEMP TABLE b (y REFERENCES a (x));
+	CREATE VIEW v AS SELECT x FROM a;
+	CREATE TRIGGER t AFTER INSERT ON a BEGIN DELETE FROM b; END;
+	CREATE INDEX broken ON a;
+	`)
+	assert.Equal(t, ERROR_NONE, errCode)
+	assert.Len(t, schema.Tables, 2)
+	assert.True(t, schema.Table("B").IsTemporary)
+	assert.Nil(t, schema.Table("a_x"))
+	if assert.Len(t, schema.Indexes, 1) {
+		assert.Equal(t, "a_x", schema.Indexes[0].Name)
+	}
+	assert.Len(t, schema.Views, 1)
+	assert.Len(t, schema.Triggers, 1)
+}
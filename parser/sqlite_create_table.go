@@ -1,9 +1,7 @@
 package parser
 
 import (
-	"fmt"
 	"strings"
-	"unicode"
 )
 
 type tokenT int
@@ -13,7 +11,8 @@ const (
 	tokEOF tokenT = iota
 	tokERROR
 	tokIDENTIFIER
-	tokCOMMENT
+	// tokKEYWORD is any keyword without a token of its own.
+	tokKEYWORD
 
 	// keywords
 	tokCREATE
@@ -32,6 +31,11 @@ const (
 	tokCOMMA
 	tokOPENparenthesis
 	tokCLOSEDparenthesis
+	tokPLUS
+	tokMINUS
+
+	// literals
+	tokNUMBER
 
 	// Constraints
 	tokCONSTRAINT
@@ -71,162 +75,144 @@ const (
 )
 
 type ForeignKey struct {
-	Table      string
-	NumColumns int
-	ColumnName []string
-	OnDelete   FkAction
-	OnUpdate   FkAction
-	Match      string
-	Deferrable FkDefType
-}
-
-type Column struct {
-	Name                  string
-	Type                  string
-	Length                string
-	ConstraintName        string
-	IsPrimaryKey          bool
-	IsAutoincrement       bool
-	IsNotnull             bool
-	IsUnique              bool
-	PkOrder               OrderClause
-	PkConflictClause      ConflictClause
-	NotNullConflictClause ConflictClause
-	UniqueConflictClause  ConflictClause
-	CheckExpr             string
-	DefaultExpr           string
-	CollateName           string
-	ForeignKeyClause      *ForeignKey
-}
-
-type TableConstraint struct {
-	Type             ConstraintType
-	Name             string
-	NumIndexed       int
-	IndexedColumns   []IdxColumn
-	ConflictClause   ConflictClause
-	CheckExpr        string
-	ForeignKeyNum    int
-	ForeignKeyName   []string
-	ForeignKeyClause *ForeignKey
-}
-
-type Table struct {
-	Name           string
-	Schema         string
-	IsTemporary    bool
-	IsIfNotExists  bool
-	IsWithoutRowid bool
-	NumColumns     int
-	Columns        []Column
-	NumConstraint  int
-	Constraints    []TableConstraint
-}
-
-type IdxColumn struct {
-	Name        string
-	CollateName string
-	Order       OrderClause
-}
-
-type State struct {
-	buffer     []rune
-	size       int
-	offset     int
-	identifier string
-	table      *Table
+	Table      string    `json:"table" yaml:"table"`
+	NumColumns int       `json:"num_columns" yaml:"num_columns"`
+	ColumnName []string  `json:"column_name" yaml:"column_name"`
+	OnDelete   FkAction  `json:"on_delete" yaml:"on_delete"`
+	OnUpdate   FkAction  `json:"on_update" yaml:"on_update"`
+	Match      string    `json:"match" yaml:"match"`
+	Deferrable FkDefType `json:"deferrable" yaml:"deferrable"`
 }
 
-func isEOF(state *State) bool {
-	return state.offset == state.size
+// SignedNumber is a numeric argument of a type name, such as the -5 of
+// NUMERIC(-5, +3). Sign is "", "+" or "-" and Value the number as written.
+type SignedNumber struct {
+	Sign  string `json:"sign" yaml:"sign"`
+	Value string `json:"value" yaml:"value"`
 }
 
-func peek(state *State) rune {
-	return state.buffer[state.offset]
+func (n SignedNumber) String() string {
+	return n.Sign + n.Value
 }
 
-func peek2(state *State) rune {
-	return state.buffer[state.offset+1]
+// TypeName is the declared type of a column: the unquoted words of its
+// name, such as DOUBLE PRECISION, its numeric arguments, at most two, and
+// its text as written, arguments included.
+type TypeName struct {
+	Words []string       `json:"words" yaml:"words"`
+	Args  []SignedNumber `json:"args" yaml:"args"`
+	Text  string         `json:"text" yaml:"text"`
 }
 
-func next(state *State) rune {
-	c := state.buffer[state.offset]
-	state.offset++
-	return c
-}
-
-func skip1(state *State) {
-	state.offset++
-}
-
-func strNoCaseNcmp(s1, s2 string, n int) int {
-	index := 0
-	sl1 := strings.ToLower(s1)
-	sl2 := strings.ToLower(s2)
-	for n > 0 && index < len(sl2) && sl1[index] == sl2[index] {
-		if sl1[index] == 0x00 {
-			return 0
+// String returns the type name with its words separated by single spaces
+// and its arguments by a comma and a space.
+func (t *TypeName) String() string {
+	s := strings.Join(t.Words, " ")
+	if len(t.Args) > 0 {
+		args := make([]string, len(t.Args))
+		for i, arg := range t.Args {
+			args[i] = arg.String()
 		}
-		n--
-		index++
-	}
-	if n == 0 {
-		return 0
+		s += "(" + strings.Join(args, ", ") + ")"
 	}
-	return 1
+	return s
 }
 
-func symbolIsSpace(r rune) bool {
-	switch r {
-	case '\t', '\v', '\f', ' ':
-		return true
-	}
-	return false
-}
-
-func symbolIsNewline(r rune) bool {
-	switch r {
-	case '\n', '\r':
-		return true
-	}
-	return false
+// ColumnConstraint is one constraint of a column definition, with the
+// CONSTRAINT name given just before it, if any. Start and End are the byte
+// offsets of its text in the parsed statement, CONSTRAINT name included.
+type ColumnConstraint struct {
+	Type             ColumnConstraintType `json:"type" yaml:"type"`
+	Name             string               `json:"name" yaml:"name"`
+	ConflictClause   ConflictClause       `json:"conflict_clause" yaml:"conflict_clause"`
+	Order            OrderClause          `json:"order" yaml:"order"`
+	IsAutoincrement  bool                 `json:"is_autoincrement" yaml:"is_autoincrement"`
+	Expr             string               `json:"expr" yaml:"expr"`
+	CollateName      string               `json:"collate_name" yaml:"collate_name"`
+	ForeignKeyClause *ForeignKey          `json:"foreign_key_clause" yaml:"foreign_key_clause"`
+	Start            int                  `json:"start" yaml:"start"`
+	End              int                  `json:"end" yaml:"end"`
 }
 
-func symbolIsToSkip(r rune) bool {
-	return symbolIsSpace(r) || symbolIsNewline(r)
-}
-
-func symbolIsComment(r rune, state *State) bool {
-	if r == '-' && peek2(state) == '-' {
-		return true
-	}
-	if r == '/' && peek2(state) == '*' {
-		return true
-	}
-	return false
+// Column is a column definition. Type holds the words of the type name
+// joined by spaces and Length the text between its parentheses; TypeName,
+// set by the parser, has the same type broken down.
+//
+// Constraints lists the column constraints in the order they are written.
+// The fields from ConstraintName to ForeignKeyClause sum them up: when a
+// kind of constraint is repeated, the last one wins, and ConstraintName is
+// the last name given.
+type Column struct {
+	Name                  string             `json:"name" yaml:"name"`
+	Type                  string             `json:"type" yaml:"type"`
+	Length                string             `json:"length" yaml:"length"`
+	TypeName              *TypeName          `json:"type_name" yaml:"type_name"`
+	ConstraintName        string             `json:"constraint_name" yaml:"constraint_name"`
+	IsPrimaryKey          bool               `json:"is_primary_key" yaml:"is_primary_key"`
+	IsAutoincrement       bool               `json:"is_autoincrement" yaml:"is_autoincrement"`
+	IsNotnull             bool               `json:"is_notnull" yaml:"is_notnull"`
+	IsUnique              bool               `json:"is_unique" yaml:"is_unique"`
+	PkOrder               OrderClause        `json:"pk_order" yaml:"pk_order"`
+	PkConflictClause      ConflictClause     `json:"pk_conflict_clause" yaml:"pk_conflict_clause"`
+	NotNullConflictClause ConflictClause     `json:"not_null_conflict_clause" yaml:"not_null_conflict_clause"`
+	UniqueConflictClause  ConflictClause     `json:"unique_conflict_clause" yaml:"unique_conflict_clause"`
+	CheckExpr             string             `json:"check_expr" yaml:"check_expr"`
+	DefaultExpr           string             `json:"default_expr" yaml:"default_expr"`
+	CollateName           string             `json:"collate_name" yaml:"collate_name"`
+	ForeignKeyClause      *ForeignKey        `json:"foreign_key_clause" yaml:"foreign_key_clause"`
+	Constraints           []ColumnConstraint `json:"constraints" yaml:"constraints"`
 }
 
-func symbolIsAlpha(r rune) bool {
-	if r == '_' {
-		return true
-	}
-	return unicode.IsLetter(r)
+type TableConstraint struct {
+	Type             ConstraintType `json:"type" yaml:"type"`
+	Name             string         `json:"name" yaml:"name"`
+	NumIndexed       int            `json:"num_indexed" yaml:"num_indexed"`
+	IndexedColumns   []IdxColumn    `json:"indexed_columns" yaml:"indexed_columns"`
+	ConflictClause   ConflictClause `json:"conflict_clause" yaml:"conflict_clause"`
+	CheckExpr        string         `json:"check_expr" yaml:"check_expr"`
+	ForeignKeyNum    int            `json:"foreign_key_num" yaml:"foreign_key_num"`
+	ForeignKeyName   []string       `json:"foreign_key_name" yaml:"foreign_key_name"`
+	ForeignKeyClause *ForeignKey    `json:"foreign_key_clause" yaml:"foreign_key_clause"`
 }
 
-func symbolIsIdentifier(r rune) bool {
-	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+type Table struct {
+	Name           string            `json:"name" yaml:"name"`
+	Schema         string            `json:"schema" yaml:"schema"`
+	IsTemporary    bool              `json:"is_temporary" yaml:"is_temporary"`
+	IsIfNotExists  bool              `json:"is_if_not_exists" yaml:"is_if_not_exists"`
+	IsWithoutRowid bool              `json:"is_without_rowid" yaml:"is_without_rowid"`
+	IsStrict       bool              `json:"is_strict" yaml:"is_strict"`
+	NumColumns     int               `json:"num_columns" yaml:"num_columns"`
+	Columns        []Column          `json:"columns" yaml:"columns"`
+	NumConstraint  int               `json:"num_constraint" yaml:"num_constraint"`
+	Constraints    []TableConstraint `json:"constraints" yaml:"constraints"`
 }
 
-func symbolIsEscape(r rune) bool {
-	return r == '`' || r == '\'' || r == '"' || r == '['
+// IdxColumn is a column of an index or of a PRIMARY KEY or UNIQUE
+// constraint. When it is an expression rather than a column name, Expr holds
+// the expression and Name its text as written.
+type IdxColumn struct {
+	Name        string      `json:"name" yaml:"name"`
+	CollateName string      `json:"collate_name" yaml:"collate_name"`
+	Order       OrderClause `json:"order" yaml:"order"`
+	Expr        *Expr       `json:"expr" yaml:"expr"`
 }
 
-func symbolIsPunctuation(r rune) bool {
-	return r == '.' || r == ',' || r == '(' || r == ')' || r == ';'
+type State struct {
+	buffer string
+	offset int
+	// start is the offset of the last token returned by lexerNext.
+	start      int
+	identifier string
+	// usage tells where the last word returned by lexerNext may be used
+	// as a name: a keyword usage, wordUsage for any other unquoted word and
+	// 0 for anything else.
+	usage uint8
+	table *Table
 }
 
 func tokenIsColumnConstraint(t tokenT) bool {
-	return t == tokCONSTRAINT || t == tokPRIMARY || t == tokNOT || t == tokUNIQUE ||
+	return t == tokCONSTRAINT || t == tokPRIMARY || t == tokNOT || t == tokNULL || t == tokUNIQUE ||
 		t == tokCHECK || t == tokDEFAULT || t == tokCOLLATE || t == tokREFERENCES
 }
 
@@ -235,277 +221,94 @@ func tokenIsTableConstraint(t tokenT) bool {
 		t == tokCHECK || t == tokFOREIGN
 }
 
-func lexerKeyword(ptr string, length int) tokenT {
-	switch length {
-	case 2:
-		if strNoCaseNcmp(ptr, "if", length) == 0 {
-			return tokIF
-		}
-		if strNoCaseNcmp(ptr, "as", length) == 0 {
-			return tokAS
-		}
-		if strNoCaseNcmp(ptr, "on", length) == 0 {
-			return tokON
-		}
-		if strNoCaseNcmp(ptr, "no", length) == 0 {
-			return tokNO
-		}
-	case 3:
-		if strNoCaseNcmp(ptr, "not", length) == 0 {
-			return tokNOT
-		}
-		if strNoCaseNcmp(ptr, "key", length) == 0 {
-			return tokKEY
-		}
-		if strNoCaseNcmp(ptr, "asc", length) == 0 {
-			return tokASC
-		}
-		if strNoCaseNcmp(ptr, "set", length) == 0 {
-			return tokSET
-		}
-	case 4:
-		if strNoCaseNcmp(ptr, "temp", length) == 0 {
-			return tokTEMP
-		}
-		if strNoCaseNcmp(ptr, "desc", length) == 0 {
-			return tokDESC
-		}
-		if strNoCaseNcmp(ptr, "null", length) == 0 {
-			return tokNULL
-		}
-		if strNoCaseNcmp(ptr, "fail", length) == 0 {
-			return tokFAIL
-		}
-	case 5:
-		if strNoCaseNcmp(ptr, "table", length) == 0 {
-			return tokTABLE
-		}
-		if strNoCaseNcmp(ptr, "rowid", length) == 0 {
-			return tokROWID
-		}
-		if strNoCaseNcmp(ptr, "check", length) == 0 {
-			return tokCHECK
-		}
-		if strNoCaseNcmp(ptr, "abort", length) == 0 {
-			return tokABORT
-		}
-		if strNoCaseNcmp(ptr, "match", length) == 0 {
-			return tokMATCH
-		}
-	case 6:
-		if strNoCaseNcmp(ptr, "create", length) == 0 {
-			return tokCREATE
-		}
-		if strNoCaseNcmp(ptr, "exists", length) == 0 {
-			return tokEXISTS
-		}
-		if strNoCaseNcmp(ptr, "unique", length) == 0 {
-			return tokUNIQUE
-		}
-		if strNoCaseNcmp(ptr, "ignore", length) == 0 {
-			return tokIGNORE
-		}
-		if strNoCaseNcmp(ptr, "delete", length) == 0 {
-			return tokDELETE
-		}
-		if strNoCaseNcmp(ptr, "update", length) == 0 {
-			return tokUPDATE
-		}
-		if strNoCaseNcmp(ptr, "action", length) == 0 {
-			return tokACTION
-		}
-	case 7:
-		if strNoCaseNcmp(ptr, "without", length) == 0 {
-			return tokWITHOUT
-		}
-		if strNoCaseNcmp(ptr, "primary", length) == 0 {
-			return tokPRIMARY
-		}
-		if strNoCaseNcmp(ptr, "default", length) == 0 {
-			return tokDEFAULT
-		}
-		if strNoCaseNcmp(ptr, "collate", length) == 0 {
-			return tokCOLLATE
-		}
-		if strNoCaseNcmp(ptr, "replace", length) == 0 {
-			return tokREPLACE
-		}
-		if strNoCaseNcmp(ptr, "cascade", length) == 0 {
-			return tokCASCADE
-		}
-		if strNoCaseNcmp(ptr, "foreign", length) == 0 {
-			return tokFOREIGN
-		}
-	case 8:
-		if strNoCaseNcmp(ptr, "conflict", length) == 0 {
-			return tokCONFLICT
-		}
-		if strNoCaseNcmp(ptr, "rollback", length) == 0 {
-			return tokROLLBACK
-		}
-		if strNoCaseNcmp(ptr, "restrict", length) == 0 {
-			return tokRESTRICT
-		}
-		if strNoCaseNcmp(ptr, "deferred", length) == 0 {
-			return tokDEFERRED
-		}
-	case 9:
-		if strNoCaseNcmp(ptr, "temporary", length) == 0 {
-			return tokTEMP
-		}
-		if strNoCaseNcmp(ptr, "initially", length) == 0 {
-			return tokINITIALLY
-		}
-		if strNoCaseNcmp(ptr, "immediate", length) == 0 {
-			return tokIMMEDIATE
-		}
-	case 10:
-		if strNoCaseNcmp(ptr, "constraint", length) == 0 {
-			return tokCONSTRAINT
-		}
-		if strNoCaseNcmp(ptr, "references", length) == 0 {
-			return tokREFERENCES
-		}
-		if strNoCaseNcmp(ptr, "deferrable", length) == 0 {
-			return tokDEFERRABLE
-		}
-	case 13:
-		if strNoCaseNcmp(ptr, "autoincrement", length) == 0 {
-			return tokAUTOINCREMENT
-		}
+// lexerKeyword returns the token of the word ptr and where it may be used
+// as a name. ROWID is not a keyword but has a token for WITHOUT ROWID.
+func lexerKeyword(ptr string) (tokenT, uint8) {
+	if k := lookupKeyword(ptr); k != nil {
+		return k.token, k.usage
 	}
-
-	return tokIDENTIFIER
+	if strings.EqualFold(ptr, "rowid") {
+		return tokROWID, wordUsage
+	}
+	return tokIDENTIFIER, wordUsage
 }
 
-func lexerComment(state *State) tokenT {
-	isCComment := next(state) == '/' && next(state) == '*'
-	for {
-		c1 := next(state)
-
-		if c1 == 0x00 {
-			if isCComment {
-				return tokERROR
+// lexerNext returns the next token, skipping whitespace and comments. It
+// reads the tokens of the Tokenizer: words, strings and quoted names are
+// left in state.identifier, strings and quoted names without their quotes.
+// Tokens the grammar only has inside expressions, which scanText skips,
+// are tokERROR. Like SQLite, the input ends at a NUL character.
+func lexerNext(state *State) tokenT {
+	state.usage = 0
+	for state.offset < len(state.buffer) && state.buffer[state.offset] != 0x00 {
+		state.start = state.offset
+		kind, length, _ := scanToken(state.buffer[state.offset:])
+		text := state.buffer[state.offset : state.offset+length]
+		state.offset += length
+
+		switch kind {
+		case TOKEN_WHITESPACE, TOKEN_COMMENT:
+			continue
+		case TOKEN_IDENTIFIER, TOKEN_STRING:
+			if isIDChar(text[0]) {
+				var t tokenT
+				t, state.usage = lexerKeyword(text)
+				state.identifier = text
+				return t
+			}
+			state.identifier = Token{Kind: kind, Text: text}.Value()
+			return tokIDENTIFIER
+		case TOKEN_NUMBER:
+			state.identifier = text
+			return tokNUMBER
+		case TOKEN_PUNCTUATION, TOKEN_OPERATOR:
+			switch text {
+			case ",":
+				return tokCOMMA
+			case ".":
+				return tokDOT
+			case "(":
+				return tokOPENparenthesis
+			case ")":
+				return tokCLOSEDparenthesis
+			case ";":
+				return tokSEMICOLON
+			case "+":
+				return tokPLUS
+			case "-":
+				return tokMINUS
 			}
-			return tokCOMMENT
-		}
-
-		if !isCComment && symbolIsNewline(c1) {
-			break
-		}
-
-		c2 := next(state)
-		if isCComment && c1 == '*' && c2 == '/' {
-			break
 		}
+		return tokERROR
 	}
-	return tokCOMMENT
-}
-
-func lexerPunctuation(state *State) tokenT {
-	c := next(state)
-	switch c {
-	case ',':
-		return tokCOMMA
-	case '.':
-		return tokDOT
-	case '(':
-		return tokOPENparenthesis
-	case ')':
-		return tokCLOSEDparenthesis
-	case ';':
-		return tokSEMICOLON
-	}
-	return tokERROR
+	return tokEOF
 }
 
-func lexerAlpha(state *State) tokenT {
-	offset := state.offset
-
-	for symbolIsIdentifier(peek(state)) {
-		skip1(state)
-	}
-
-	length := state.offset - offset
-	ptr := string(state.buffer[offset:state.offset])
-
-	t := lexerKeyword(ptr, length)
-
-	if t != tokIDENTIFIER {
-		return t
-	}
-
-	state.identifier = ptr
-
-	return tokIDENTIFIER
+func lexerPeek(state *State) tokenT {
+	saved := state.offset
+	token := lexerNext(state)
+	state.offset = saved
+	return token
 }
 
-func lexerEscape(state *State) tokenT {
-	c := next(state)
-	escaped := c
-	if escaped == '[' {
-		escaped = ']'
-	}
-
-	offset := state.offset
-	c = next(state)
-	for c != 0 && c != escaped {
-		c = next(state)
-	}
-
-	ptr := string(state.buffer[offset : state.offset-1])
-
-	if c != escaped {
-		return tokERROR
+// lexerWord is lexerNext that returns tokIDENTIFIER for the keywords SQLite
+// accepts as names with the given usage, leaving the word in
+// state.identifier.
+func lexerWord(state *State, usage uint8) tokenT {
+	token := lexerNext(state)
+	if token != tokIDENTIFIER && state.usage&usage != 0 {
+		return tokIDENTIFIER
 	}
-
-	state.identifier = ptr
-
-	return tokIDENTIFIER
+	return token
 }
 
-func lexerNext(state *State) tokenT {
-	for {
-		if isEOF(state) {
-			return tokEOF
-		}
-
-		c := peek(state)
-		if c == 0x00 {
-			return tokEOF
-		}
-
-		if symbolIsToSkip(c) {
-			skip1(state)
-			continue
-		}
-
-		if symbolIsComment(c, state) {
-			if lexerComment(state) != tokCOMMENT {
-				return tokERROR
-			}
-			continue
-		}
-
-		if symbolIsPunctuation(c) {
-			return lexerPunctuation(state)
-		}
-
-		if symbolIsAlpha(c) {
-			return lexerAlpha(state)
-		}
-
-		if symbolIsEscape(c) {
-			return lexerEscape(state)
-		}
-
-		return tokERROR
-	}
+func lexerName(state *State) tokenT {
+	return lexerWord(state, keywordName)
 }
 
-func lexerPeek(state *State) tokenT {
+func lexerPeekWord(state *State, usage uint8) tokenT {
 	saved := state.offset
-	token := lexerNext(state)
+	token := lexerWord(state, usage)
 	state.offset = saved
 	return token
 }
@@ -559,9 +362,8 @@ func parseOptionalConflictClause(state *State, conflict *ConflictClause) ErrorCo
 func parseForeignKeyClause(state *State) *ForeignKey {
 	var fk ForeignKey
 
-	token := lexerNext(state)
+	token := lexerName(state)
 	if token != tokIDENTIFIER {
-		fmt.Println("parseForeignKeyClause error")
 		return nil
 	}
 
@@ -570,9 +372,8 @@ func parseForeignKeyClause(state *State) *ForeignKey {
 	if lexerPeek(state) == tokOPENparenthesis {
 		lexerNext(state)
 
-		token = lexerNext(state)
+		token = lexerName(state)
 		if token != tokIDENTIFIER {
-			fmt.Println("parseForeignKeyClause error")
 			return nil
 		}
 		fk.ColumnName = []string{state.identifier}
@@ -582,9 +383,8 @@ func parseForeignKeyClause(state *State) *ForeignKey {
 			lexerNext(state)
 		}
 		for token == tokCOMMA {
-			token = lexerNext(state)
+			token = lexerName(state)
 			if token != tokIDENTIFIER {
-				fmt.Println("parseForeignKeyClause error")
 				return nil
 			}
 
@@ -597,7 +397,6 @@ func parseForeignKeyClause(state *State) *ForeignKey {
 			}
 		}
 		if lexerNext(state) != tokCLOSEDparenthesis {
-			fmt.Println("parseForeignKeyClause error")
 			return nil
 		}
 	}
@@ -608,9 +407,8 @@ func parseForeignKeyClause(state *State) *ForeignKey {
 			lexerNext(state)
 
 			if token == tokMATCH {
-				token = lexerNext(state)
+				token = lexerName(state)
 				if token != tokIDENTIFIER {
-					fmt.Println("parseForeignKeyClause error")
 					return nil
 				}
 				fk.Match = state.identifier
@@ -620,7 +418,6 @@ func parseForeignKeyClause(state *State) *ForeignKey {
 			if token == tokON {
 				token = lexerNext(state)
 				if token != tokDELETE && token != tokUPDATE {
-					fmt.Println("parseForeignKeyClause error")
 					return nil
 				}
 				isUpdate := token == tokUPDATE
@@ -641,7 +438,6 @@ func parseForeignKeyClause(state *State) *ForeignKey {
 				} else if token == tokSET {
 					token = lexerNext(state)
 					if token != tokNULL && token != tokDEFAULT {
-						fmt.Println("parseForeignKeyClause error")
 						return nil
 					}
 					if token == tokNULL {
@@ -659,7 +455,6 @@ func parseForeignKeyClause(state *State) *ForeignKey {
 					}
 				} else if token == tokNO {
 					if lexerNext(state) != tokACTION {
-						fmt.Println("parseForeignKeyClause error")
 						return nil
 					}
 					if isUpdate {
@@ -699,13 +494,11 @@ func parseForeignKeyClause(state *State) *ForeignKey {
 							fk.Deferrable = DEFTYPE_DEFERRABLE_INITIALLY_IMMEDIATE
 						}
 					} else {
-						fmt.Println("parseForeignKeyClause error")
 						return nil
 					}
 				}
 				continue
 			}
-			fmt.Println("parseForeignKeyClause error")
 			return nil
 		}
 		return &fk
@@ -718,9 +511,8 @@ func parseTableConstraint(state *State) *TableConstraint {
 
 	if token == tokCONSTRAINT {
 		lexerNext(state)
-		token = lexerNext(state)
+		token = lexerName(state)
 		if token != tokIDENTIFIER {
-			fmt.Println("parseTableConstraint error")
 			return nil
 		}
 		constraint.Name = state.identifier
@@ -728,22 +520,25 @@ func parseTableConstraint(state *State) *TableConstraint {
 		token = lexerPeek(state)
 
 		if token != tokCHECK && token != tokPRIMARY && token != tokUNIQUE && token != tokFOREIGN {
-			fmt.Println("parseTableConstraint error")
 			return nil
 		}
 	}
 
 	if token == tokCHECK {
-		token = lexerNext(state)
+		lexerNext(state)
 		constraint.Type = TABLECONSTRAINT_CHECK
-
-		fmt.Println("parseTableConstraint error")
-		return nil
+		expr, errCode := parseCheck(state)
+		if errCode != ERROR_NONE {
+			return nil
+		}
+		constraint.CheckExpr = expr
+		if parseOptionalConflictClause(state, &constraint.ConflictClause) != ERROR_NONE {
+			return nil
+		}
 	} else if token == tokPRIMARY || token == tokUNIQUE {
 		token = lexerNext(state)
 		if token == tokPRIMARY {
 			if lexerNext(state) != tokKEY {
-				fmt.Println("parseTableConstraint error")
 				return nil
 			}
 			constraint.Type = TABLECONSTRAINT_PRIMARYKEY
@@ -752,102 +547,41 @@ func parseTableConstraint(state *State) *TableConstraint {
 		}
 
 		if lexerNext(state) != tokOPENparenthesis {
-			fmt.Println("parseTableConstraint error")
-			return nil
-		}
-
-		//do
-		var column IdxColumn
-
-		token = lexerNext(state)
-		if token != tokIDENTIFIER {
-			fmt.Println("parseTableConstraint error")
-			return nil
-		}
-		column.Name = state.identifier
-
-		if lexerPeek(state) == tokCOLLATE {
-			lexerNext(state)
-
-			token := lexerNext(state)
-			if token != tokIDENTIFIER {
-				fmt.Println("parseTableConstraint error")
-				return nil
-			}
-			column.CollateName = state.identifier
-		}
-
-		if parseOptionalOrder(state, &column.Order) != ERROR_NONE {
-			fmt.Println("parseTableConstraint error")
 			return nil
 		}
 
-		constraint.NumIndexed++
-		constraint.IndexedColumns = []IdxColumn{column}
-
-		token = lexerPeek(state)
-		if token == tokCOMMA {
-			lexerNext(state)
-		}
-		// while
-		for token == tokCOMMA {
-			var column IdxColumn
-
-			token = lexerNext(state)
-			if token != tokIDENTIFIER {
-				fmt.Println("parseTableConstraint error")
-				return nil
-			}
-			column.Name = state.identifier
-
-			if lexerPeek(state) == tokCOLLATE {
-				lexerNext(state)
-
-				token := lexerNext(state)
-				if token != tokIDENTIFIER {
-					fmt.Println("parseTableConstraint error")
-					return nil
-				}
-				column.CollateName = state.identifier
-			}
-
-			if parseOptionalOrder(state, &column.Order) != ERROR_NONE {
-				fmt.Println("parseTableConstraint error")
+		for {
+			column, errCode := parseIndexedColumn(state)
+			if errCode != ERROR_NONE {
 				return nil
 			}
-
 			constraint.NumIndexed++
 			constraint.IndexedColumns = append(constraint.IndexedColumns, column)
 
-			token = lexerPeek(state)
-			if token == tokCOMMA {
-				lexerNext(state)
+			if lexerPeek(state) != tokCOMMA {
+				break
 			}
+			lexerNext(state)
 		}
 		if lexerNext(state) != tokCLOSEDparenthesis {
-			fmt.Println("parseTableConstraint error")
 			return nil
 		}
 		if parseOptionalConflictClause(state, &constraint.ConflictClause) != ERROR_NONE {
-			fmt.Println("parseTableConstraint error")
 			return nil
 		}
 	} else if token == tokFOREIGN {
 		lexerNext(state)
 		if lexerNext(state) != tokKEY {
-			fmt.Println("parseTableConstraint error")
 			return nil
 		}
 		if lexerNext(state) != tokOPENparenthesis {
-			fmt.Println("parseTableConstraint error")
 			return nil
 		}
 
 		constraint.Type = TABLECONSTRAINT_FOREIGNKEY
 		//do
-		token = lexerNext(state)
+		token = lexerName(state)
 		if token != tokIDENTIFIER {
-			fmt.Println("parseTableConstraint error")
 			return nil
 		}
 		constraint.ForeignKeyNum++
@@ -859,9 +593,8 @@ func parseTableConstraint(state *State) *TableConstraint {
 		}
 		//while
 		for token == tokCOMMA {
-			token = lexerNext(state)
+			token = lexerName(state)
 			if token != tokIDENTIFIER {
-				fmt.Println("parseTableConstraint error")
 				return nil
 			}
 			constraint.ForeignKeyNum++
@@ -874,18 +607,15 @@ func parseTableConstraint(state *State) *TableConstraint {
 		}
 
 		if lexerNext(state) != tokCLOSEDparenthesis {
-			fmt.Println("parseTableConstraint error")
 			return nil
 		}
 
 		if lexerNext(state) != tokREFERENCES {
-			fmt.Println("parseTableConstraint error")
 			return nil
 		}
 
 		fk := parseForeignKeyClause(state)
 		if fk == nil {
-			fmt.Println("parseTableConstraint error")
 			return nil
 		}
 		constraint.ForeignKeyClause = fk
@@ -895,58 +625,114 @@ func parseTableConstraint(state *State) *TableConstraint {
 }
 
 func parseLiteral(state *State) ErrorCode {
-	if lexerNext(state) == tokIDENTIFIER {
+	if lexerWord(state, keywordValue) == tokIDENTIFIER {
 		return ERROR_NONE
 	} else {
 		return ERROR_SYNTAX
 	}
 }
 
-func parseColumnType(state *State, column *Column) ErrorCode {
-	offset := 0
-	for lexerPeek(state) == tokIDENTIFIER {
-		// consume identifier
-		lexerNext(state)
+// isTypeName reports whether the next token is a word of a type name: a
+// name, but not the GENERATED of GENERATED ALWAYS, which starts a generated
+// column constraint.
+func isTypeName(state *State) bool {
+	saved := state.offset
+	defer func() { state.offset = saved }()
+	if lexerWord(state, keywordID) != tokIDENTIFIER {
+		return false
+	}
+	return !isWord(state, tokIDENTIFIER, "GENERATED") || !nextIsWord(state, "ALWAYS")
+}
 
-		if offset == 0 {
-			offset = state.offset - len(state.identifier)
-		}
+// parseSignedNumber parses a number with an optional sign.
+func parseSignedNumber(state *State) (SignedNumber, ErrorCode) {
+	var number SignedNumber
+	token := lexerNext(state)
+	if token == tokPLUS || token == tokMINUS {
+		number.Sign = state.buffer[state.start : state.start+1]
+		token = lexerNext(state)
 	}
-	ptr := string(state.buffer[offset:state.offset])
+	if token != tokNUMBER {
+		return number, ERROR_SYNTAX
+	}
+	number.Value = state.identifier
+	return number, ERROR_NONE
+}
 
-	column.Type = ptr
+func parseColumnType(state *State, column *Column) ErrorCode {
+	var typeName TypeName
+	offset := -1
+	for isTypeName(state) {
+		lexerWord(state, keywordID)
+		if offset < 0 {
+			offset = state.start
+		}
+		typeName.Words = append(typeName.Words, state.identifier)
+	}
+	if offset < 0 {
+		return ERROR_NONE
+	}
 
 	if lexerPeek(state) == tokOPENparenthesis {
 		lexerNext(state)
+		start := state.offset
+		for {
+			number, errCode := parseSignedNumber(state)
+			if errCode != ERROR_NONE {
+				return errCode
+			}
+			typeName.Args = append(typeName.Args, number)
 
-		offset = state.offset
-		var c rune
-		c = next(state)
-		for c != 0x00 && c != ')' {
-			c = next(state)
-		}
-
-		if c != ')' {
-			return ERROR_SYNTAX
+			token := lexerNext(state)
+			if token == tokCLOSEDparenthesis {
+				break
+			}
+			if token != tokCOMMA || len(typeName.Args) == 2 {
+				return ERROR_SYNTAX
+			}
 		}
-
-		ptr := string(state.buffer[offset:state.offset])
-
-		column.Length = ptr
+		column.Length = state.buffer[start : state.offset-1]
 	}
 
+	typeName.Text = state.buffer[offset:state.offset]
+	column.Type = strings.Join(typeName.Words, " ")
+	column.TypeName = &typeName
 	return ERROR_NONE
 }
 
+// parseCheck parses the parenthesized expression of a CHECK constraint and
+// returns its text as written.
+func parseCheck(state *State) (string, ErrorCode) {
+	if lexerNext(state) != tokOPENparenthesis {
+		return "", ERROR_SYNTAX
+	}
+	start := state.offset
+	text := scanText(state, "")
+	if text == "" {
+		return "", ERROR_SYNTAX
+	}
+	p := newExprParser(state.buffer[start:state.offset], start)
+	if _, errCode := p.parseExpr(0); errCode != ERROR_NONE {
+		return "", errCode
+	}
+	if p.peek().Kind != TOKEN_EOF || lexerNext(state) != tokCLOSEDparenthesis {
+		return "", ERROR_SYNTAX
+	}
+	return text, ERROR_NONE
+}
+
 func parseColumnConstraints(state *State, column *Column) ErrorCode {
 	for tokenIsColumnConstraint(lexerPeek(state)) {
+		var constraint ColumnConstraint
 		token := lexerNext(state)
+		start := state.start
 
 		if token == tokCONSTRAINT {
-			token = lexerNext(state)
+			token = lexerName(state)
 			if token != tokIDENTIFIER {
 				return ERROR_SYNTAX
 			}
+			constraint.Name = state.identifier
 			column.ConstraintName = state.identifier
 			token = lexerNext(state)
 		}
@@ -957,34 +743,56 @@ func parseColumnConstraints(state *State, column *Column) ErrorCode {
 			if token != tokKEY {
 				return ERROR_SYNTAX
 			}
-			column.IsPrimaryKey = true
-			if parseOptionalOrder(state, &column.PkOrder) != ERROR_NONE {
+			constraint.Type = COLUMNCONSTRAINT_PRIMARYKEY
+			if parseOptionalOrder(state, &constraint.Order) != ERROR_NONE {
 				return ERROR_SYNTAX
 			}
-			if parseOptionalConflictClause(state, &column.PkConflictClause) != ERROR_NONE {
+			if parseOptionalConflictClause(state, &constraint.ConflictClause) != ERROR_NONE {
 				return ERROR_SYNTAX
 			}
 			if lexerPeek(state) == tokAUTOINCREMENT {
 				lexerNext(state)
-				column.IsAutoincrement = true
+				constraint.IsAutoincrement = true
 			}
+			column.IsPrimaryKey = true
+			column.PkOrder = constraint.Order
+			column.PkConflictClause = constraint.ConflictClause
+			column.IsAutoincrement = constraint.IsAutoincrement
 		case tokNOT:
 			token = lexerNext(state)
 			if token != tokNULL {
-				fmt.Println(string(state.buffer[state.offset:state.offset+4]), token)
+				return ERROR_SYNTAX
+			}
+			constraint.Type = COLUMNCONSTRAINT_NOTNULL
+			if parseOptionalConflictClause(state, &constraint.ConflictClause) != ERROR_NONE {
 				return ERROR_SYNTAX
 			}
 			column.IsNotnull = true
-			if parseOptionalConflictClause(state, &column.NotNullConflictClause) != ERROR_NONE {
+			column.NotNullConflictClause = constraint.ConflictClause
+		case tokNULL:
+			constraint.Type = COLUMNCONSTRAINT_NULL
+			if parseOptionalConflictClause(state, &constraint.ConflictClause) != ERROR_NONE {
 				return ERROR_SYNTAX
 			}
 		case tokUNIQUE:
-			column.IsUnique = true
-			if parseOptionalConflictClause(state, &column.UniqueConflictClause) != ERROR_NONE {
+			constraint.Type = COLUMNCONSTRAINT_UNIQUE
+			if parseOptionalConflictClause(state, &constraint.ConflictClause) != ERROR_NONE {
 				return ERROR_SYNTAX
 			}
+			column.IsUnique = true
+			column.UniqueConflictClause = constraint.ConflictClause
 		case tokCHECK:
-			return ERROR_UNSUPPORTEDSQL
+			expr, errCode := parseCheck(state)
+			if errCode != ERROR_NONE {
+				return errCode
+			}
+			constraint.Type = COLUMNCONSTRAINT_CHECK
+			constraint.Expr = expr
+			if column.CheckExpr != "" {
+				column.CheckExpr = "(" + column.CheckExpr + ") AND (" + expr + ")"
+			} else {
+				column.CheckExpr = expr
+			}
 		case tokDEFAULT:
 			if lexerPeek(state) == tokOPENparenthesis {
 				return ERROR_UNSUPPORTEDSQL
@@ -992,22 +800,32 @@ func parseColumnConstraints(state *State, column *Column) ErrorCode {
 			if parseLiteral(state) != ERROR_NONE {
 				return ERROR_SYNTAX
 			}
+			constraint.Type = COLUMNCONSTRAINT_DEFAULT
+			constraint.Expr = state.identifier
 			column.DefaultExpr = state.identifier
 		case tokCOLLATE:
-			token = lexerNext(state)
+			token = lexerWord(state, keywordID)
 			if token != tokIDENTIFIER {
 				return ERROR_SYNTAX
 			}
+			constraint.Type = COLUMNCONSTRAINT_COLLATE
+			constraint.CollateName = state.identifier
 			column.CollateName = state.identifier
 		case tokREFERENCES:
 			fk := parseForeignKeyClause(state)
 			if fk == nil {
 				return ERROR_SYNTAX
 			}
+			constraint.Type = COLUMNCONSTRAINT_FOREIGNKEY
+			constraint.ForeignKeyClause = fk
 			column.ForeignKeyClause = fk
 		default:
 			return ERROR_SYNTAX
 		}
+
+		constraint.Start = start
+		constraint.End = state.offset
+		column.Constraints = append(column.Constraints, constraint)
 	}
 	return ERROR_NONE
 }
@@ -1015,25 +833,22 @@ func parseColumnConstraints(state *State, column *Column) ErrorCode {
 func parseColumn(state *State) *Column {
 	var column Column
 
-	token := lexerNext(state)
+	token := lexerName(state)
 
 	if token != tokIDENTIFIER {
-		fmt.Println("parseColumn error")
 		return nil
 	}
 
 	column.Name = state.identifier
 
-	if lexerPeek(state) == tokIDENTIFIER {
+	if isTypeName(state) {
 		if parseColumnType(state, &column) != ERROR_NONE {
-			fmt.Println("parseColumn error")
 			return nil
 		}
 	}
 
 	if tokenIsColumnConstraint(lexerPeek(state)) {
 		if parseColumnConstraints(state, &column) != ERROR_NONE {
-			fmt.Println("parseColumn error")
 			return nil
 		}
 	}
@@ -1044,6 +859,9 @@ func parseColumn(state *State) *Column {
 func parse(state *State) ErrorCode {
 	token := lexerNext(state)
 
+	if token == tokEOF {
+		return ERROR_SYNTAX
+	}
 	if token != tokCREATE {
 		return ERROR_UNSUPPORTEDSQL
 	}
@@ -1073,17 +891,15 @@ func parse(state *State) ErrorCode {
 		table.IsIfNotExists = true
 	}
 
-	if lexerNext(state) != tokIDENTIFIER {
-		return ERROR_SYNTAX
+	var errCode ErrorCode
+	if table.Schema, table.Name, errCode = parseQualifiedName(state); errCode != ERROR_NONE {
+		return errCode
 	}
-
-	identifier := state.identifier
-	if identifier == "" {
+	// SQLite cannot read back a table named IF, even after a schema name.
+	if isWord(state, tokIDENTIFIER, "IF") {
 		return ERROR_SYNTAX
 	}
 
-	table.Name = state.identifier
-
 	if lexerPeek(state) == tokAS {
 		return ERROR_UNSUPPORTEDSQL
 	}
@@ -1095,7 +911,7 @@ func parse(state *State) ErrorCode {
 
 	// parse column def
 	for {
-		token = lexerPeek(state)
+		token = lexerPeekWord(state, keywordName)
 
 		if token != tokIDENTIFIER {
 			return ERROR_SYNTAX
@@ -1162,39 +978,50 @@ func parse(state *State) ErrorCode {
 		return ERROR_SYNTAX
 	}
 
-	if lexerPeek(state) == tokWITHOUT {
-		lexerNext(state)
-
-		if lexerNext(state) != tokROWID {
-			return ERROR_SYNTAX
+	// table-options are WITHOUT ROWID and STRICT, separated by commas.
+	token = lexerNext(state)
+	if token == tokWITHOUT || isWord(state, token, "STRICT") {
+		for {
+			if token == tokWITHOUT {
+				if lexerNext(state) != tokROWID {
+					return ERROR_SYNTAX
+				}
+				table.IsWithoutRowid = true
+			} else if isWord(state, token, "STRICT") {
+				table.IsStrict = true
+			} else {
+				return ERROR_SYNTAX
+			}
+			if token = lexerNext(state); token != tokCOMMA {
+				break
+			}
+			token = lexerNext(state)
 		}
-
-		table.IsWithoutRowid = true
 	}
-
-	token = lexerPeek(state)
 	if token == tokSEMICOLON {
-		lexerNext(state)
+		token = lexerNext(state)
+	}
+	if token != tokEOF {
+		return ERROR_SYNTAX
 	}
 	return ERROR_NONE
 }
 
+// ParseTable parses the CREATE TABLE statement in the first length bytes of
+// sql, or in all of sql when length is 0. It never panics: malformed or
+// truncated input of any kind returns an ErrorCode.
 func ParseTable(sql string, length int) (*Table, ErrorCode) {
 	if sql == "" {
-		return nil, ERROR_NONE
+		return nil, ERROR_SYNTAX
 	}
-	if length == 0 {
+	if length <= 0 || length > len(sql) {
 		length = len(sql)
 	}
-	if length == 0 {
-		return nil, ERROR_NONE
-	}
 
 	var table Table
 
 	state := State{
-		buffer: []rune(sql),
-		size:   length,
+		buffer: sql[:length],
 		table:  &table,
 	}
 
@@ -58,3 +58,16 @@ const (
 	TABLECONSTRAINT_CHECK
 	TABLECONSTRAINT_FOREIGNKEY
 )
+
+type ColumnConstraintType int
+
+const (
+	COLUMNCONSTRAINT_PRIMARYKEY ColumnConstraintType = iota
+	COLUMNCONSTRAINT_NOTNULL
+	COLUMNCONSTRAINT_NULL
+	COLUMNCONSTRAINT_UNIQUE
+	COLUMNCONSTRAINT_CHECK
+	COLUMNCONSTRAINT_DEFAULT
+	COLUMNCONSTRAINT_COLLATE
+	COLUMNCONSTRAINT_FOREIGNKEY
+)
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -40,3 +41,286 @@ func TestParserQouteIdentifier(t *testing.T) {
 	assert.Equal(t, errCode, ERROR_NONE, "Parsing should work")
 	assert.Len(t, table.Constraints, 2, "Should have 3 constraints")
 }
+
+func TestParserComments(t *testing.T) {
+	const ddl = `
+	-- contacts of a user
+	CREATE TABLE contacts ( /* the key */
+	 id integer primary key, -- rowid alias
+	 name text /* display name */
+	)`
+
+	table, errCode := ParseTable(ddl, 0)
+	assert.Equal(t, errCode, ERROR_NONE, "Parsing should work")
+	assert.Len(t, table.Columns, 2, "Should have 2 columns")
+}
+
+func TestParserColumnLength(t *testing.T) {
+	table, errCode := ParseTable("CREATE TABLE t (name varchar(30), price decimal(8, 2))", 0)
+	assert.Equal(t, errCode, ERROR_NONE, "Parsing should work")
+	assert.Equal(t, "30", table.Columns[0].Length)
+	assert.Equal(t, "8, 2", table.Columns[1].Length)
+}
+
+func TestParserTypeName(t *testing.T) {
+	const ddl = `
+	CREATE TABLE measures (
+	 a DOUBLE   PRECISION,
+	 b VARCHAR(255),
+	 c DECIMAL(10, 2),
+	 d NUMERIC( -5,+3 ),
+	 e "my type" [long] (0x10, 1.5e3),
+	 f
+	)`
+
+	table, errCode := ParseTable(ddl, 0)
+	if !assert.Equal(t, ERROR_NONE, errCode) {
+		return
+	}
+	columns := table.Columns
+
+	assert.Equal(t, "DOUBLE PRECISION", columns[0].Type)
+	assert.Equal(t, &TypeName{Words: []string{"DOUBLE", "PRECISION"}, Text: "DOUBLE   PRECISION"}, columns[0].TypeName)
+
+	assert.Equal(t, "VARCHAR", columns[1].Type)
+	assert.Equal(t, "255", columns[1].Length)
+	assert.Equal(t, []SignedNumber{{Value: "255"}}, columns[1].TypeName.Args)
+
+	assert.Equal(t, "10, 2", columns[2].Length)
+	assert.Equal(t, "DECIMAL(10, 2)", columns[2].TypeName.String())
+
+	assert.Equal(t, []SignedNumber{{Sign: "-", Value: "5"}, {Sign: "+", Value: "3"}}, columns[3].TypeName.Args)
+	assert.Equal(t, "NUMERIC( -5,+3 )", columns[3].TypeName.Text)
+	assert.Equal(t, "NUMERIC(-5, +3)", columns[3].TypeName.String())
+
+	assert.Equal(t, "my type long", columns[4].Type)
+	assert.Equal(t, []SignedNumber{{Value: "0x10"}, {Value: "1.5e3"}}, columns[4].TypeName.Args)
+
+	assert.Equal(t, "", columns[5].Type)
+	assert.Nil(t, columns[5].TypeName)
+
+	for _, ddl := range []string{
+		"CREATE TABLE t (x VARCHAR(max))",
+		"CREATE TABLE t (x INT())",
+		"CREATE TABLE t (x INT(1, 2, 3))",
+		"CREATE TABLE t (x INT(1,))",
+		"CREATE TABLE t (x INT(- -1))",
+		"CREATE TABLE t (x INT(12ab))",
+		"CREATE TABLE t (x (1))",
+		"CREATE TABLE t (x INT(1)",
+	} {
+		_, errCode := ParseTable(ddl, 0)
+		assert.Equal(t, ERROR_SYNTAX, errCode, ddl)
+	}
+}
+
+func TestParserColumnConstraints(t *testing.T) {
+	const ddl = `CREATE TABLE t (
+	 a INTEGER CONSTRAINT pk PRIMARY KEY ASC ON CONFLICT ROLLBACK AUTOINCREMENT CONSTRAINT nn NOT NULL,
+	 b TEXT NULL ON CONFLICT IGNORE DEFAULT 'é' COLLATE nocase COLLATE rtrim,
+	 c INT UNIQUE ON CONFLICT ABORT CONSTRAINT fk REFERENCES p (id) ON DELETE CASCADE
+	)`
+
+	table, errCode := ParseTable(ddl, 0)
+	if !assert.Equal(t, ERROR_NONE, errCode) {
+		return
+	}
+
+	a := table.Columns[0]
+	if assert.Len(t, a.Constraints, 2) {
+		pk, nn := a.Constraints[0], a.Constraints[1]
+		assert.Equal(t, COLUMNCONSTRAINT_PRIMARYKEY, pk.Type)
+		assert.Equal(t, "pk", pk.Name)
+		assert.Equal(t, ORDER_ASC, pk.Order)
+		assert.Equal(t, CONFLICT_ROOLBACK, pk.ConflictClause)
+		assert.True(t, pk.IsAutoincrement)
+		assert.Equal(t, "CONSTRAINT pk PRIMARY KEY ASC ON CONFLICT ROLLBACK AUTOINCREMENT", ddl[pk.Start:pk.End])
+		assert.Equal(t, COLUMNCONSTRAINT_NOTNULL, nn.Type)
+		assert.Equal(t, "nn", nn.Name)
+		assert.Equal(t, CONFLICT_NONE, nn.ConflictClause)
+		assert.Equal(t, "CONSTRAINT nn NOT NULL", ddl[nn.Start:nn.End])
+	}
+	// The summary keeps the last name.
+	assert.Equal(t, "nn", a.ConstraintName)
+	assert.True(t, a.IsPrimaryKey)
+	assert.True(t, a.IsNotnull)
+
+	b := table.Columns[1]
+	var types []ColumnConstraintType
+	var texts []string
+	for _, constraint := range b.Constraints {
+		types = append(types, constraint.Type)
+		texts = append(texts, ddl[constraint.Start:constraint.End])
+	}
+	assert.Equal(t, []ColumnConstraintType{
+		COLUMNCONSTRAINT_NULL, COLUMNCONSTRAINT_DEFAULT, COLUMNCONSTRAINT_COLLATE, COLUMNCONSTRAINT_COLLATE,
+	}, types)
+	assert.Equal(t, []string{"NULL ON CONFLICT IGNORE", "DEFAULT 'é'", "COLLATE nocase", "COLLATE rtrim"}, texts)
+	assert.Equal(t, CONFLICT_IGNORE, b.Constraints[0].ConflictClause)
+	assert.Equal(t, "é", b.Constraints[1].Expr)
+	assert.Equal(t, "nocase", b.Constraints[2].CollateName)
+	assert.Equal(t, "rtrim", b.CollateName)
+	assert.False(t, b.IsNotnull)
+
+	c := table.Columns[2]
+	if assert.Len(t, c.Constraints, 2) {
+		assert.Equal(t, CONFLICT_ABORT, c.Constraints[0].ConflictClause)
+		assert.Equal(t, "fk", c.Constraints[1].Name)
+		assert.Equal(t, "p", c.Constraints[1].ForeignKeyClause.Table)
+		assert.Equal(t, "CONSTRAINT fk REFERENCES p (id) ON DELETE CASCADE", ddl[c.Constraints[1].Start:c.Constraints[1].End])
+	}
+}
+
+func TestTableForeignKeys(t *testing.T) {
+	table, errCode := ParseTable(`CREATE TABLE t (
+	 a INT REFERENCES p REFERENCES q (id),
+	 b INT,
+	 FOREIGN KEY (a, b) REFERENCES r (x, y)
+	)`, 0)
+	if !assert.Equal(t, ERROR_NONE, errCode) {
+		return
+	}
+	var targets []string
+	for _, fk := range table.ForeignKeys() {
+		targets = append(targets, strings.Join(fk.Columns, ",")+" -> "+fk.Clause.Table)
+	}
+	assert.Equal(t, []string{"a -> p", "a -> q", "a,b -> r"}, targets)
+
+	// Columns built without Constraints count through ForeignKeyClause.
+	table.Columns[0].Constraints = nil
+	assert.Len(t, table.ForeignKeys(), 2)
+
+	column := Column{Name: "a", ConstraintName: "pk", IsPrimaryKey: true, IsNotnull: true, ForeignKeyClause: &ForeignKey{Table: "p"}}
+	var types []ColumnConstraintType
+	for _, constraint := range column.ConstraintList() {
+		types = append(types, constraint.Type)
+	}
+	assert.Equal(t, []ColumnConstraintType{COLUMNCONSTRAINT_PRIMARYKEY, COLUMNCONSTRAINT_NOTNULL, COLUMNCONSTRAINT_FOREIGNKEY}, types)
+	assert.Equal(t, "pk", column.ConstraintList()[0].Name)
+}
+
+func TestParserIndexedColumnExpressions(t *testing.T) {
+	const ddl = `CREATE TABLE t (
+	 a TEXT, b TEXT,
+	 UNIQUE (lower(a) COLLATE nocase DESC, (b)),
+	 PRIMARY KEY ('a' COLLATE rtrim COLLATE binary, a || b)
+	)`
+
+	table, errCode := ParseTable(ddl, 0)
+	if !assert.Equal(t, ERROR_NONE, errCode) {
+		return
+	}
+
+	unique := table.Constraints[0].IndexedColumns
+	if assert.Len(t, unique, 2) {
+		assert.Equal(t, "lower(a)", unique[0].Name)
+		assert.Equal(t, "nocase", unique[0].CollateName)
+		assert.Equal(t, ORDER_DESC, unique[0].Order)
+		if assert.NotNil(t, unique[0].Expr) {
+			assert.Equal(t, EXPR_FUNCTION, unique[0].Expr.Kind)
+			assert.Equal(t, "lower(a)", ddl[unique[0].Expr.Start:unique[0].Expr.End])
+		}
+		// A parenthesized name is a column, as in SQLite.
+		assert.Equal(t, IdxColumn{Name: "b"}, unique[1])
+	}
+
+	pk := table.Constraints[1].IndexedColumns
+	if assert.Len(t, pk, 2) {
+		// So is a string, and the last COLLATE wins.
+		assert.Equal(t, IdxColumn{Name: "a", CollateName: "binary"}, pk[0])
+		assert.Equal(t, "a || b", pk[1].Name)
+		assert.Equal(t, "||", pk[1].Expr.Op)
+	}
+
+	assert.Equal(t, "UNIQUE (lower(a) COLLATE nocase DESC, b)", FormatTableConstraint(&table.Constraints[0]))
+	assert.Equal(t, "PRIMARY KEY (a COLLATE binary, a || b)", FormatTableConstraint(&table.Constraints[1]))
+
+	for _, ddl := range []string{
+		"CREATE TABLE t (a, UNIQUE ())",
+		"CREATE TABLE t (a, UNIQUE (a, ))",
+		"CREATE TABLE t (a, UNIQUE (a > ))",
+		"CREATE TABLE t (a, UNIQUE (a ASC DESC))",
+	} {
+		_, errCode := ParseTable(ddl, 0)
+		assert.Equal(t, ERROR_SYNTAX, errCode, ddl)
+	}
+}
+
+func TestParserCheckConstraints(t *testing.T) {
+	table, errCode := ParseTable(`CREATE TABLE t (
+	 a INTEGER CHECK (a > 0) CONSTRAINT small CHECK (a < 10),
+	 b TEXT CHECK (b IN ('x', 'y, z')),
+	 CONSTRAINT ordered CHECK (a < length(b)) ON CONFLICT FAIL
+	)`, 0)
+	if !assert.Equal(t, ERROR_NONE, errCode) {
+		return
+	}
+
+	a := table.Columns[0]
+	assert.Equal(t, "(a > 0) AND (a < 10)", a.CheckExpr)
+	if assert.Len(t, a.Constraints, 2) {
+		assert.Equal(t, COLUMNCONSTRAINT_CHECK, a.Constraints[1].Type)
+		assert.Equal(t, "a < 10", a.Constraints[1].Expr)
+		assert.Equal(t, "CONSTRAINT small CHECK (a < 10)", FormatColumnConstraint(&a.Constraints[1]))
+	}
+	assert.Equal(t, "b IN ('x', 'y, z')", table.Columns[1].CheckExpr)
+
+	check := table.Constraints[0]
+	assert.Equal(t, TABLECONSTRAINT_CHECK, check.Type)
+	assert.Equal(t, "a < length(b)", check.CheckExpr)
+	assert.Equal(t, "CONSTRAINT ordered CHECK (a < length(b)) ON CONFLICT FAIL", FormatTableConstraint(&check))
+
+	for _, ddl := range []string{
+		"CREATE TABLE t (a CHECK ())",
+		"CREATE TABLE t (a CHECK a > 0)",
+		"CREATE TABLE t (a CHECK (a > ))",
+		"CREATE TABLE t (a, CHECK (a, a))",
+	} {
+		_, errCode := ParseTable(ddl, 0)
+		assert.Equal(t, ERROR_SYNTAX, errCode, ddl)
+	}
+}
+
+func TestParserTableOptions(t *testing.T) {
+	for ddl, want := range map[string][2]bool{
+		"CREATE TABLE t (a) WITHOUT ROWID;":        {true, false},
+		"CREATE TABLE t (a) strict -- comment":     {false, true},
+		"CREATE TABLE t (a) STRICT, WITHOUT ROWID": {true, true},
+	} {
+		table, errCode := ParseTable(ddl, 0)
+		if assert.Equal(t, ERROR_NONE, errCode, ddl) {
+			assert.Equal(t, want, [2]bool{table.IsWithoutRowid, table.IsStrict}, ddl)
+		}
+	}
+	table, _ := ParseTable("CREATE TABLE t (a INTEGER PRIMARY KEY) STRICT, WITHOUT ROWID", 0)
+	assert.Equal(t, "CREATE TABLE t (\n  a INTEGER PRIMARY KEY\n) WITHOUT ROWID, STRICT;", Format(table))
+
+	for _, ddl := range []string{
+		"",
+		" -- nothing",
+		"CREATE TABLE t (a) garbage",
+		"CREATE TABLE t (a) WITHOUT ROWID garbage",
+		"CREATE TABLE t (a) STRICT,",
+		"CREATE TABLE t (a), STRICT",
+		"CREATE TABLE t (a); CREATE TABLE u (b)",
+		"CREATE TABLE t (a) WITHOUT",
+	} {
+		_, errCode := ParseTable(ddl, 0)
+		assert.Equal(t, ERROR_SYNTAX, errCode, ddl)
+	}
+}
+
+func TestParserQuotedNames(t *testing.T) {
+	table, errCode := ParseTable("CREATE TABLE \"my \"\"t\"\"\" (`a``b` TEXT DEFAULT 'it''s', [c d], 'e', f$g)", 0)
+	if !assert.Equal(t, ERROR_NONE, errCode) {
+		return
+	}
+	assert.Equal(t, `my "t"`, table.Name)
+	var names []string
+	for _, column := range table.Columns {
+		names = append(names, column.Name)
+	}
+	assert.Equal(t, []string{"a`b", "c d", "e", "f$g"}, names)
+	assert.Equal(t, "it's", table.Columns[0].DefaultExpr)
+	assert.Equal(t, "CREATE TABLE \"my \"\"t\"\"\" (\n  \"a`b\" TEXT DEFAULT 'it''s',\n  \"c d\",\n  e,\n  f$g\n);", Format(table))
+}
@@ -0,0 +1,100 @@
+package parser
+
+import "strings"
+
+// Column returns the column with the given name, compared case-insensitively
+// like SQLite does, or nil.
+func (t *Table) Column(name string) *Column {
+	for i := range t.Columns {
+		if strings.EqualFold(t.Columns[i].Name, name) {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+// PrimaryKey returns the names of the primary key columns in key order,
+// whether the key is declared on a column or as a table constraint, or nil
+// when the table has no primary key.
+func (t *Table) PrimaryKey() []string {
+	for _, column := range t.Columns {
+		if column.IsPrimaryKey {
+			return []string{column.Name}
+		}
+	}
+	for _, constraint := range t.Constraints {
+		if constraint.Type == TABLECONSTRAINT_PRIMARYKEY {
+			names := make([]string, 0, len(constraint.IndexedColumns))
+			for _, column := range constraint.IndexedColumns {
+				names = append(names, column.Name)
+			}
+			return names
+		}
+	}
+	return nil
+}
+
+// ConstraintList returns the constraints of the column in the order they
+// are written: Constraints when it is set, and otherwise the constraints the
+// summary fields describe, in the order FormatColumn writes them, the first
+// one carrying ConstraintName.
+func (c *Column) ConstraintList() []ColumnConstraint {
+	if c.Constraints != nil {
+		return c.Constraints
+	}
+	var list []ColumnConstraint
+	if c.IsPrimaryKey {
+		list = append(list, ColumnConstraint{Type: COLUMNCONSTRAINT_PRIMARYKEY, Order: c.PkOrder, ConflictClause: c.PkConflictClause, IsAutoincrement: c.IsAutoincrement})
+	}
+	if c.IsNotnull {
+		list = append(list, ColumnConstraint{Type: COLUMNCONSTRAINT_NOTNULL, ConflictClause: c.NotNullConflictClause})
+	}
+	if c.IsUnique {
+		list = append(list, ColumnConstraint{Type: COLUMNCONSTRAINT_UNIQUE, ConflictClause: c.UniqueConflictClause})
+	}
+	if c.CheckExpr != "" {
+		list = append(list, ColumnConstraint{Type: COLUMNCONSTRAINT_CHECK, Expr: c.CheckExpr})
+	}
+	if c.DefaultExpr != "" {
+		list = append(list, ColumnConstraint{Type: COLUMNCONSTRAINT_DEFAULT, Expr: c.DefaultExpr})
+	}
+	if c.CollateName != "" {
+		list = append(list, ColumnConstraint{Type: COLUMNCONSTRAINT_COLLATE, CollateName: c.CollateName})
+	}
+	if c.ForeignKeyClause != nil {
+		list = append(list, ColumnConstraint{Type: COLUMNCONSTRAINT_FOREIGNKEY, ForeignKeyClause: c.ForeignKeyClause})
+	}
+	if len(list) > 0 {
+		list[0].Name = c.ConstraintName
+	}
+	return list
+}
+
+// TableForeignKey is a foreign key of a table with its child columns and
+// its CONSTRAINT name, if any, whether it is declared as a column constraint
+// or as a FOREIGN KEY table constraint.
+type TableForeignKey struct {
+	Columns []string
+	Name    string
+	Clause  *ForeignKey
+}
+
+// ForeignKeys returns the foreign keys of the table, those of its columns
+// first, in the order they are written.
+func (t *Table) ForeignKeys() []TableForeignKey {
+	var fks []TableForeignKey
+	for i := range t.Columns {
+		column := &t.Columns[i]
+		for _, constraint := range column.ConstraintList() {
+			if constraint.Type == COLUMNCONSTRAINT_FOREIGNKEY && constraint.ForeignKeyClause != nil {
+				fks = append(fks, TableForeignKey{[]string{column.Name}, constraint.Name, constraint.ForeignKeyClause})
+			}
+		}
+	}
+	for _, constraint := range t.Constraints {
+		if constraint.Type == TABLECONSTRAINT_FOREIGNKEY && constraint.ForeignKeyClause != nil {
+			fks = append(fks, TableForeignKey{constraint.ForeignKeyName, constraint.Name, constraint.ForeignKeyClause})
+		}
+	}
+	return fks
+}
@@ -0,0 +1,436 @@
+-- CREATE TABLE statements from SQLite's test suite (test/*.test), which is
+-- in the public domain, used as the seed corpus of the fuzz tests. Each is
+-- preceded by the name of the test file it was taken from. Some are invalid
+-- on purpose.
+
+-- affinity3.test
+CREATE TABLE customer (id INT PRIMARY KEY);
+
+-- aggnested.test
+CREATE TABLE RRR ( rrr_id INTEGER PRIMARY KEY AUTOINCREMENT, rrr_date INTEGER NOT NULL, rrr_aaa INTEGER );
+
+-- aggnested.test
+CREATE TABLE t1 (A1 INTEGER NOT NULL,A2 INTEGER NOT NULL,A3 INTEGER NOT NULL,A4 INTEGER NOT NULL,PRIMARY KEY(A1));
+
+-- aggnested.test
+CREATE TABLE AAA ( aaa_id INTEGER PRIMARY KEY AUTOINCREMENT );
+
+-- alter.test
+CREATE TABLE [t1'x1](c UNIQUE, b PRIMARY KEY);
+
+-- alter3.test
+CREATE TABLE t2(a, b, c REFERENCES t1(c), UNIQUE(a, b));
+
+-- alter4.test
+CREATE TEMP TABLE t2(a, b, UNIQUE(a, b));
+
+-- alterdropcol2.test
+CREATE TABLE x1234(a, b, c PRIMARY KEY, CHECK(((a+5)%10)!=0)) WITHOUT ROWID;
+
+-- alterdropcol2.test
+CREATE TABLE t1(c, b, a, PRIMARY KEY(b, a)) WITHOUT ROWID;
+
+-- alterdropcol2.test
+CREATE TABLE c1(u, v, FOREIGN KEY (v) REFERENCES p1(y));
+
+-- alterqf.test
+CREATE TABLE xyz(a CHECK (a!="str"), b AS (a||"str"));
+
+-- altertab.test
+CREATE TABLE aux.c1(x INTEGER PRIMARY KEY, y REFERENCES p1(a));
+
+-- altertab3.test
+CREATE TEMPORARY TABLE Table0 ( Col0 INTEGER, PRIMARY KEY(Col0 COLLATE RTRIM), FOREIGN KEY (Col0) REFERENCES Table0 );
+
+-- altertab3.test
+CREATE TABLE t1 ( c1 integer, c2, PRIMARY KEY(c1 collate rtrim), UNIQUE(c2) );
+
+-- altertab3.test
+CREATE TABLE t1( a,b,c,d,e,f,g,h,j,jj,jjb,k,aa,bb,cc,dd,ee DEFAULT 3.14, ff DEFAULT('hiccup'),Wg NOD NULL DEFAULT(false) );
+
+-- analyze4.test
+CREATE TABLE t2( x INTEGER PRIMARY KEY, a TEXT COLLATE nocase, b TEXT COLLATE rtrim, c TEXT COLLATE binary );
+
+-- attach4.test
+CREATE TABLE IF NOT EXISTS aux.t1(a, b);
+
+-- auth.test
+CREATE TEMP TABLE t3(a PRIMARY KEY, b, c);
+
+-- auth3.test
+CREATE TEMPORARY TABLE TempTable ( key TEXT NOT NULL ON CONFLICT FAIL UNIQUE ON CONFLICT REPLACE, value TEXT NOT NULL ON CONFLICT FAIL);
+
+-- autoinc.test
+CREATE TEMP TABLE t3(a INTEGER PRIMARY KEY AUTOINCREMENT, b);
+
+-- autoindex1.test
+CREATE TABLE flock_owner( owner_rec_id INTEGER CONSTRAINT flock_owner_key PRIMARY KEY, flock_no VARCHAR(6) NOT NULL REFERENCES flock (flock_no), owner_person_id INTEGER NOT NULL REFERENCES person (person_id), owner_change_date TEXT, last_changed TEXT NOT NULL, CONSTRAINT fo_owner_date UNIQUE (flock_no, owner_change_date) );
+
+-- autoindex1.test
+CREATE TABLE labels (ROWID INTEGER PRIMARY KEY, message_id INTEGER NOT NULL, mailbox_id INTEGER NOT NULL, UNIQUE(message_id, mailbox_id));
+
+-- autoindex1.test
+CREATE TABLE addresses (ROWID INTEGER PRIMARY KEY, address COLLATE NOCASE, comment, UNIQUE(address, comment));
+
+-- autoindex5.test
+CREATE TABLE package_notes (id INTEGER NOT NULL PRIMARY KEY, bug_name TEXT NOT NULL, package TEXT NOT NULL, fixed_version TEXT CHECK (fixed_version IS NULL OR fixed_version <> ''), fixed_version_id INTEGER NOT NULL DEFAULT 0, release TEXT NOT NULL, package_kind TEXT NOT NULL DEFAULT 'unknown', urgency TEXT NOT NULL, bug_origin TEXT NOT NULL DEFAULT '');
+
+-- autoindex5.test
+CREATE TABLE albums ( id integer NOT NULL PRIMARY KEY AUTOINCREMENT, name varchar(255), artist_id integer REFERENCES artists );
+
+-- autoindex5.test
+CREATE TABLE source_packages (name TEXT NOT NULL, release TEXT NOT NULL, subrelease TEXT NOT NULL, archive TEXT NOT NULL, version TEXT NOT NULL, version_id INTEGER NOT NULL DEFAULT 0, PRIMARY KEY (name, release, subrelease, archive));
+
+-- basexx1.test
+CREATE TEMP TABLE rb( len int, b blob ) STRICT;
+
+-- capi2.test
+CREATE TABLE t2(a NOT NULL, b);
+
+-- check.test
+CREATE TABLE t2b( x INTEGER CHECK( typeof(coalesce(x,0))=='integer' ) CONSTRAINT one, y TEXT PRIMARY KEY constraint two, z INTEGER, UNIQUE(x,z) constraint three );
+
+-- check.test
+CREATE TABLE t2c( x INTEGER CONSTRAINT x_one CONSTRAINT x_two CHECK( typeof(coalesce(x,0))=='integer' ) CONSTRAINT x_two CONSTRAINT x_three, y INTEGER, z INTEGER, CONSTRAINT u_one UNIQUE(x,y,z) CONSTRAINT u_two );
+
+-- check.test
+CREATE TABLE t2( x INTEGER CONSTRAINT one CHECK( typeof(coalesce(x,0))=="integer" ), y REAL CONSTRAINT two CHECK( typeof(coalesce(y,0.1))=='real' ), z TEXT CONSTRAINT three CHECK( typeof(coalesce(z,''))=='text' ) );
+
+-- checkfault.test
+CREATE TABLE t1 (Col0 CHECK(1 COLLATE BINARY BETWEEN 1 AND 1) );
+
+-- collate1.test
+CREATE TABLE t0(c0 COLLATE RTRIM, c1 BLOB UNIQUE, PRIMARY KEY (c0, c1)) WITHOUT ROWID;
+
+-- collate3.test
+CREATE TABLE collate3t1(c1 UNIQUE);
+
+-- colmeta.test
+CREATE TABLE abc6(rowid TEXT COLLATE rtrim, oid REAL, _rowid_ BLOB);
+
+-- conflict.test
+CREATE TABLE t1( x PRIMARY KEY, UNIQUE(x,x), UNIQUE(x,x) ON CONFLICT REPLACE );
+
+-- conflict.test
+CREATE TABLE t2( a INTEGER UNIQUE ON CONFLICT IGNORE, b INTEGER UNIQUE ON CONFLICT FAIL, c INTEGER UNIQUE ON CONFLICT REPLACE, d INTEGER UNIQUE ON CONFLICT ABORT, e INTEGER UNIQUE ON CONFLICT ROLLBACK );
+
+-- conflict.test
+CREATE TABLE t1(x NOT NULL DEFAULT NULL);
+
+-- conflict2.test
+CREATE TABLE t2( a INTEGER PRIMARY KEY ON CONFLICT IGNORE, b INTEGER UNIQUE ON CONFLICT FAIL, c INTEGER UNIQUE ON CONFLICT REPLACE, d INTEGER UNIQUE ON CONFLICT ABORT, e INTEGER UNIQUE ON CONFLICT ROLLBACK ) WITHOUT rowid;
+
+-- conflict2.test
+CREATE TABLE t1( x TEXT PRIMARY KEY NOT NULL, y TEXT NOT NULL, z INTEGER ) WITHOUT ROWID;
+
+-- conflict2.test
+CREATE TABLE t1(a INTEGER PRIMARY KEY, b, c, UNIQUE(a,b)) WITHOUT rowid;
+
+-- corruptL.test
+CREATE TABLE t1( | 3952: 61 20 52 45 41 4c 20 4e 4f 54 20 4e 55 4c 4c 20 a REAL NOT NULL | 3968: 44 45 46 41 55 4c 54 28 32 35 2b 33 32 29 2c 62 DEFAULT(25+32),b | 3984: 20 46 4c 4f 41 54 2c 63 20 44 4f 55 42 4c 45 20 FLOAT,c DOUBLE | 4000: 55 4e 49 51 55 45 2c 0a 64 20 43 4c 4f 42 2c 65 UNIQUE,.d CLOB,e | 4016: 20 49 4e 54 45 47 45 52 20 50 52 49 4d 41 52 59 INTEGER PRIMARY | 4032: 20 4b 45 59 20 41 55 54 4f 49 4e 43 52 45 4d 45 KEY AUTOINCREME | 4048: 4e 54 29 23 02 06 17 37 11 01 00 69 6e 64 65 78 NT);
+
+-- corruptN.test
+CREATE TABLE | 4016: 74 31 28 61 20 55 4e 49 51 55 45 20 4f 4e 20 43 t1(a UNIQUE ON C | 4032: 4f 4e 46 4c 49 43 54 20 52 45 50 4c 41 43 45 2c ONFLICT REPLACE, | 4048: 20 62 29 23 02 06 17 37 11 01 00 69 6e 64 65 78 b);
+
+-- csv01.test
+CREATE TABLE t3(a,b,c,d) WITHOUT ROWID;
+
+-- dbstatus.test
+CREATE TABLE t1(a PRIMARY KEY, b REFERENCES t1, c UNIQUE);
+
+-- default.test
+CREATE TABLE t3( a INTEGER PRIMARY KEY AUTOINCREMENT, b INT DEFAULT 12345 UNIQUE NOT NULL CHECK( b>=0 AND b<99999 ), c VARCHAR(123,456) DEFAULT 'hello' NOT NULL ON CONFLICT REPLACE, d REAL, e FLOATING POINT(5,10) DEFAULT 4.36, f NATIONAL CHARACTER(15) COLLATE RTRIM, g LONG INTEGER DEFAULT( 3600*12 ) );
+
+-- delete2.test
+CREATE TABLE q(s string, id string, constraint pk_q primary key(id));
+
+-- distinct2.test
+CREATE TABLE t102 (i0 TEXT UNIQUE NOT NULL);
+
+-- distinctagg.test
+CREATE TABLE v1 ( v2 UNIQUE, v3 AS( TYPEOF ( NULL ) ) UNIQUE );
+
+-- e_changes.test
+CREATE TABLE c1(a, b, FOREIGN KEY(a) REFERENCES p1 ON DELETE SET NULL);
+
+-- e_changes.test
+CREATE TABLE c2(a, b, FOREIGN KEY(a) REFERENCES p1 ON DELETE SET DEFAULT);
+
+-- e_changes.test
+CREATE TABLE c4(a, b, FOREIGN KEY(a) REFERENCES p1 ON UPDATE SET NULL);
+
+-- e_createtable.test
+CREATE TABLE t1(c1 text PRIMARY KEY ASC);
+
+-- e_fkey.test
+CREATE TABLE track( trackid INTEGER, trackname TEXT, trackartist INTEGER NOT NULL, FOREIGN KEY(trackartist) REFERENCES artist(artistid) );
+
+-- e_fkey.test
+CREATE TABLE c(j REFERENCES p ON UPDATE CASCADE);
+
+-- e_insert.test
+CREATE TEMP TABLE IF NOT EXISTS tmptable(a, b);
+
+-- e_totalchanges.test
+CREATE TABLE c3(a, b, FOREIGN KEY(a) REFERENCES p1 ON UPDATE SET DEFAULT);
+
+-- e_totalchanges.test
+CREATE TABLE c2(a, b, FOREIGN KEY(a) REFERENCES p1 ON DELETE CASCADE);
+
+-- e_totalchanges.test
+CREATE TABLE c2(a, b, FOREIGN KEY(a) REFERENCES p1 ON UPDATE CASCADE);
+
+-- eqp.test
+CREATE TABLE blob( rid INTEGER PRIMARY KEY, rcvid INTEGER, size INTEGER, uuid TEXT UNIQUE NOT NULL, content BLOB, CHECK( length(uuid)>=40 AND rid>0 ) );
+
+-- fkey1.test
+CREATE TABLE t1( a INTEGER PRIMARY KEY, b INTEGER REFERENCES t1 ON DELETE CASCADE REFERENCES t2, c TEXT, FOREIGN KEY (b,c) REFERENCES t2(x,y) ON UPDATE CASCADE );
+
+-- fkey3.test
+CREATE TABLE t2(y INTEGER PRIMARY KEY REFERENCES t1 (x) ON UPDATE SET NULL);
+
+-- fkey3.test
+CREATE TABLE TestTable ( id INTEGER PRIMARY KEY, name text, source_id integer not null, parent_id integer, foreign key(source_id, parent_id) references TestTable(source_id, id) );
+
+-- fkey3.test
+CREATE TABLE t3(a, b, c, d, UNIQUE(a, b), FOREIGN KEY(c, d) REFERENCES t3(a, b) );
+
+-- fkey4.test
+CREATE TABLE t2(c REFERENCES t1 DEFERRABLE INITIALLY DEFERRED, d);
+
+-- fkey6.test
+CREATE TABLE t2(y INTEGER PRIMARY KEY, z INTEGER REFERENCES t1(x) DEFERRABLE INITIALLY DEFERRED);
+
+-- fkey6.test
+CREATE TABLE c2(x, y REFERENCES p2 ON DELETE RESTRICT ON UPDATE RESTRICT);
+
+-- fkey8.test
+CREATE TABLE c1(b REFERENCES p1 ON DELETE CASCADE, c PRIMARY KEY);
+
+-- fkey8.test
+CREATE TABLE c1(b NOT NULL REFERENCES p1 ON UPDATE SET NULL, c);
+
+-- fkey8.test
+CREATE TABLE c1(b REFERENCES p1 ON DELETE SET NULL);
+
+-- fkey_malloc.test
+CREATE TABLE t1( x PRIMARY KEY, y REFERENCES t1 ON DELETE RESTRICT ON UPDATE SET DEFAULT );
+
+-- fkey_malloc.test
+CREATE TABLE t2(x REFERENCES t1 ON UPDATE CASCADE ON DELETE CASCADE);
+
+-- fkey_malloc.test
+CREATE TABLE t2(x, y, FOREIGN KEY(x, y) REFERENCES t1(a, b) DEFERRABLE INITIALLY DEFERRED );
+
+-- fordelete.test
+CREATE TABLE t2( c INTEGER PRIMARY KEY, d INTEGER DEFAULT 1 REFERENCES t1 ON DELETE SET DEFAULT );
+
+-- fts2r.test
+CREATE TABLE t2(id INTEGER PRIMARY KEY AUTOINCREMENT, weight INTEGER UNIQUE);
+
+-- func4.test
+CREATE TABLE t1( x INTEGER CHECK(tointeger(x) IS NOT NULL) );
+
+-- fuzz-oss1.test
+CREATE TABLE doctransaction (id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,t_name TEXT NOT NULL,t_mode VARCHAR(1) DEFAULT 'U' CHECK (t_mode IN ('U', 'R')),d_date DATE NOT NULL,t_savestep VARCHAR(1) DEFAULT 'N' CHECK (t_savestep IN ('Y', 'N')),i_parent INTEGER, t_refreshviews VARCHAR(1) DEFAULT 'Y' CHECK (t_refreshviews IN ('Y', 'N')));
+
+-- fuzz-oss1.test
+CREATE TABLE parameters (id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,t_uuid_parent TEXT NOT NULL DEFAULT '',t_name TEXT NOT NULL,t_value TEXT NOT NULL DEFAULT '',b_blob BLOB,d_lastmodifdate DATE NOT NULL DEFAULT CURRENT_TIMESTAMP,i_tmp INTEGER NOT NULL DEFAULT 0);
+
+-- fuzz-oss1.test
+CREATE TABLE unitvalue(id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,rd_unit_id INTEGER NOT NULL,d_date DATE NOT NULL,f_quantity FLOAT NOT NULL CHECK (f_quantity>=0));
+
+-- fuzzer1.test
+CREATE TEMP TABLE f2_rules(ruleset DEFAULT 0, cFrom, cTo, cost);
+
+-- gencol1.test
+CREATE TABLE t1( w INT GENERATED ALWAYS AS (m*5), m INT AS (a*2) NOT NULL, a INT, x TEXT AS (typeof(c)) CHECK (x<>'blank'), b TEXT, y TEXT AS (substr(b,m/2,m/2+2)) STORED, c ANY, PRIMARY KEY(b,a) ) WITHOUT ROWID;
+
+-- gencol1.test
+CREATE TABLE t1( w INT GENERATED ALWAYS AS (a*10), a INT, x TEXT AS (typeof(c)), b TEXT, y TEXT AS (substr(b,a,a+2)) STORED, c ANY, PRIMARY KEY(a,b) ) WITHOUT ROWID;
+
+-- gencol1.test
+CREATE TABLE t1( a INTEGER PRIMARY KEY, w INT GENERATED ALWAYS AS (a*10), b TEXT, x TEXT AS (typeof(c)), y TEXT AS (substr(b,a,a+2)) STORED, c ANY );
+
+-- hook.test
+CREATE TABLE t4(a COLLATE nocase PRIMARY KEY, b) WITHOUT ROWID;
+
+-- hook2.test
+CREATE TABLE t2(a DEFAULT 4, b, c, PRIMARY KEY(b, c)) WITHOUT ROWID;
+
+-- imposter1.test
+CREATE TEMP TABLE chnglog(desc TEXT);
+
+-- in.test
+CREATE TABLE IF NOT EXISTS t1(id INTEGER PRIMARY KEY);
+
+-- index3.test
+CREATE TABLE t1(a, b, c, d, e, PRIMARY KEY('a'), UNIQUE('b' COLLATE nocase DESC));
+
+-- insert3.test
+CREATE TABLE t2( a INTEGER PRIMARY KEY, b DEFAULT 'b', c DEFAULT 'c' );
+
+-- insert4.test
+CREATE TABLE t1(a INTEGER PRIMARY KEY ON CONFLICT REPLACE, b);
+
+-- istrue.test
+CREATE TABLE t2( a INTEGER PRIMARY KEY, b BOOLEAN DEFAULT(not true), c BOOLEAN DEFAULT(not false) );
+
+-- istrue.test
+CREATE TABLE t2( a INTEGER PRIMARY KEY, b BOOLEAN CHECK(b IS TRUE), c BOOLEAN CHECK(c IS FALSE), d BOOLEAN CHECK(d IS NOT TRUE), e BOOLEAN CHECK(e IS NOT FALSE) );
+
+-- joinH.test
+CREATE TABLE t2 (c0 , c1 , c2 , UNIQUE (c0), UNIQUE (c2 DESC));
+
+-- misc1.test
+CREATE TABLE t4( abort, asc, begin, cluster, conflict, copy, delimiters, desc, end, explain, fail, ignore, key, offset, pragma, replace, temp, vacuum, view );
+
+-- notnull.test
+CREATE TABLE t1 ( a NOT NULL, b NOT NULL DEFAULT 5, c NOT NULL ON CONFLICT REPLACE DEFAULT 6, d NOT NULL ON CONFLICT IGNORE DEFAULT 7, e NOT NULL ON CONFLICT ABORT DEFAULT 8 );
+
+-- notnull.test
+CREATE TABLE t9(a PRIMARY KEY UNIQUE NOT NULL);
+
+-- null.test
+CREATE TABLE t0(c0 PRIMARY KEY DESC);
+
+-- orderby1.test
+CREATE TABLE track( tid INTEGER PRIMARY KEY, aid INTEGER NOT NULL REFERENCES album, tn INTEGER NOT NULL, name TEXT, UNIQUE(aid ASC, tn DESC) );
+
+-- orderby1.test
+CREATE TABLE track( tid INTEGER PRIMARY KEY, aid INTEGER NOT NULL REFERENCES album, tn INTEGER NOT NULL, name TEXT, UNIQUE(aid, tn) );
+
+-- orderby1.test
+CREATE TABLE track( aid INTEGER NOT NULL REFERENCES album, tn INTEGER NOT NULL, name TEXT, UNIQUE(aid, tn) );
+
+-- orderby5.test
+CREATE TABLE Records(typeID INTEGER, key TEXT COLLATE nocase, value TEXT);
+
+-- parser1.test
+CREATE TABLE t301( id INTEGER PRIMARY KEY, c1 INTEGER NOT NULL, c2 INTEGER NOT NULL, c3 BOOLEAN NOT NULL DEFAULT 0, FOREIGN KEY(c1) REFERENCES t300(id) ON DELETE CASCADE ON UPDATE RESTRICT /* no comma */ FOREIGN KEY(c2) REFERENCES t300(id) ON DELETE CASCADE ON UPDATE RESTRICT /* no comma */ UNIQUE(c1, c2) );
+
+-- parser1.test
+CREATE TABLE t1( a TEXT PRIMARY KEY, b TEXT, FOREIGN KEY(b COLLATE nocase DESC) REFERENCES t1(a COLLATE binary ASC) );
+
+-- parser1.test
+CREATE TABLE t1( a TEXT PRIMARY KEY, b TEXT, FOREIGN KEY(b ASC) REFERENCES t1(a) );
+
+-- pragma.test
+CREATE TABLE t5( a TEXT DEFAULT CURRENT_TIMESTAMP, b DEFAULT (5+3), c TEXT, d INTEGER DEFAULT NULL, e TEXT DEFAULT '', UNIQUE(b,c,d), PRIMARY KEY(e,b,c) );
+
+-- quickcheck.test
+CREATE TABLE t1( a INTEGER NOT NULL, b INTEGER NOT NULL, c AS (a+1), PRIMARY KEY(b, a) ) WITHOUT ROWID;
+
+-- quote.test
+CREATE TABLE xyz(a, b, c CHECK (c!="null") );
+
+-- reindex.test
+CREATE TABLE t0 ( c0 INTEGER PRIMARY KEY DESC, c1 UNIQUE DEFAULT NULL );
+
+-- schema5.test
+CREATE TABLE t1(a,b,c, PRIMARY KEY(a) UNIQUE (a) CONSTRAINT one);
+
+-- schema6.test
+CREATE TABLE t1(a INTEGER PRIMARY KEY ASC, b UNIQUE) WITHOUT ROWID;
+
+-- schema6.test
+CREATE TABLE t1(a INTEGER PRIMARY KEY ASC, b UNIQUE);
+
+-- select1.test
+CREATE TABLE t1 ( a INTEGER PRIMARY KEY, b AS('Y') UNIQUE );
+
+-- select3.test
+CREATE TABLE t0(c0 REAL, c1 REAL GENERATED ALWAYS AS (c0));
+
+-- select4.test
+CREATE TABLE t1(a,b,c,d,e,f,g,h,i,j,k,l,m,n,o,p,q,r,s,t,u,v,w,x,y,z, PRIMARY KEY(a,b DESC)) WITHOUT ROWID;
+
+-- select7.test
+CREATE TABLE x(id integer primary key, a TEXT NULL);
+
+-- selectC.test
+CREATE TABLE person ( org_id TEXT NOT NULL, nickname TEXT NOT NULL, license TEXT, CONSTRAINT person_pk PRIMARY KEY (org_id, nickname), CONSTRAINT person_license_uk UNIQUE (license) );
+
+-- shell6.test
+CREATE TABLE y1(a COLLATE rtrim REFERENCES x1(a));
+
+-- skipscan2.test
+CREATE TABLE peoplew( name TEXT PRIMARY KEY, role TEXT NOT NULL, height INT NOT NULL, -- in cm CHECK( role IN ('student','teacher') ) ) WITHOUT ROWID;
+
+-- skipscan2.test
+CREATE TABLE people( name TEXT PRIMARY KEY, role TEXT NOT NULL, height INT NOT NULL, -- in cm CHECK( role IN ('student','teacher') ) );
+
+-- speed4.test
+CREATE TABLE t1(rowid INTEGER PRIMARY KEY, i INTEGER, t TEXT);
+
+-- strict1.test
+CREATE TABLE IF NOT EXISTS transactions ( debit REAL, credit REAL, amount REAL GENERATED ALWAYS AS (ifnull(credit, 0.0) - ifnull(debit, 0.0)) ) STRICT;
+
+-- strict1.test
+CREATE TABLE t4( a INT AS (b*2) VIRTUAL, b INT AS (c*2) STORED, c INT PRIMARY KEY ) STRICT;
+
+-- strict1.test
+CREATE TABLE t1(a PRIMARY KEY) STRICT, WITHOUT ROWID;
+
+-- strict2.test
+CREATE TABLE t1nn( a INT NOT NULL, b INTEGER NOT NULL, c TEXT NOT NULL, d REAL NOT NULL, e BLOB NOT NULL ) STRICT;
+
+-- table.test
+CREATE TABLE IF NOT EXISTS test2(x UNIQUE, y TEXT PRIMARY KEY);
+
+-- tableopts.test
+CREATE TABLE t1(a INTEGER PRIMARY KEY AUTOINCREMENT,b) WITHOUT rowid;
+
+-- tableopts.test
+CREATE TABLE without(x INTEGER PRIMARY KEY, without TEXT);
+
+-- tkt1449.test
+CREATE TABLE ACLS(ISSUEID text(50) not null, OBJECTID text(50) not null, PARTICIPANTID text(50) not null, PERMISSIONBITS int not null, constraint PK_ACLS primary key (ISSUEID, OBJECTID, PARTICIPANTID));
+
+-- trustschema1.test
+CREATE TEMP TABLE temp1(a,b AS (f3(a+1)));
+
+-- trustschema1.test
+CREATE TEMP TABLE temp2(a, b, CHECK(f3(b)==b));
+
+-- upfrom3.test
+CREATE TABLE c1(x PRIMARY KEY, y REFERENCES p1 ON UPDATE CASCADE);
+
+-- upsert1.test
+CREATE TABLE t2(a TEXT UNIQUE, b INT DEFAULT 1);
+
+-- where4.test
+CREATE TABLE t3(x,y,UNIQUE("x",'y' ASC));
+
+-- where7.test
+CREATE TABLE t301 ( c8 INTEGER PRIMARY KEY, c6 INTEGER, c4 INTEGER, c7 INTEGER, FOREIGN KEY (c4) REFERENCES series(c4) );
+
+-- wherefault.test
+CREATE TABLE t1( a INT AS (c*11), b TEXT AS (substr(d,1,3)) STORED, c INTEGEB PRIMARI KEY, d TEXT );
+
+-- window6.test
+CREATE TABLE IF NOT EXISTS "sample" ( "id" INTEGER NOT NULL PRIMARY KEY, "counter" INTEGER NOT NULL, "value" REAL NOT NULL );
+
+-- with1.test
+CREATE TABLE org( name TEXT PRIMARY KEY, boss TEXT REFERENCES org ) WITHOUT ROWID;
+
+-- without_rowid2.test
+CREATE TABLE t1( a INT PRIMARY KEY, b INT REFERENCES t1 ON DELETE CASCADE REFERENCES t2, c TEXT, FOREIGN KEY (b,c) REFERENCES t2(x,y) ON UPDATE CASCADE ) WITHOUT rowid;
+
+-- without_rowid2.test
+CREATE TABLE t8(d, e, f, FOREIGN KEY (d, e) REFERENCES t5 ON DELETE CASCADE ON UPDATE SET NULL );
+
+-- without_rowid2.test
+CREATE TABLE t9(d, e, f, FOREIGN KEY (d, e) REFERENCES t5 ON DELETE CASCADE ON UPDATE SET DEFAULT );
+
+-- without_rowid3.test
+CREATE TABLE cd( c PRIMARY KEY REFERENCES ab ON UPDATE CASCADE ON DELETE CASCADE, d ) WITHOUT rowid;
+
+-- without_rowid3.test
+CREATE TABLE node( nodeid PRIMARY KEY, parent REFERENCES node DEFERRABLE INITIALLY DEFERRED ) WITHOUT rowid;
+
+-- without_rowid4.test
+CREATE TEMP TABLE tbl (a PRIMARY KEY, b) WITHOUT rowid;
+
+-- without_rowid5.test
+CREATE TABLE statement. # For example: CREATE TABLE IF NOT EXISTS wordcount( word TEXT PRIMARY # KEY, cnt INTEGER ) WITHOUT ROWID;
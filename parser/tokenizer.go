@@ -0,0 +1,310 @@
+package parser
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// TokenKind classifies the tokens returned by a Tokenizer.
+type TokenKind int
+
+const (
+	TOKEN_EOF TokenKind = iota
+	TOKEN_ERROR
+	TOKEN_WHITESPACE
+	TOKEN_COMMENT
+	TOKEN_KEYWORD
+	TOKEN_IDENTIFIER
+	TOKEN_STRING
+	TOKEN_NUMBER
+	TOKEN_BLOB
+	TOKEN_VARIABLE
+	TOKEN_OPERATOR
+	TOKEN_PUNCTUATION
+)
+
+// Token is one token of an SQL text. Text is the exact source text, found
+// at the byte offsets Start to End; Line and Col are 1-based, Col counting
+// runes. Message says what is wrong with a TOKEN_ERROR token.
+//
+// Unquoted words that are SQLite keywords are TOKEN_KEYWORD, even where the
+// grammar accepts them as names, and quoted names are TOKEN_IDENTIFIER.
+type Token struct {
+	Kind    TokenKind
+	Text    string
+	Start   int
+	End     int
+	Line    int
+	Col     int
+	Message string
+}
+
+// Is reports whether the token is the keyword or unquoted word, ignoring
+// case.
+func (t Token) Is(word string) bool {
+	return (t.Kind == TOKEN_KEYWORD || t.Kind == TOKEN_IDENTIFIER) && strings.EqualFold(t.Text, word)
+}
+
+// Value returns the content of a string or quoted identifier, without its
+// quotes and with doubled quotes undone, and the text of any other token.
+func (t Token) Value() string {
+	if t.Kind != TOKEN_STRING && t.Kind != TOKEN_IDENTIFIER || t.Text == "" {
+		return t.Text
+	}
+	quote := t.Text[0]
+	switch quote {
+	case '[':
+		return t.Text[1 : len(t.Text)-1]
+	case '\'', '"', '`':
+		q := string(quote)
+		return strings.ReplaceAll(t.Text[1:len(t.Text)-1], q+q, q)
+	}
+	return t.Text
+}
+
+// Tokenizer splits SQL text into tokens following the rules of SQLite's
+// tokenizer. Every byte of the input belongs to exactly one token, so the
+// texts of the tokens put together give back the input.
+//
+//	tokenizer := parser.NewTokenizer(sql)
+//	for token := tokenizer.Next(); token.Kind != parser.TOKEN_EOF; token = tokenizer.Next() {
+//		...
+//	}
+//
+// Malformed input, such as an unterminated string or a character SQLite
+// does not accept, is returned as a TOKEN_ERROR token and tokenizing goes
+// on after it.
+type Tokenizer struct {
+	sql    string
+	offset int
+	line   int
+	col    int
+}
+
+func NewTokenizer(sql string) *Tokenizer {
+	return &Tokenizer{sql: sql, line: 1, col: 1}
+}
+
+// Tokenize returns every token of sql, without the final TOKEN_EOF.
+func Tokenize(sql string) []Token {
+	var tokens []Token
+	tokenizer := NewTokenizer(sql)
+	for token := tokenizer.Next(); token.Kind != TOKEN_EOF; token = tokenizer.Next() {
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// Next returns the next token, or a TOKEN_EOF token at the end of the input
+// and on every later call.
+func (t *Tokenizer) Next() Token {
+	if t.offset >= len(t.sql) {
+		return Token{Kind: TOKEN_EOF, Start: t.offset, End: t.offset, Line: t.line, Col: t.col}
+	}
+	kind, length, message := scanToken(t.sql[t.offset:])
+	token := Token{
+		Kind:    kind,
+		Text:    t.sql[t.offset : t.offset+length],
+		Start:   t.offset,
+		End:     t.offset + length,
+		Line:    t.line,
+		Col:     t.col,
+		Message: message,
+	}
+	if kind == TOKEN_IDENTIFIER && IsKeyword(token.Text) {
+		token.Kind = TOKEN_KEYWORD
+	}
+	t.offset = token.End
+	if newlines := strings.Count(token.Text, "\n"); newlines > 0 {
+		t.line += newlines
+		t.col = 1 + utf8.RuneCountInString(token.Text[strings.LastIndexByte(token.Text, '\n')+1:])
+	} else {
+		t.col += utf8.RuneCountInString(token.Text)
+	}
+	return token
+}
+
+func isSQLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\v' || c == '\f' || c == '\r'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// isIDChar reports whether c may appear in an unquoted identifier. Like
+// SQLite, every byte of a multi-byte UTF-8 sequence is accepted.
+func isIDChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || isDigit(c) || c == '_' || c == '$' || c >= 0x80
+}
+
+// at returns the byte at i of s, or 0 past its end.
+func at(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return 0
+}
+
+// scanToken returns the kind and byte length of the token at the start of
+// s, which is not empty, and the message of an error token.
+func scanToken(s string) (TokenKind, int, string) {
+	c := s[0]
+	switch {
+	case isSQLSpace(c):
+		i := 1
+		for i < len(s) && isSQLSpace(s[i]) {
+			i++
+		}
+		return TOKEN_WHITESPACE, i, ""
+	case c == '-' && at(s, 1) == '-':
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			i = len(s)
+		}
+		return TOKEN_COMMENT, i, ""
+	case c == '/' && at(s, 1) == '*':
+		// An unterminated comment runs to the end, as in SQLite.
+		i := strings.Index(s[2:], "*/")
+		if i < 0 {
+			return TOKEN_COMMENT, len(s), ""
+		}
+		return TOKEN_COMMENT, i + 4, ""
+	case c == '(' || c == ')' || c == ',' || c == ';':
+		return TOKEN_PUNCTUATION, 1, ""
+	case c == '.' && !isDigit(at(s, 1)):
+		return TOKEN_PUNCTUATION, 1, ""
+	case c == '\'':
+		return scanQuoted(s, TOKEN_STRING, "unterminated string")
+	case c == '"' || c == '`':
+		return scanQuoted(s, TOKEN_IDENTIFIER, "unterminated quoted identifier")
+	case c == '[':
+		i := strings.IndexByte(s, ']')
+		if i < 0 {
+			return TOKEN_ERROR, len(s), "unterminated quoted identifier"
+		}
+		return TOKEN_IDENTIFIER, i + 1, ""
+	case isDigit(c) || c == '.':
+		return scanNumber(s)
+	case (c == 'x' || c == 'X') && at(s, 1) == '\'':
+		return scanBlob(s)
+	case c == '?':
+		i := 1
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+		return TOKEN_VARIABLE, i, ""
+	case c == ':' || c == '@' || c == '$' || c == '#':
+		i := 1
+		for i < len(s) && isIDChar(s[i]) {
+			i++
+		}
+		if i == 1 {
+			return TOKEN_ERROR, 1, "variable without a name"
+		}
+		return TOKEN_VARIABLE, i, ""
+	case isIDChar(c):
+		i := 1
+		for i < len(s) && isIDChar(s[i]) {
+			i++
+		}
+		return TOKEN_IDENTIFIER, i, ""
+	}
+	if length := operatorLength(s); length > 0 {
+		return TOKEN_OPERATOR, length, ""
+	}
+	_, size := utf8.DecodeRuneInString(s)
+	return TOKEN_ERROR, size, "unexpected character"
+}
+
+// scanQuoted scans a string or identifier quoted by s[0], in which the
+// quote is escaped by doubling it.
+func scanQuoted(s string, kind TokenKind, message string) (TokenKind, int, string) {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		if s[i] != quote {
+			continue
+		}
+		if at(s, i+1) != quote {
+			return kind, i + 1, ""
+		}
+		i++
+	}
+	return TOKEN_ERROR, len(s), message
+}
+
+func scanNumber(s string) (TokenKind, int, string) {
+	i := 0
+	if s[0] == '0' && (at(s, 1) == 'x' || at(s, 1) == 'X') && isHexDigit(at(s, 2)) {
+		i = 3
+		for i < len(s) && isHexDigit(s[i]) {
+			i++
+		}
+	} else {
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+		if at(s, i) == '.' {
+			i++
+			for i < len(s) && isDigit(s[i]) {
+				i++
+			}
+		}
+		if c := at(s, i); c == 'e' || c == 'E' {
+			if isDigit(at(s, i+1)) {
+				i++
+			} else if sign := at(s, i+1); (sign == '+' || sign == '-') && isDigit(at(s, i+2)) {
+				i += 2
+			}
+			for i < len(s) && isDigit(s[i]) {
+				i++
+			}
+		}
+	}
+	// SQLite rejects a number followed directly by letters, such as 12ab.
+	if i < len(s) && isIDChar(s[i]) {
+		for i < len(s) && isIDChar(s[i]) {
+			i++
+		}
+		return TOKEN_ERROR, i, "malformed number"
+	}
+	return TOKEN_NUMBER, i, ""
+}
+
+func scanBlob(s string) (TokenKind, int, string) {
+	i := 2
+	for i < len(s) && isHexDigit(s[i]) {
+		i++
+	}
+	if at(s, i) == '\'' && i%2 == 0 {
+		return TOKEN_BLOB, i + 1, ""
+	}
+	for i < len(s) && s[i] != '\'' {
+		i++
+	}
+	if i < len(s) {
+		i++
+	}
+	return TOKEN_ERROR, i, "malformed blob literal"
+}
+
+// operators lists SQLite's operators, longest first so that the first
+// match is the longest.
+var operators = []string{
+	"->>",
+	"->", "||", "<=", ">=", "==", "!=", "<>", "<<", ">>",
+	"-", "+", "*", "/", "%", "=", "<", ">", "&", "|", "~",
+}
+
+func operatorLength(s string) int {
+	for _, operator := range operators {
+		if strings.HasPrefix(s, operator) {
+			return len(operator)
+		}
+	}
+	return 0
+}
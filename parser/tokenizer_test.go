@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// kinds returns the kind and text of every token that is not whitespace.
+func kinds(sql string) [][2]string {
+	var result [][2]string
+	for _, token := range Tokenize(sql) {
+		if token.Kind != TOKEN_WHITESPACE {
+			result = append(result, [2]string{token.Kind.String(), token.Text})
+		}
+	}
+	return result
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, [][2]string{
+		{"KEYWORD", "CREATE"},
+		{"KEYWORD", "table"},
+		{"IDENTIFIER", "main"},
+		{"PUNCTUATION", "."},
+		{"IDENTIFIER", `"my ""t"""`},
+		{"PUNCTUATION", "("},
+		{"IDENTIFIER", "[a b]"},
+		{"IDENTIFIER", "`c`"},
+		{"KEYWORD", "DEFAULT"},
+		{"STRING", "'it''s'"},
+		{"PUNCTUATION", ","},
+		{"COMMENT", "-- note"},
+		{"IDENTIFIER", "d"},
+		{"KEYWORD", "CHECK"},
+		{"PUNCTUATION", "("},
+		{"IDENTIFIER", "d"},
+		{"OPERATOR", ">="},
+		{"NUMBER", "1.5e-3"},
+		{"OPERATOR", "||"},
+		{"BLOB", "x'0aFF'"},
+		{"OPERATOR", "->>"},
+		{"VARIABLE", ":name"},
+		{"OPERATOR", "<>"},
+		{"NUMBER", "0x1F"},
+		{"OPERATOR", "+"},
+		{"NUMBER", ".5"},
+		{"OPERATOR", "!="},
+		{"VARIABLE", "?2"},
+		{"PUNCTUATION", ")"},
+		{"COMMENT", "/* done */"},
+		{"PUNCTUATION", ")"},
+		{"PUNCTUATION", ";"},
+	}, kinds("CREATE table main.\"my \"\"t\"\"\" ([a b] `c` DEFAULT 'it''s', -- note\n"+
+		"d CHECK (d >= 1.5e-3 || x'0aFF' ->> :name <> 0x1F + .5 != ?2) /* done */);"))
+}
+
+func TestTokenizePositions(t *testing.T) {
+	const sql = "SELECT 'é',\r\n  x -- end"
+	tokens := Tokenize(sql)
+
+	var text string
+	for _, token := range tokens {
+		assert.Equal(t, token.Text, sql[token.Start:token.End])
+		text += token.Text
+	}
+	assert.Equal(t, sql, text)
+
+	x := tokens[5]
+	assert.Equal(t, "x", x.Text)
+	assert.Equal(t, 2, x.Line)
+	assert.Equal(t, 3, x.Col)
+	comma := tokens[3]
+	assert.Equal(t, ",", comma.Text)
+	assert.Equal(t, 1, comma.Line)
+	assert.Equal(t, 11, comma.Col)
+
+	tokenizer := NewTokenizer(sql)
+	for tokenizer.Next().Kind != TOKEN_EOF {
+	}
+	eof := tokenizer.Next()
+	assert.Equal(t, TOKEN_EOF, eof.Kind)
+	assert.Equal(t, len(sql), eof.Start)
+	assert.Equal(t, 2, eof.Line)
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	for sql, message := range map[string]string{
+		"'abc":   "unterminated string",
+		`"abc`:   "unterminated quoted identifier",
+		"[abc":   "unterminated quoted identifier",
+		"12ab":   "malformed number",
+		"x'abc'": "malformed blob literal",
+		"x'zz'":  "malformed blob literal",
+		"!":      "unexpected character",
+		"^":      "unexpected character",
+		":":      "variable without a name",
+	} {
+		tokens := Tokenize(sql)
+		if assert.Len(t, tokens, 1, sql) {
+			assert.Equal(t, TOKEN_ERROR, tokens[0].Kind, sql)
+			assert.Equal(t, sql, tokens[0].Text, sql)
+			assert.Equal(t, message, tokens[0].Message, sql)
+		}
+	}
+
+	// Tokenizing goes on after an error, which carries its position.
+	tokens := Tokenize("a ^ b")
+	if assert.Len(t, tokens, 5) {
+		assert.Equal(t, TOKEN_ERROR, tokens[2].Kind)
+		assert.Equal(t, 2, tokens[2].Start)
+		assert.Equal(t, 3, tokens[2].Col)
+		assert.Equal(t, TOKEN_IDENTIFIER, tokens[4].Kind)
+	}
+
+	// Like SQLite, an unterminated block comment is not an error.
+	assert.Equal(t, [][2]string{{"COMMENT", "/* open"}}, kinds("/* open"))
+}
+
+func TestTokenValue(t *testing.T) {
+	tokens := Tokenize(`'it''s' "a""b" [c d] ` + "`e``f`" + ` g`)
+	var values []string
+	for _, token := range tokens {
+		if token.Kind != TOKEN_WHITESPACE {
+			values = append(values, token.Value())
+		}
+	}
+	assert.Equal(t, []string{"it's", `a"b`, "c d", "e`f", "g"}, values)
+	assert.True(t, tokens[len(tokens)-1].Is("G"))
+	assert.False(t, tokens[0].Is("it's"))
+}
+
+func TestTokenizeKeywords(t *testing.T) {
+	for _, word := range []string{"WINDOW", "returning", "Materialized", "NULLS"} {
+		tokens := Tokenize(word)
+		assert.Equal(t, TOKEN_KEYWORD, tokens[0].Kind, word)
+	}
+	assert.Equal(t, TOKEN_IDENTIFIER, Tokenize("rowid")[0].Kind)
+}
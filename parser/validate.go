@@ -0,0 +1,163 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Diagnostic describes a single reason SQLite would refuse to create a table.
+// Message carries the exact error text SQLite reports for the same input.
+type Diagnostic struct {
+	Table   string
+	Column  string
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return d.Message
+}
+
+// Validate checks a parsed table against the semantic rules SQLite applies
+// in CREATE TABLE after the statement has been parsed successfully. Unlike
+// SQLite, which stops at the first problem, every violation is reported.
+func Validate(table *Table) []Diagnostic {
+	if table == nil {
+		return nil
+	}
+
+	var diags []Diagnostic
+	report := func(column, format string, args ...interface{}) {
+		diags = append(diags, Diagnostic{
+			Table:   table.Name,
+			Column:  column,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	seen := make(map[string]bool, len(table.Columns))
+	for _, column := range table.Columns {
+		name := strings.ToLower(column.Name)
+		if seen[name] {
+			report(column.Name, "duplicate column name: %s", column.Name)
+		}
+		seen[name] = true
+	}
+
+	// A constraint written twice on a column counts twice, as in SQLite.
+	numPrimaryKeys := 0
+	for i := range table.Columns {
+		column := &table.Columns[i]
+		for _, constraint := range column.ConstraintList() {
+			switch constraint.Type {
+			case COLUMNCONSTRAINT_PRIMARYKEY:
+				numPrimaryKeys++
+				if numPrimaryKeys > 1 {
+					report(column.Name, "table \"%s\" has more than one primary key", table.Name)
+				}
+				if constraint.IsAutoincrement {
+					if !strings.EqualFold(column.Type, "INTEGER") || constraint.Order == ORDER_DESC {
+						report(column.Name, "AUTOINCREMENT is only allowed on an INTEGER PRIMARY KEY")
+					} else if table.IsWithoutRowid {
+						report(column.Name, "AUTOINCREMENT not allowed on WITHOUT ROWID tables")
+					}
+				}
+			case COLUMNCONSTRAINT_FOREIGNKEY:
+				fk := constraint.ForeignKeyClause
+				if fk != nil && len(fk.ColumnName) > 1 {
+					report(column.Name, "foreign key on %s should reference only one column of table %s", column.Name, fk.Table)
+				}
+			}
+		}
+	}
+
+	for _, constraint := range table.Constraints {
+		switch constraint.Type {
+		case TABLECONSTRAINT_PRIMARYKEY, TABLECONSTRAINT_UNIQUE:
+			if constraint.Type == TABLECONSTRAINT_PRIMARYKEY {
+				numPrimaryKeys++
+				if numPrimaryKeys > 1 {
+					report("", "table \"%s\" has more than one primary key", table.Name)
+				}
+			}
+			for _, column := range constraint.IndexedColumns {
+				if column.Expr != nil {
+					report(column.Name, "%s", indexExprError(column.Expr, seen))
+				} else if !seen[strings.ToLower(column.Name)] {
+					report(column.Name, "no such column: %s", column.Name)
+				}
+			}
+		case TABLECONSTRAINT_FOREIGNKEY:
+			for _, name := range constraint.ForeignKeyName {
+				if !seen[strings.ToLower(name)] {
+					report(name, "unknown column \"%s\" in foreign key definition", name)
+				}
+			}
+			fk := constraint.ForeignKeyClause
+			if fk != nil && len(fk.ColumnName) > 0 && len(fk.ColumnName) != len(constraint.ForeignKeyName) {
+				report("", "number of columns in foreign key does not match the number of columns in the referenced table")
+			}
+		}
+	}
+
+	if table.IsWithoutRowid && numPrimaryKeys == 0 {
+		report("", "PRIMARY KEY missing on table %s", table.Name)
+	}
+
+	return diags
+}
+
+// nonDeterministic lists the built-in functions SQLite does not allow in
+// index expressions, which include CURRENT_DATE, CURRENT_TIME and
+// CURRENT_TIMESTAMP.
+var nonDeterministic = map[string]bool{
+	"changes": true, "last_insert_rowid": true, "random": true, "randomblob": true, "total_changes": true,
+	"CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true,
+}
+
+// indexExprError returns the error SQLite reports for an expression in a
+// PRIMARY KEY or UNIQUE constraint. Like SQLite, it looks for parameters
+// first, then for the problems found resolving the expression, and then for
+// non-deterministic functions; an expression without any of them is still
+// prohibited there.
+func indexExprError(expr *Expr, columns map[string]bool) string {
+	find := func(match func(e *Expr) string) string {
+		var message string
+		expr.Walk(func(e *Expr) bool {
+			message = match(e)
+			return message == ""
+		})
+		return message
+	}
+
+	if message := find(func(e *Expr) string {
+		if e.Kind == EXPR_VARIABLE {
+			return "parameters prohibited in index expressions"
+		}
+		return ""
+	}); message != "" {
+		return message
+	}
+	if message := find(func(e *Expr) string {
+		switch {
+		case e.Kind == EXPR_COLUMN && e.Table != "":
+			return `the "." operator prohibited in index expressions`
+		case e.Kind == EXPR_COLUMN && !columns[strings.ToLower(e.Name)]:
+			return "no such column: " + e.Name
+		case e.Kind == EXPR_SUBQUERY, e.Kind == EXPR_IN && e.Select != "":
+			return "subqueries prohibited in index expressions"
+		}
+		return ""
+	}); message != "" {
+		return message
+	}
+	if message := find(func(e *Expr) string {
+		if e.Kind == EXPR_FUNCTION && nonDeterministic[strings.ToLower(e.Name)] ||
+			e.Kind == EXPR_LITERAL && nonDeterministic[e.Value] {
+			return "non-deterministic functions prohibited in index expressions"
+		}
+		return ""
+	}); message != "" {
+		return message
+	}
+	return "expressions prohibited in PRIMARY KEY and UNIQUE constraints"
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		ddl     string
+		message string
+	}{
+		{"CREATE TABLE t(a, a);", "duplicate column name: a"},
+		{"CREATE TABLE t(a PRIMARY KEY, b, PRIMARY KEY(b));", "table \"t\" has more than one primary key"},
+		{"CREATE TABLE t(a INTEGER PRIMARY KEY PRIMARY KEY);", "table \"t\" has more than one primary key"},
+		{"CREATE TABLE t(a INTEGER CONSTRAINT x PRIMARY KEY CONSTRAINT y PRIMARY KEY ASC);", "table \"t\" has more than one primary key"},
+		{"CREATE TABLE t(a TEXT PRIMARY KEY AUTOINCREMENT);", "AUTOINCREMENT is only allowed on an INTEGER PRIMARY KEY"},
+		{"CREATE TABLE t(a INTEGER PRIMARY KEY DESC AUTOINCREMENT);", "AUTOINCREMENT is only allowed on an INTEGER PRIMARY KEY"},
+		{"CREATE TABLE t(a INTEGER PRIMARY KEY AUTOINCREMENT) WITHOUT ROWID;", "AUTOINCREMENT not allowed on WITHOUT ROWID tables"},
+		{"CREATE TABLE t(a INT) WITHOUT ROWID;", "PRIMARY KEY missing on table t"},
+		{"CREATE TABLE t(a REFERENCES p(x, y) REFERENCES q(z));", "foreign key on a should reference only one column of table p"},
+		{"CREATE TABLE t(a REFERENCES p(x, y));", "foreign key on a should reference only one column of table p"},
+		{"CREATE TABLE t(a, FOREIGN KEY(a) REFERENCES p(x, y));", "number of columns in foreign key does not match the number of columns in the referenced table"},
+		{"CREATE TABLE t(a, FOREIGN KEY(b) REFERENCES p(x));", "unknown column \"b\" in foreign key definition"},
+		{"CREATE TABLE t(a, UNIQUE(b));", "no such column: b"},
+		{"CREATE TABLE t(a, UNIQUE(lower(a)));", "expressions prohibited in PRIMARY KEY and UNIQUE constraints"},
+		{"CREATE TABLE t(a, PRIMARY KEY(a + 1 DESC));", "expressions prohibited in PRIMARY KEY and UNIQUE constraints"},
+		{"CREATE TABLE t(a, UNIQUE(t.a));", `the "." operator prohibited in index expressions`},
+		{"CREATE TABLE t(a, UNIQUE((SELECT 1)));", "subqueries prohibited in index expressions"},
+		{"CREATE TABLE t(a, UNIQUE(?));", "parameters prohibited in index expressions"},
+		{"CREATE TABLE t(a, UNIQUE(lower(c)));", "no such column: c"},
+		{"CREATE TABLE t(a, UNIQUE(lower(c) + ?));", "parameters prohibited in index expressions"},
+		{"CREATE TABLE t(a, UNIQUE(random()));", "non-deterministic functions prohibited in index expressions"},
+		{"CREATE TABLE t(a, UNIQUE(CURRENT_TIME + c));", "no such column: c"},
+	}
+
+	for _, test := range tests {
+		table, errCode := ParseTable(test.ddl, 0)
+		assert.Equal(t, ERROR_NONE, errCode, test.ddl)
+
+		diags := Validate(table)
+		if assert.Len(t, diags, 1, test.ddl) {
+			assert.Equal(t, test.message, diags[0].Message, test.ddl)
+		}
+	}
+}
+
+func TestValidateValidTable(t *testing.T) {
+	const ddl = `
+	CREATE TABLE contact_groups (
+	 contact_id INTEGER,
+	 group_id INTEGER,
+	 PRIMARY KEY (contact_id, group_id),
+	 FOREIGN KEY (contact_id) REFERENCES contacts (contact_id)
+	) WITHOUT ROWID;
+	`
+
+	table, errCode := ParseTable(ddl, 0)
+	assert.Equal(t, ERROR_NONE, errCode)
+	assert.Empty(t, Validate(table))
+}